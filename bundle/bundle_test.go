@@ -40,6 +40,7 @@ func TestBundleWriteAndLoad(t *testing.T) {
 		"test_app.star",
 		"test.txt",
 		"a_subdirectory/hi.jpg",
+		"fonts/tiny.bdf",
 	}
 	for _, file := range filesExpected {
 		_, err := newBun.Source.Open(file)
@@ -81,6 +82,7 @@ func TestBundleWriteAndLoadWithoutRuntime(t *testing.T) {
 		"test_app.star",
 		"test.txt",
 		"a_subdirectory/hi.jpg",
+		"fonts/tiny.bdf",
 		"unused.txt",
 	}
 	for _, file := range filesExpected {
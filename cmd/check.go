@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"io/fs"
 	"os"
@@ -12,6 +13,7 @@ import (
 	"github.com/spf13/cobra"
 	"tidbyt.dev/pixlet/cmd/community"
 	"tidbyt.dev/pixlet/manifest"
+	"tidbyt.dev/pixlet/runtime"
 	"tidbyt.dev/pixlet/tools"
 )
 
@@ -20,6 +22,10 @@ var maxRenderTime = time.Duration(1 * time.Second)
 func init() {
 	CheckCmd.Flags().BoolVarP(&rflag, "recursive", "r", false, "find apps recursively")
 	CheckCmd.Flags().DurationVarP(&maxRenderTime, "max-render-time", "", maxRenderTime, "override the default max render time")
+	CheckCmd.Flags().StringVarP(&recordHTTP, "record-http", "", "", "record all HTTP requests made by the app to fixtures in this directory")
+	CheckCmd.Flags().StringVarP(&replayHTTP, "replay-http", "", "", "serve HTTP requests made by the app only from fixtures in this directory")
+	CheckCmd.MarkFlagsMutuallyExclusive("record-http", "replay-http")
+	addLimitFlags(CheckCmd)
 }
 
 var CheckCmd = &cobra.Command{
@@ -108,6 +114,12 @@ func checkCmd(cmd *cobra.Command, args []string) error {
 		silenceOutput = true
 		output = f.Name()
 		err = render(cmd, []string{path})
+		var limitErr *runtime.LimitError
+		if errors.As(err, &limitErr) {
+			foundIssue = true
+			failure(path, fmt.Errorf("app failed to render: %w", err), fmt.Sprintf("try reducing the %s your app uses", limitErr.Limit))
+			continue
+		}
 		if err != nil {
 			foundIssue = true
 			failure(path, fmt.Errorf("app failed to render: %w", err), "try `pixlet render` and resolve any runtime issues")
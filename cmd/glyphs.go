@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/image/font"
+
+	pixletrender "tidbyt.dev/pixlet/render"
+	"tidbyt.dev/pixlet/runtime"
+	"tidbyt.dev/pixlet/tools"
+)
+
+var (
+	glyphsText  string
+	glyphsFonts []string
+)
+
+func init() {
+	GlyphsCmd.Flags().StringVarP(&glyphsText, "text", "", "", "Check this string instead of the app's output")
+	GlyphsCmd.Flags().StringSliceVarP(&glyphsFonts, "font", "f", nil, "Check --text against these fonts only, in order")
+}
+
+var GlyphsCmd = &cobra.Command{
+	Use:   "glyphs [path] [<key>=value>]...",
+	Short: "Report characters that no font can draw",
+	Example: `  pixlet glyphs examples/clock timezone=Asia/Tokyo
+  pixlet glyphs --text "Beyoncé 東京"
+  pixlet glyphs --text "東京" --font tb-8 --font fonts/cjk.bdf examples/clock`,
+	Args: cobra.ArbitraryArgs,
+	RunE: glyphs,
+	Long: `Report characters that no font can draw.
+
+Given the path to an app, the app is rendered with the provided config
+parameters, and every text widget in its output is checked against its
+font and fallback fonts.
+
+With --text, the string is checked against every built-in font and
+every font that ships with the app at path, if any. Use --font to
+check against specific fonts instead.
+
+Exits with an error if any character can't be drawn.`,
+}
+
+func glyphs(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 && glyphsText == "" {
+		return fmt.Errorf("either an app or --text is required")
+	}
+	if len(glyphsFonts) > 0 && glyphsText == "" {
+		return fmt.Errorf("--font can only be used with --text")
+	}
+
+	var applet *runtime.Applet
+	if len(args) > 0 {
+		var err error
+		applet, err = loadAppletForGlyphs(args[0])
+		if err != nil {
+			return err
+		}
+	}
+
+	if glyphsText != "" {
+		if len(args) > 1 {
+			return fmt.Errorf("config parameters can't be used with --text")
+		}
+		return glyphsInText(applet)
+	}
+
+	return glyphsInApplet(applet, args[1:])
+}
+
+func loadAppletForGlyphs(path string) (*runtime.Applet, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+
+	var fsys fs.FS
+	if info.IsDir() {
+		fsys = os.DirFS(path)
+	} else {
+		if !strings.HasSuffix(path, ".star") {
+			return nil, fmt.Errorf("script file must have suffix .star: %s", path)
+		}
+		fsys = tools.NewSingleFileFS(path)
+	}
+
+	applet, err := runtime.NewAppletFromFS(filepath.Base(path), fsys, runtime.WithPrintDisabled())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load applet: %w", err)
+	}
+
+	return applet, nil
+}
+
+func glyphsInText(applet *runtime.Applet) error {
+	var src pixletrender.FontSource
+	names := glyphsFonts
+	if applet != nil {
+		src = applet.Fonts()
+	}
+
+	if len(names) == 0 {
+		names = pixletrender.GetFontList()
+		sort.Strings(names)
+		if applet != nil {
+			names = append(names, applet.FontNames()...)
+		}
+	}
+
+	faces := make([]font.Face, 0, len(names))
+	for _, name := range names {
+		face, err := pixletrender.LookupFont(src, name)
+		if err != nil {
+			return err
+		}
+		faces = append(faces, face)
+	}
+
+	missing := pixletrender.MissingGlyphs(glyphsText, faces...)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, r := range missing {
+		fmt.Fprintf(w, "%U\t%c\n", r, r)
+	}
+	w.Flush()
+
+	return glyphsResult(len(missing))
+}
+
+func glyphsInApplet(applet *runtime.Applet, args []string) error {
+	config := map[string]string{}
+	for _, param := range args {
+		split := strings.Split(param, "=")
+		if len(split) < 2 {
+			return fmt.Errorf("parameters must be on form <key>=<value>, found %s", param)
+		}
+		config[split[0]] = strings.Join(split[1:], "=")
+	}
+
+	cache, err := newCache()
+	if err != nil {
+		return fmt.Errorf("failed to initialize cache: %w", err)
+	}
+	if err := initHTTP(cache); err != nil {
+		return fmt.Errorf("failed to initialize HTTP: %w", err)
+	}
+	runtime.InitCache(cache)
+
+	roots, err := applet.RunWithConfig(context.Background(), config)
+	if err != nil {
+		return fmt.Errorf("error running script: %w", err)
+	}
+
+	type textWidget interface {
+		MissingGlyphs() []rune
+	}
+
+	count := 0
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, root := range roots {
+		pixletrender.Walk(root.Child, func(widget pixletrender.Widget) {
+			text, ok := widget.(textWidget)
+			if !ok {
+				return
+			}
+
+			for _, r := range text.MissingGlyphs() {
+				fmt.Fprintf(w, "%U\t%c\t%s\n", r, r, describeTextWidget(widget))
+				count++
+			}
+		})
+	}
+	w.Flush()
+
+	return glyphsResult(count)
+}
+
+func describeTextWidget(widget pixletrender.Widget) string {
+	switch w := widget.(type) {
+	case *pixletrender.Text:
+		return fmt.Sprintf("Text(%q, font = %q)", w.Content, w.Font)
+	case *pixletrender.WrappedText:
+		return fmt.Sprintf("WrappedText(%q, font = %q)", w.Content, w.Font)
+	case *pixletrender.RichText:
+		content := ""
+		for _, span := range w.Spans {
+			content += span.Content
+		}
+		return fmt.Sprintf("RichText(%q, font = %q)", content, w.Font)
+	default:
+		return fmt.Sprintf("%T", widget)
+	}
+}
+
+func glyphsResult(missing int) error {
+	if missing > 0 {
+		return fmt.Errorf("found %d characters that can't be drawn", missing)
+	}
+
+	fmt.Println("All characters can be drawn.")
+	return nil
+}
@@ -26,6 +26,8 @@ func init() {
 	ProfileCmd.Flags().StringVarP(
 		&pprof_cmd, "pprof", "", "top 10", "Command to call pprof with",
 	)
+	ProfileCmd.Flags().StringVarP(&cacheDir, "cache-dir", "", "", "Persist the cache in this directory instead of in memory")
+	ProfileCmd.Flags().IntVarP(&cacheMaxSize, "cache-max-size", "", 100, "Maximum size of the on-disk cache (MB)")
 }
 
 var ProfileCmd = &cobra.Command{
@@ -111,8 +113,13 @@ func ProfileApp(path string, config map[string]string) (*pprof_profile.Profile,
 		fsys = tools.NewSingleFileFS(path)
 	}
 
-	cache := runtime.NewInMemoryCache()
-	runtime.InitHTTP(cache)
+	cache, err := newCache()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize cache: %w", err)
+	}
+	if err := initHTTP(cache); err != nil {
+		return nil, fmt.Errorf("failed to initialize HTTP: %w", err)
+	}
 	runtime.InitCache(cache)
 
 	applet, err := runtime.NewAppletFromFS(path, fsys, runtime.WithPrintDisabled())
@@ -1,9 +1,13 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"image"
+	"image/png"
 	"io/fs"
 	"os"
 	"path/filepath"
@@ -14,6 +18,8 @@ import (
 
 	"tidbyt.dev/pixlet/encode"
 	"tidbyt.dev/pixlet/globals"
+	pixletrender "tidbyt.dev/pixlet/render"
+	"tidbyt.dev/pixlet/render/tree"
 	"tidbyt.dev/pixlet/runtime"
 	"tidbyt.dev/pixlet/tools"
 )
@@ -22,16 +28,35 @@ var (
 	output        string
 	magnify       int
 	renderGif     bool
+	renderFormat  string
+	sheetColumns  int
 	maxDuration   int
 	silenceOutput bool
 	width         int
 	height        int
 	timeout       int
+	cacheDir      string
+	cacheMaxSize  int
+	recordHTTP    string
+	replayHTTP    string
+	reportFormat  string
+	filterSpecs   []string
+	emitTree      bool
+	layoutDebug   bool
+	layoutFrame   int
+
+	maxSteps        uint64
+	maxHTTPRequests int
+	maxHTTPBytes    int64
+	maxFrames       int
+	maxPixels       int64
 )
 
 func init() {
 	RenderCmd.Flags().StringVarP(&output, "output", "o", "", "Path for rendered image")
-	RenderCmd.Flags().BoolVarP(&renderGif, "gif", "", false, "Generate GIF instead of WebP")
+	RenderCmd.Flags().BoolVarP(&renderGif, "gif", "", false, "Generate GIF instead of WebP (same as --format gif)")
+	RenderCmd.Flags().StringVarP(&renderFormat, "format", "", "webp", "Output format (webp, gif, apng, rgb565, rgb888, png-frames or sprite-sheet)")
+	RenderCmd.Flags().IntVarP(&sheetColumns, "sheet-columns", "", 0, "Number of columns in a sprite sheet (default about as many as rows)")
 	RenderCmd.Flags().BoolVarP(&silenceOutput, "silent", "", false, "Silence print statements when rendering app")
 	RenderCmd.Flags().IntVarP(
 		&magnify,
@@ -68,6 +93,39 @@ func init() {
 		30000,
 		"Timeout for execution (ms)",
 	)
+	RenderCmd.Flags().StringVarP(&cacheDir, "cache-dir", "", "", "Persist the cache in this directory instead of in memory")
+	RenderCmd.Flags().IntVarP(&cacheMaxSize, "cache-max-size", "", 100, "Maximum size of the on-disk cache (MB)")
+	RenderCmd.Flags().StringVarP(&recordHTTP, "record-http", "", "", "Record all HTTP requests made by the app to fixtures in this directory")
+	RenderCmd.Flags().StringVarP(&replayHTTP, "replay-http", "", "", "Serve HTTP requests made by the app only from fixtures in this directory")
+	RenderCmd.MarkFlagsMutuallyExclusive("record-http", "replay-http")
+	RenderCmd.Flags().StringVarP(&reportFormat, "report", "", "", "Print a report of timings, HTTP and cache calls in this format (json)")
+	RenderCmd.Flags().BoolVarP(&emitTree, "emit-tree", "", false, "Write the widget tree as JSON instead of rendering an image")
+	RenderCmd.Flags().BoolVarP(&layoutDebug, "layout-debug", "", false, "Also write the bounds of every widget as JSON, and an image outlining them")
+	RenderCmd.Flags().IntVarP(&layoutFrame, "layout-frame", "", 0, "Frame to inspect with --layout-debug")
+	RenderCmd.Flags().StringArrayVarP(&filterSpecs, "filter", "", nil, "Apply a filter to every frame, e.g. gamma:2.2, max-brightness:0.8, floyd-steinberg:5,6,5 or bayer:#000,#fff (repeatable)")
+	addLimitFlags(RenderCmd)
+}
+
+// addLimitFlags adds flags for the resource limits applied to apps. All
+// limits are off by default.
+func addLimitFlags(cmd *cobra.Command) {
+	cmd.Flags().Uint64VarP(&maxSteps, "max-steps", "", 0, "Maximum number of Starlark execution steps per call")
+	cmd.Flags().IntVarP(&maxHTTPRequests, "max-http-requests", "", 0, "Maximum number of HTTP requests per call")
+	cmd.Flags().Int64VarP(&maxHTTPBytes, "max-http-bytes", "", 0, "Maximum total size of HTTP responses per call (bytes)")
+	cmd.Flags().IntVarP(&maxFrames, "max-frames", "", 0, "Maximum number of frames rendered")
+	cmd.Flags().Int64VarP(&maxPixels, "max-pixels", "", 0, "Maximum total pixel area of all frames rendered")
+}
+
+// limitOptions returns the applet options for the limits set with the flags
+// from addLimitFlags.
+func limitOptions() []runtime.AppletOption {
+	return []runtime.AppletOption{
+		runtime.WithMaxExecutionSteps(maxSteps),
+		runtime.WithMaxHTTPRequests(maxHTTPRequests),
+		runtime.WithMaxHTTPBytes(maxHTTPBytes),
+		runtime.WithMaxFrames(maxFrames),
+		runtime.WithMaxPixels(maxPixels),
+	}
 }
 
 var RenderCmd = &cobra.Command{
@@ -106,14 +164,48 @@ func render(cmd *cobra.Command, args []string) error {
 		outPath = strings.TrimSuffix(path, ".star")
 	}
 
+	// layout files go next to the output, or the app if that's stdout
+	layoutPath := outPath
+	if output != "" && output != "-" {
+		layoutPath = strings.TrimSuffix(output, filepath.Ext(output))
+	}
+
+	format := renderFormat
 	if renderGif {
-		outPath += ".gif"
+		if cmd.Flags().Changed("format") && format != "gif" {
+			return fmt.Errorf("--gif conflicts with --format %s", format)
+		}
+		format = "gif"
+	}
+	ext, ok := formatExtensions[format]
+	if !ok {
+		return fmt.Errorf("unsupported output format: %s", format)
+	}
+
+	if emitTree {
+		outPath += ".json"
 	} else {
-		outPath += ".webp"
+		outPath += ext
 	}
 	if output != "" {
 		outPath = output
 	}
+	if outPath == "-" && !emitTree && (format == "png-frames" || format == "sprite-sheet") {
+		return fmt.Errorf("--format %s writes several files, and can't write to stdout", format)
+	}
+
+	if reportFormat != "" && reportFormat != "json" {
+		return fmt.Errorf("unsupported report format: %s", reportFormat)
+	}
+
+	var filters []encode.ImageFilter
+	for _, spec := range filterSpecs {
+		f, err := encode.ParseFilter(spec)
+		if err != nil {
+			return fmt.Errorf("invalid filter: %w", err)
+		}
+		filters = append(filters, f)
+	}
 
 	globals.Width = width
 	globals.Height = height
@@ -129,7 +221,7 @@ func render(cmd *cobra.Command, args []string) error {
 
 	// Remove the print function from the starlark thread if the silent flag is
 	// passed.
-	var opts []runtime.AppletOption
+	opts := limitOptions()
 	if silenceOutput {
 		opts = append(opts, runtime.WithPrintDisabled())
 	}
@@ -143,8 +235,13 @@ func render(cmd *cobra.Command, args []string) error {
 		)
 	}
 
-	cache := runtime.NewInMemoryCache()
-	runtime.InitHTTP(cache)
+	cache, err := newCache()
+	if err != nil {
+		return fmt.Errorf("failed to initialize cache: %w", err)
+	}
+	if err := initHTTP(cache); err != nil {
+		return fmt.Errorf("failed to initialize HTTP: %w", err)
+	}
 	runtime.InitCache(cache)
 
 	applet, err := runtime.NewAppletFromFS(filepath.Base(path), fs, opts...)
@@ -152,13 +249,29 @@ func render(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load applet: %w", err)
 	}
 
-	roots, err := applet.RunWithConfig(ctx, config)
+	roots, report, err := applet.RunWithReport(ctx, config)
+	var limitErr *runtime.LimitError
+	if errors.As(err, &limitErr) {
+		return fmt.Errorf("app exceeded a resource limit: %w", err)
+	}
 	if err != nil {
 		return fmt.Errorf("error running script: %w", err)
 	}
+
+	if emitTree {
+		return writeTree(outPath, roots)
+	}
+
+	if layoutDebug {
+		if err := writeLayout(layoutPath, roots, layoutFrame); err != nil {
+			return err
+		}
+	}
+
 	screens := encode.ScreensFromRoots(roots)
 
-	filter := func(input image.Image) (image.Image, error) {
+	// magnify last, so that the other filters work on actual pixels
+	filters = append(filters, func(input image.Image) (image.Image, error) {
 		if magnify <= 1 {
 			return input, nil
 		}
@@ -188,32 +301,255 @@ func render(cmd *cobra.Command, args []string) error {
 		}
 
 		return out, nil
-	}
-
-	var buf []byte
+	})
 
 	if screens.ShowFullAnimation {
 		maxDuration = 0
 	}
 
-	if renderGif {
-		buf, err = screens.EncodeGIF(maxDuration, filter)
-	} else {
-		buf, err = screens.EncodeWebP(maxDuration, filter)
+	// paint before encoding, so that the report can tell the two apart
+	start := time.Now()
+	frames, err := screens.Frames()
+	if err != nil {
+		return fmt.Errorf("error rendering: %w", err)
 	}
+	report.Paint = time.Since(start)
+	report.Frames = len(frames)
+
+	start = time.Now()
+	files, err := encodeScreens(screens, format, outPath, filters)
 	if err != nil {
 		return fmt.Errorf("error rendering: %w", err)
 	}
+	report.Encode = time.Since(start)
+	for _, f := range files {
+		report.EncodedSize += len(f.data)
+	}
+
+	for _, f := range files {
+		if f.path == "-" {
+			_, err = os.Stdout.Write(f.data)
+		} else if err = os.MkdirAll(filepath.Dir(f.path), 0755); err == nil {
+			err = os.WriteFile(f.path, f.data, 0644)
+		}
+
+		if err != nil {
+			return fmt.Errorf("writing %s: %s", f.path, err)
+		}
+	}
+
+	if reportFormat == "json" {
+		// keep the report out of the way of an image written to stdout
+		out := os.Stdout
+		if outPath == "-" {
+			out = os.Stderr
+		}
+
+		b, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("serializing report: %w", err)
+		}
+		fmt.Fprintln(out, string(b))
+	}
+
+	return nil
+}
+
+// File extensions of the formats accepted by --format. PNG frames
+// are written to a directory.
+var formatExtensions = map[string]string{
+	"webp":         ".webp",
+	"gif":          ".gif",
+	"apng":         ".png",
+	"rgb565":       ".rgb565",
+	"rgb888":       ".rgb888",
+	"png-frames":   ".frames",
+	"sprite-sheet": ".sheet.png",
+}
+
+// outputFile is a file written by pixlet render, or stdout if the
+// path is "-".
+type outputFile struct {
+	path string
+	data []byte
+}
+
+// frameTiming is how long a PNG frame is shown, listed in the
+// frames.json next to it.
+type frameTiming struct {
+	File  string `json:"file"`
+	Delay int    `json:"delay"`
+}
+
+// encodeScreens encodes screens in one of the formats of
+// formatExtensions. Most formats are a single file at outPath.
+func encodeScreens(screens *encode.Screens, format string, outPath string, filters []encode.ImageFilter) ([]outputFile, error) {
+	var buf []byte
+	var err error
+
+	switch format {
+	case "gif":
+		buf, err = screens.EncodeGIF(maxDuration, filters...)
+	case "apng":
+		buf, err = screens.EncodeAPNG(maxDuration, filters...)
+	case "rgb565":
+		buf, err = screens.EncodeRaw(maxDuration, encode.RawRGB565, filters...)
+	case "rgb888":
+		buf, err = screens.EncodeRaw(maxDuration, encode.RawRGB888, filters...)
+	case "png-frames":
+		return encodePNGFrames(screens, outPath, filters)
+	case "sprite-sheet":
+		return encodeSpriteSheet(screens, outPath, filters)
+	default:
+		buf, err = screens.EncodeWebP(maxDuration, filters...)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return []outputFile{{outPath, buf}}, nil
+}
+
+// encodePNGFrames returns a PNG for every frame, to go in the outDir
+// directory, along with a frames.json listing them with their delays.
+func encodePNGFrames(screens *encode.Screens, outDir string, filters []encode.ImageFilter) ([]outputFile, error) {
+	frames, delays, err := screens.EncodePNGFrames(maxDuration, filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	// zero pad the names, so that they sort in order
+	digits := len(fmt.Sprint(len(frames) - 1))
+	if digits < 3 {
+		digits = 3
+	}
+
+	files := []outputFile{}
+	timings := []frameTiming{}
+	for i, data := range frames {
+		name := fmt.Sprintf("frame_%0*d.png", digits, i)
+		files = append(files, outputFile{filepath.Join(outDir, name), data})
+		timings = append(timings, frameTiming{File: name, Delay: delays[i]})
+	}
+
+	index, err := json.MarshalIndent(struct {
+		Frames []frameTiming `json:"frames"`
+	}{timings}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("serializing frames: %w", err)
+	}
+	index = append(index, '\n')
+
+	return append(files, outputFile{filepath.Join(outDir, "frames.json"), index}), nil
+}
+
+// encodeSpriteSheet returns a sprite sheet of every frame at outPath,
+// and its layout at the same path with the extension .json.
+func encodeSpriteSheet(screens *encode.Screens, outPath string, filters []encode.ImageFilter) ([]outputFile, error) {
+	data, sheet, err := screens.EncodeSpriteSheet(maxDuration, sheetColumns, filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	layout, err := json.MarshalIndent(sheet, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("serializing sprite sheet: %w", err)
+	}
+	layout = append(layout, '\n')
+
+	return []outputFile{
+		{outPath, data},
+		{strings.TrimSuffix(outPath, filepath.Ext(outPath)) + ".json", layout},
+	}, nil
+}
+
+// newCache returns the cache selected by the --cache-dir flag. Without it,
+// the cache lives in memory and is lost when pixlet exits.
+func newCache() (runtime.Cache, error) {
+	if cacheDir == "" {
+		return runtime.NewInMemoryCache(), nil
+	}
+
+	return runtime.NewFileCache(cacheDir, int64(cacheMaxSize)*1024*1024)
+}
+
+// initHTTP configures the HTTP client used by apps, recording or replaying
+// requests if the --record-http or --replay-http flags are set.
+func initHTTP(cache runtime.Cache) error {
+	switch {
+	case recordHTTP != "":
+		return runtime.InitHTTPRecording(cache, recordHTTP)
+
+	case replayHTTP != "":
+		return runtime.InitHTTPReplay(replayHTTP)
+
+	default:
+		runtime.InitHTTP(cache)
+		return nil
+	}
+}
+
+// writeTree writes roots to outPath, or stdout if it's "-", in the JSON
+// format of the tree package.
+func writeTree(outPath string, roots []pixletrender.Root) error {
+	data, err := tree.Marshal(roots)
+	if err != nil {
+		return fmt.Errorf("serializing widget tree: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, "", "  "); err != nil {
+		return fmt.Errorf("serializing widget tree: %w", err)
+	}
+	buf.WriteByte('\n')
 
 	if outPath == "-" {
-		_, err = os.Stdout.Write(buf)
+		_, err = os.Stdout.Write(buf.Bytes())
 	} else {
-		err = os.WriteFile(outPath, buf, 0644)
+		err = os.WriteFile(outPath, buf.Bytes(), 0644)
 	}
-
 	if err != nil {
 		return fmt.Errorf("writing %s: %s", outPath, err)
 	}
 
 	return nil
 }
+
+// writeLayout writes the layout of a frame to layoutPath with the
+// extension .layout.json, and an overlay outlining every widget in it
+// to .layout.png. The frame index counts across all roots.
+func writeLayout(layoutPath string, roots []pixletrender.Root, frameIdx int) error {
+	layout, frame, err := pixletrender.InspectRootsLayout(roots, frameIdx)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(layout, "", "  ")
+	if err != nil {
+		return fmt.Errorf("serializing layout: %w", err)
+	}
+	data = append(data, '\n')
+
+	jsonPath := layoutPath + ".layout.json"
+	if err := os.WriteFile(jsonPath, data, 0644); err != nil {
+		return fmt.Errorf("writing %s: %s", jsonPath, err)
+	}
+
+	// outlines need room, so the overlay is always magnified a bit
+	scale := magnify
+	if scale < 4 {
+		scale = 4
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, pixletrender.LayoutOverlay(frame, layout, scale)); err != nil {
+		return fmt.Errorf("encoding layout overlay: %w", err)
+	}
+
+	pngPath := layoutPath + ".layout.png"
+	if err := os.WriteFile(pngPath, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("writing %s: %s", pngPath, err)
+	}
+
+	return nil
+}
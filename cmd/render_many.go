@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	goruntime "runtime"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+
+	"tidbyt.dev/pixlet/farm"
+	"tidbyt.dev/pixlet/globals"
+	"tidbyt.dev/pixlet/runtime"
+)
+
+var (
+	renderManyOutputDir string
+	renderManyWorkers   int
+	renderManySummary   string
+)
+
+func init() {
+	RenderManyCmd.Flags().StringVarP(&renderManyOutputDir, "output-dir", "o", ".", "Directory to write rendered images to")
+	RenderManyCmd.Flags().IntVarP(&renderManyWorkers, "workers", "", goruntime.NumCPU(), "Number of jobs to render in parallel")
+	RenderManyCmd.Flags().StringVarP(&renderManySummary, "summary", "", "", "Also write a JSON summary of all jobs to this path")
+	RenderManyCmd.Flags().BoolVarP(&renderGif, "gif", "", false, "Generate GIF instead of WebP")
+	RenderManyCmd.Flags().IntVarP(&width, "width", "w", 64, "Set width")
+	RenderManyCmd.Flags().IntVarP(&height, "height", "t", 32, "Set height")
+	RenderManyCmd.Flags().IntVarP(&maxDuration, "max_duration", "d", 15000, "Maximum allowed animation duration (ms)")
+	RenderManyCmd.Flags().IntVarP(&timeout, "timeout", "", 30000, "Timeout for execution of each job (ms)")
+	RenderManyCmd.Flags().StringVarP(&cacheDir, "cache-dir", "", "", "Persist the cache in this directory instead of in memory")
+	RenderManyCmd.Flags().IntVarP(&cacheMaxSize, "cache-max-size", "", 100, "Maximum size of the on-disk cache (MB)")
+}
+
+var RenderManyCmd = &cobra.Command{
+	Use:     "render-many [jobs file]",
+	Short:   "Render many Pixlet apps in parallel",
+	Example: `  pixlet render-many -o out/ jobs.yaml`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    renderMany,
+	Long: `Render many Pixlet apps in parallel.
+
+The jobs file lists the apps to render, and the config to render
+each of them with:
+
+  jobs:
+    - id: clock-nyc
+      path: examples/clock
+      config:
+        timezone: America/New_York
+    - id: clock-sf
+      path: examples/clock
+      config:
+        timezone: America/Los_Angeles
+
+Paths are relative to the jobs file. Jobs are rendered on a pool of
+workers, and every app is only loaded once no matter how many jobs
+use it. All jobs share the same cache. Each job's image is written to
+the output directory, named after its ID, and a summary of timings
+and errors is printed once all jobs are done.`,
+}
+
+type renderManySummaryEntry struct {
+	ID       string            `json:"id"`
+	Path     string            `json:"path"`
+	Config   map[string]string `json:"config,omitempty"`
+	Output   string            `json:"output,omitempty"`
+	Frames   int               `json:"frames"`
+	Bytes    int               `json:"bytes"`
+	LoadMs   int64             `json:"load_ms"`
+	RunMs    int64             `json:"run_ms"`
+	EncodeMs int64             `json:"encode_ms"`
+	Error    string            `json:"error,omitempty"`
+}
+
+func renderMany(cmd *cobra.Command, args []string) error {
+	jobsPath := args[0]
+
+	f, err := os.Open(jobsPath)
+	if err != nil {
+		return fmt.Errorf("failed to open jobs file: %w", err)
+	}
+	defer f.Close()
+
+	jobs, err := farm.LoadJobs(f, filepath.Dir(jobsPath))
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(renderManyOutputDir, 0755); err != nil {
+		return fmt.Errorf("creating output directory: %w", err)
+	}
+
+	globals.Width = width
+	globals.Height = height
+
+	cache, err := newCache()
+	if err != nil {
+		return fmt.Errorf("failed to initialize cache: %w", err)
+	}
+	if err := initHTTP(cache); err != nil {
+		return fmt.Errorf("failed to initialize HTTP: %w", err)
+	}
+	runtime.InitCache(cache)
+
+	opts := []farm.Option{
+		farm.WithWorkers(renderManyWorkers),
+		farm.WithMaxDuration(maxDuration),
+		farm.WithAppletOptions(runtime.WithPrintDisabled()),
+	}
+	if timeout > 0 {
+		opts = append(opts, farm.WithTimeout(time.Duration(timeout)*time.Millisecond))
+	}
+	if renderGif {
+		opts = append(opts, farm.WithGIF())
+	}
+
+	ext := ".webp"
+	if renderGif {
+		ext = ".gif"
+	}
+
+	start := time.Now()
+	results := farm.New(opts...).Render(context.Background(), jobs)
+	elapsed := time.Since(start)
+
+	var summary []renderManySummaryEntry
+	failed := 0
+	for _, r := range results {
+		entry := renderManySummaryEntry{
+			ID:       r.Job.ID,
+			Path:     r.Job.Path,
+			Config:   r.Job.Config,
+			Frames:   r.Frames,
+			Bytes:    len(r.Image),
+			LoadMs:   r.Load.Milliseconds(),
+			RunMs:    r.Run.Milliseconds(),
+			EncodeMs: r.Encode.Milliseconds(),
+		}
+
+		if r.Err == nil {
+			entry.Output = filepath.Join(renderManyOutputDir, r.Job.ID+ext)
+			if err := os.WriteFile(entry.Output, r.Image, 0644); err != nil {
+				r.Err = fmt.Errorf("writing %s: %w", entry.Output, err)
+				entry.Output = ""
+			}
+		}
+
+		if r.Err != nil {
+			failed++
+			entry.Error = r.Err.Error()
+		}
+
+		summary = append(summary, entry)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSTATUS\tFRAMES\tSIZE\tLOAD\tRUN\tENCODE")
+	for _, e := range summary {
+		status := "ok"
+		if e.Error != "" {
+			status = "error"
+		}
+		fmt.Fprintf(
+			w, "%s\t%s\t%d\t%s\t%dms\t%dms\t%dms\n",
+			e.ID, status, e.Frames, humanize.Bytes(uint64(e.Bytes)), e.LoadMs, e.RunMs, e.EncodeMs,
+		)
+	}
+	w.Flush()
+
+	for _, e := range summary {
+		if e.Error != "" {
+			fmt.Printf("\n%s: %s\n", e.ID, e.Error)
+		}
+	}
+
+	fmt.Printf("\nrendered %d of %d jobs in %s\n", len(results)-failed, len(results), elapsed.Round(time.Millisecond))
+
+	if renderManySummary != "" {
+		b, err := json.MarshalIndent(summary, "", "  ")
+		if err != nil {
+			return fmt.Errorf("serializing summary: %w", err)
+		}
+		if err := os.WriteFile(renderManySummary, b, 0644); err != nil {
+			return fmt.Errorf("writing summary: %w", err)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d jobs failed", failed, len(results))
+	}
+
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"tidbyt.dev/pixlet/runtime"
 	"tidbyt.dev/pixlet/server"
 )
 
@@ -22,18 +23,25 @@ func init() {
 	ServeCmd.Flags().IntVarP(&maxDuration, "max_duration", "d", 15000, "Maximum allowed animation duration (ms)")
 	ServeCmd.Flags().IntVarP(&timeout, "timeout", "", 30000, "Timeout for execution (ms)")
 	ServeCmd.Flags().BoolVarP(&serveGif, "gif", "", false, "Generate GIF instead of WebP")
+	ServeCmd.Flags().StringVarP(&cacheDir, "cache-dir", "", "", "Persist the cache in this directory instead of in memory")
+	ServeCmd.Flags().IntVarP(&cacheMaxSize, "cache-max-size", "", 100, "Maximum size of the on-disk cache (MB)")
+	addLimitFlags(ServeCmd)
 }
 
 var ServeCmd = &cobra.Command{
-	Use:   "serve [path]",
+	Use:   "serve [path]...",
 	Short: "Serve a Pixlet app in a web server",
-	Args:  cobra.ExactArgs(1),
+	Args:  cobra.MinimumNArgs(1),
 	RunE:  serve,
 	Long: `Serve a Pixlet app in a web server.
 
 The path argument should be the path to the Pixlet program to run. The
 program can be a single file with the .star extension, or a directory
-containing multiple Starlark files and resources.`,
+containing multiple Starlark files and resources.
+
+To serve several apps at once, pass several paths, or a directory
+whose subdirectories are apps. Each app is then served under
+/apps/<name>/, and the index page lists them all.`,
 }
 
 func serve(cmd *cobra.Command, args []string) error {
@@ -41,7 +49,31 @@ func serve(cmd *cobra.Command, args []string) error {
 		fmt.Printf("explicitly setting --watch is unnecessary, since it's the default\n\n")
 	}
 
-	s, err := server.NewServer(host, port, watch, args[0], maxDuration, timeout, serveGif)
+	cache, err := newCache()
+	if err != nil {
+		return fmt.Errorf("failed to initialize cache: %w", err)
+	}
+	runtime.InitHTTP(cache)
+	runtime.InitCache(cache)
+
+	var paths []string
+	for _, arg := range args {
+		applets, err := server.FindApplets(arg)
+		if err != nil {
+			return err
+		}
+		paths = append(paths, applets...)
+	}
+
+	if len(args) == 1 && len(paths) == 1 {
+		s, err := server.NewServer(host, port, watch, paths[0], maxDuration, timeout, serveGif, limitOptions()...)
+		if err != nil {
+			return err
+		}
+		return s.Run()
+	}
+
+	s, err := server.NewMultiServer(host, port, watch, paths, maxDuration, timeout, serveGif, limitOptions()...)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"tidbyt.dev/pixlet/globals"
+	"tidbyt.dev/pixlet/runtime"
+	"tidbyt.dev/pixlet/snapshot"
+	"tidbyt.dev/pixlet/tools"
+)
+
+var (
+	snapshotUpdate    bool
+	snapshotSuite     string
+	snapshotGoldenDir string
+	snapshotFormat    string
+	snapshotTolerance uint8
+)
+
+func init() {
+	SnapshotCmd.Flags().BoolVarP(&snapshotUpdate, "update", "u", false, "Rewrite the golden frames instead of comparing against them")
+	SnapshotCmd.Flags().StringVarP(&snapshotSuite, "suite", "s", "", "Path to the file listing snapshots and their configs (default <app dir>/snapshots.yaml)")
+	SnapshotCmd.Flags().StringVarP(&snapshotGoldenDir, "golden-dir", "g", "", "Directory holding golden frames (default <app dir>/snapshots)")
+	SnapshotCmd.Flags().StringVarP(&snapshotFormat, "format", "f", string(snapshot.FormatPNG), "Format of golden frames written with --update: png or webp")
+	SnapshotCmd.Flags().Uint8VarP(&snapshotTolerance, "tolerance", "", 0, "Largest per-channel color difference that isn't considered a change")
+	SnapshotCmd.Flags().StringVarP(&replayHTTP, "replay-http", "", "", "Serve HTTP requests made by the app only from fixtures in this directory")
+	SnapshotCmd.Flags().IntVarP(&width, "width", "w", 64, "Set width")
+	SnapshotCmd.Flags().IntVarP(&height, "height", "t", 32, "Set height")
+	SnapshotCmd.Flags().IntVarP(&timeout, "timeout", "", 30000, "Timeout for execution (ms)")
+}
+
+var SnapshotCmd = &cobra.Command{
+	Use:   "snapshot [path]",
+	Short: "Compare a Pixlet app's rendered frames against golden images",
+	Example: `  pixlet snapshot examples/clock
+  pixlet snapshot --update examples/clock`,
+	Args: cobra.ExactArgs(1),
+	RunE: snapshotCmd,
+	Long: `Compare a Pixlet app's rendered frames against golden images.
+
+The path argument should be the path to the Pixlet app to run. The
+app can be a single file with the .star extension, or a directory
+containing multiple Starlark files and resources.
+
+The app is rendered once for every snapshot listed in the suite file,
+using the config given for it:
+
+  snapshots:
+    - name: default
+    - name: small
+      config:
+        who: Tidbyt
+        small: "true"
+
+Without a suite file, a single snapshot named "default" is taken with
+an empty config. Every frame is compared against the golden frames
+stored in the golden directory. When frames differ, an image showing
+the expected frame, actual frame and their difference is written to a
+directory next to the golden directory. Use --update to create or
+rewrite the golden frames.
+
+The app's test functions are run first, just like with pixlet test,
+and fail the command too if they fail.`,
+}
+
+func snapshotCmd(cmd *cobra.Command, args []string) error {
+	path := args[0]
+
+	// check if path exists, and whether it is a directory or a file
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+
+	var fsys fs.FS
+	var baseDir string
+	if info.IsDir() {
+		fsys = os.DirFS(path)
+		baseDir = path
+	} else {
+		if !strings.HasSuffix(path, ".star") {
+			return fmt.Errorf("script file must have suffix .star: %s", path)
+		}
+
+		fsys = tools.NewSingleFileFS(path)
+		baseDir = filepath.Dir(path)
+	}
+
+	suitePath := snapshotSuite
+	if suitePath == "" {
+		suitePath = filepath.Join(baseDir, snapshot.SuiteFileName)
+	}
+
+	goldenDir := snapshotGoldenDir
+	if goldenDir == "" {
+		goldenDir = filepath.Join(baseDir, snapshot.GoldenDirName)
+	}
+
+	format := snapshot.Format(snapshotFormat)
+	if format != snapshot.FormatPNG && format != snapshot.FormatWebP {
+		return fmt.Errorf("unsupported golden format: %s", snapshotFormat)
+	}
+
+	suite, err := loadSnapshotSuite(suitePath, snapshotSuite != "")
+	if err != nil {
+		return err
+	}
+
+	globals.Width = width
+	globals.Height = height
+
+	cache := runtime.NewInMemoryCache()
+	if err := initHTTP(cache); err != nil {
+		return fmt.Errorf("failed to initialize HTTP: %w", err)
+	}
+	runtime.InitCache(cache)
+
+	applet, err := runtime.NewAppletFromFS(filepath.Base(path), fsys, runtime.WithPrintDisabled())
+	if err != nil {
+		return fmt.Errorf("failed to load applet: %w", err)
+	}
+
+	runner := &snapshot.Runner{
+		Applet:    applet,
+		GoldenDir: goldenDir,
+		Update:    snapshotUpdate,
+		Format:    format,
+		Tolerance: snapshotTolerance,
+	}
+
+	var tests []*testResult
+	for _, test := range applet.TestFunctions() {
+		tests = append(tests, runTest(applet, fmt.Sprintf("%s/%s", test.File, test.Name), test))
+	}
+	if err := reportText(os.Stdout, applet.ID, tests); err != nil {
+		return fmt.Errorf("writing test results: %w", err)
+	}
+
+	failedTests := 0
+	for _, t := range tests {
+		if !t.Passed() {
+			failedTests++
+		}
+	}
+
+	failed := 0
+	for _, c := range suite.Cases {
+		ctx := context.Background()
+		var cancel context.CancelFunc
+		if timeout > 0 {
+			ctx, cancel = context.WithTimeoutCause(
+				ctx,
+				time.Duration(timeout)*time.Millisecond,
+				fmt.Errorf("timeout after %dms", timeout),
+			)
+		}
+
+		result := runner.RunCase(ctx, c)
+		if cancel != nil {
+			cancel()
+		}
+
+		printSnapshotResult(result)
+		if !result.Passed() {
+			failed++
+		}
+	}
+
+	if failedTests > 0 {
+		return fmt.Errorf("%d of %d tests and %d of %d snapshots failed", failedTests, len(tests), failed, len(suite.Cases))
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d snapshots failed", failed, len(suite.Cases))
+	}
+
+	return nil
+}
+
+// loadSnapshotSuite reads the suite file. A missing suite file is only an
+// error if it was explicitly requested.
+func loadSnapshotSuite(path string, required bool) (*snapshot.Suite, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) && !required {
+		return snapshot.DefaultSuite(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open snapshot suite: %w", err)
+	}
+	defer f.Close()
+
+	return snapshot.LoadSuite(f)
+}
+
+func printSnapshotResult(result *snapshot.Result) {
+	name := result.Case.Name
+
+	switch {
+	case result.Err != nil:
+		color.New(color.FgRed).Printf("✖ %s\n", name)
+		fmt.Printf("  ▪️ %v\n", result.Err)
+
+	case len(result.Mismatches) > 0:
+		color.New(color.FgRed).Printf("✖ %s\n", name)
+		for _, m := range result.Mismatches {
+			fmt.Printf("  ▪️ %s, see %s\n", m, m.Path)
+		}
+
+	case result.Updated:
+		color.New(color.FgYellow).Printf("✎ %s (%d frames updated)\n", name, result.Frames)
+
+	default:
+		color.New(color.FgGreen).Printf("✔️ %s (%d frames)\n", name, result.Frames)
+	}
+}
@@ -0,0 +1,317 @@
+package cmd
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"tidbyt.dev/pixlet/runtime"
+	"tidbyt.dev/pixlet/tools"
+)
+
+var (
+	testRun    string
+	testFormat string
+	testOutput string
+)
+
+func init() {
+	TestCmd.Flags().StringVarP(&testRun, "run", "r", "", "Only run tests whose name matches this regular expression")
+	TestCmd.Flags().StringVarP(&testFormat, "format", "f", "text", "Output format: text, junit or tap")
+	TestCmd.Flags().StringVarP(&testOutput, "output", "o", "-", "Path to write test results to")
+	TestCmd.Flags().StringVarP(&replayHTTP, "replay-http", "", "", "Serve HTTP requests made by the tests only from fixtures in this directory")
+	TestCmd.Flags().IntVarP(&timeout, "timeout", "", 30000, "Timeout for each test function (ms)")
+}
+
+var TestCmd = &cobra.Command{
+	Use:   "test [path]",
+	Short: "Run the test functions of a Pixlet app",
+	Example: `  pixlet test examples/clock
+  pixlet test --run 'test_format_.*' --format junit -o report.xml examples/clock`,
+	Args: cobra.ExactArgs(1),
+	RunE: testCmd,
+	Long: `Run the test functions of a Pixlet app.
+
+The path argument should be the path to the Pixlet app to test. The
+app can be a single file with the .star extension, or a directory
+containing multiple Starlark files and resources.
+
+Every top-level function whose name starts with test_ is run, in any
+of the app's files. Tests can use the assert module to check their
+expectations:
+
+  load("assert.star", "assert")
+
+  def test_greeting():
+      assert.eq(greeting("World"), "Hello, World!")
+
+A test fails if an assertion fails or if it stops with an error, like
+a call to fail(). Results are printed as text by default, or as JUnit
+XML or TAP for use in CI.`,
+}
+
+// testResult is the outcome of a single test function.
+type testResult struct {
+	Name     string
+	Failures []string
+	Duration time.Duration
+}
+
+func (r *testResult) Error(args ...interface{}) {
+	r.Failures = append(r.Failures, fmt.Sprint(args...))
+}
+
+func (r *testResult) Passed() bool {
+	return len(r.Failures) == 0
+}
+
+func testCmd(cmd *cobra.Command, args []string) error {
+	path := args[0]
+
+	// check if path exists, and whether it is a directory or a file
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+
+	var fsys fs.FS
+	if info.IsDir() {
+		fsys = os.DirFS(path)
+	} else {
+		if !strings.HasSuffix(path, ".star") {
+			return fmt.Errorf("script file must have suffix .star: %s", path)
+		}
+
+		fsys = tools.NewSingleFileFS(path)
+	}
+
+	var filter *regexp.Regexp
+	if testRun != "" {
+		filter, err = regexp.Compile(testRun)
+		if err != nil {
+			return fmt.Errorf("invalid --run expression: %w", err)
+		}
+	}
+
+	var report func(io.Writer, string, []*testResult) error
+	switch testFormat {
+	case "text":
+		report = reportText
+	case "junit":
+		report = reportJUnit
+	case "tap":
+		report = reportTAP
+	default:
+		return fmt.Errorf("unsupported output format: %s", testFormat)
+	}
+
+	cache := runtime.NewInMemoryCache()
+	if err := initHTTP(cache); err != nil {
+		return fmt.Errorf("failed to initialize HTTP: %w", err)
+	}
+	runtime.InitCache(cache)
+
+	applet, err := runtime.NewAppletFromFS(filepath.Base(path), fsys, runtime.WithPrintDisabled())
+	if err != nil {
+		return fmt.Errorf("failed to load applet: %w", err)
+	}
+
+	var results []*testResult
+	for _, test := range applet.TestFunctions() {
+		name := fmt.Sprintf("%s/%s", test.File, test.Name)
+		if filter != nil && !filter.MatchString(name) {
+			continue
+		}
+
+		results = append(results, runTest(applet, name, test))
+	}
+
+	out := io.Writer(os.Stdout)
+	if testOutput != "-" {
+		f, err := os.Create(testOutput)
+		if err != nil {
+			return fmt.Errorf("creating %s: %w", testOutput, err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if err := report(out, applet.ID, results); err != nil {
+		return fmt.Errorf("writing test results: %w", err)
+	}
+
+	if len(results) == 0 {
+		return fmt.Errorf("no test functions found")
+	}
+
+	failed := 0
+	for _, r := range results {
+		if !r.Passed() {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d tests failed", failed, len(results))
+	}
+
+	return nil
+}
+
+func runTest(applet *runtime.Applet, name string, test runtime.TestFunction) *testResult {
+	result := &testResult{Name: name}
+
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(
+			ctx,
+			time.Duration(timeout)*time.Millisecond,
+			fmt.Errorf("timeout after %dms", timeout),
+		)
+		defer cancel()
+	}
+
+	start := time.Now()
+	if err := applet.RunTest(ctx, test, result); err != nil {
+		result.Error(err.Error())
+	}
+	result.Duration = time.Since(start)
+
+	return result
+}
+
+func reportText(w io.Writer, _ string, results []*testResult) error {
+	pass := color.New(color.FgGreen)
+	fail := color.New(color.FgRed)
+
+	for _, r := range results {
+		if r.Passed() {
+			pass.Fprintf(w, "✔️ %s (%s)\n", r.Name, r.Duration.Round(time.Millisecond))
+			continue
+		}
+
+		fail.Fprintf(w, "✖ %s (%s)\n", r.Name, r.Duration.Round(time.Millisecond))
+		for _, f := range r.Failures {
+			fmt.Fprintf(w, "%s\n", indent(f, "    "))
+		}
+	}
+
+	return nil
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Body    string `xml:",chardata"`
+}
+
+func reportJUnit(w io.Writer, appID string, results []*testResult) error {
+	suite := junitTestSuite{
+		Name:  appID,
+		Tests: len(results),
+	}
+
+	var total time.Duration
+	for _, r := range results {
+		total += r.Duration
+
+		c := junitTestCase{
+			Name:      r.Name,
+			ClassName: appID,
+			Time:      fmt.Sprintf("%.3f", r.Duration.Seconds()),
+		}
+
+		if !r.Passed() {
+			suite.Failures++
+			c.Failure = &junitFailure{
+				Message: lastLine(r.Failures[0]),
+				Body:    strings.Join(r.Failures, "\n"),
+			}
+		}
+
+		suite.Cases = append(suite.Cases, c)
+	}
+	suite.Time = fmt.Sprintf("%.3f", total.Seconds())
+
+	b, err := xml.MarshalIndent(junitTestSuites{Suites: []junitTestSuite{suite}}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	if _, err := w.Write(b); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+func reportTAP(w io.Writer, _ string, results []*testResult) error {
+	fmt.Fprintf(w, "TAP version 13\n")
+	fmt.Fprintf(w, "1..%d\n", len(results))
+
+	for i, r := range results {
+		if r.Passed() {
+			fmt.Fprintf(w, "ok %d - %s\n", i+1, r.Name)
+			continue
+		}
+
+		fmt.Fprintf(w, "not ok %d - %s\n", i+1, r.Name)
+		fmt.Fprintf(w, "  ---\n")
+		fmt.Fprintf(w, "  duration_ms: %d\n", r.Duration.Milliseconds())
+		fmt.Fprintf(w, "  message: |\n")
+		for _, f := range r.Failures {
+			fmt.Fprintf(w, "%s\n", indent(f, "    "))
+		}
+		fmt.Fprintf(w, "  ...\n")
+	}
+
+	return nil
+}
+
+func indent(s, prefix string) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	for i, l := range lines {
+		lines[i] = prefix + l
+	}
+	return strings.Join(lines, "\n")
+}
+
+// lastLine returns the last line of a failure, which holds the error message
+// after any backtrace.
+func lastLine(s string) string {
+	s = strings.TrimRight(s, "\n")
+	return strings.TrimSpace(s[strings.LastIndex(s, "\n")+1:])
+}
@@ -0,0 +1,229 @@
+package encode
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+)
+
+var pngSignature = []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n'}
+
+// Renders a screen to an animated PNG. Optionally pass filters for
+// postprocessing each individual frame.
+//
+// Like with GIF, every frame after the first only holds the part that
+// changed since the previous one.
+func (s *Screens) EncodeAPNG(maxDuration int, filters ...ImageFilter) ([]byte, error) {
+	images, delays, err := s.timedFrames(maxDuration, filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(images) == 0 {
+		return []byte{}, nil
+	}
+
+	frames := make([]*image.RGBA, len(images))
+	opaque := true
+	for i, im := range images {
+		imRGBA, ok := im.(*image.RGBA)
+		if !ok {
+			imRGBA = toRGBA(im)
+		}
+		frames[i] = imRGBA
+		opaque = opaque && imRGBA.Opaque()
+	}
+
+	bpp := 4
+	colorType := byte(6) // RGBA
+	if opaque {
+		bpp = 3
+		colorType = 2 // RGB
+	}
+
+	bounds := frames[0].Bounds()
+	buf := &bytes.Buffer{}
+	buf.Write(pngSignature)
+
+	ihdr := make([]byte, 13)
+	binary.BigEndian.PutUint32(ihdr[0:], uint32(bounds.Dx()))
+	binary.BigEndian.PutUint32(ihdr[4:], uint32(bounds.Dy()))
+	ihdr[8] = 8 // bits per channel
+	ihdr[9] = colorType
+	writeChunk(buf, "IHDR", ihdr)
+
+	actl := make([]byte, 8)
+	binary.BigEndian.PutUint32(actl[0:], uint32(len(frames)))
+	binary.BigEndian.PutUint32(actl[4:], 0) // loop forever
+	writeChunk(buf, "acTL", actl)
+
+	seq := uint32(0)
+	for i, frame := range frames {
+		rect := bounds
+		if i > 0 {
+			rect = changedBounds(frames[i-1], frame)
+		}
+
+		// the delay is a fraction of a second, with a 16 bit numerator
+		delayNum, delayDen := delays[i], 1000
+		if delayNum > 0xffff {
+			delayNum, delayDen = delayNum/10, 100
+		}
+		if delayNum > 0xffff {
+			delayNum = 0xffff
+		}
+
+		fctl := make([]byte, 26)
+		binary.BigEndian.PutUint32(fctl[0:], seq)
+		binary.BigEndian.PutUint32(fctl[4:], uint32(rect.Dx()))
+		binary.BigEndian.PutUint32(fctl[8:], uint32(rect.Dy()))
+		binary.BigEndian.PutUint32(fctl[12:], uint32(rect.Min.X-bounds.Min.X))
+		binary.BigEndian.PutUint32(fctl[16:], uint32(rect.Min.Y-bounds.Min.Y))
+		binary.BigEndian.PutUint16(fctl[20:], uint16(delayNum))
+		binary.BigEndian.PutUint16(fctl[22:], uint16(delayDen))
+		fctl[24] = 0 // APNG_DISPOSE_OP_NONE
+		fctl[25] = 0 // APNG_BLEND_OP_SOURCE
+		writeChunk(buf, "fcTL", fctl)
+		seq++
+
+		data, err := compressPixels(frame, rect, bpp)
+		if err != nil {
+			return nil, err
+		}
+
+		if i == 0 {
+			writeChunk(buf, "IDAT", data)
+		} else {
+			fdat := make([]byte, 4, 4+len(data))
+			binary.BigEndian.PutUint32(fdat, seq)
+			writeChunk(buf, "fdAT", append(fdat, data...))
+			seq++
+		}
+	}
+
+	writeChunk(buf, "IEND", nil)
+
+	return buf.Bytes(), nil
+}
+
+func writeChunk(buf *bytes.Buffer, name string, data []byte) {
+	var header [8]byte
+	binary.BigEndian.PutUint32(header[:4], uint32(len(data)))
+	copy(header[4:], name)
+	buf.Write(header[:])
+	buf.Write(data)
+
+	crc := crc32.NewIEEE()
+	crc.Write(header[4:])
+	crc.Write(data)
+	binary.Write(buf, binary.BigEndian, crc.Sum32())
+}
+
+// compressPixels returns the zlib compressed, filtered scanlines of the
+// rect part of img, with bpp bytes per pixel.
+func compressPixels(img *image.RGBA, rect image.Rectangle, bpp int) ([]byte, error) {
+	out := &bytes.Buffer{}
+	zw, err := zlib.NewWriterLevel(out, zlib.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+
+	rowLen := rect.Dx() * bpp
+	prev := make([]byte, rowLen)
+	cur := make([]byte, rowLen)
+	filtered := make([]byte, rowLen+1)
+
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			c := img.RGBAAt(x, y)
+			px := cur[(x-rect.Min.X)*bpp:]
+			if bpp == 3 {
+				px[0], px[1], px[2] = c.R, c.G, c.B
+				continue
+			}
+			nc := color.NRGBAModel.Convert(c).(color.NRGBA)
+			px[0], px[1], px[2], px[3] = nc.R, nc.G, nc.B, nc.A
+		}
+
+		filterRow(filtered, cur, prev, bpp)
+		if _, err := zw.Write(filtered); err != nil {
+			return nil, err
+		}
+
+		prev, cur = cur, prev
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
+
+// filterRow writes the filter type and filtered bytes of cur to out,
+// picking the filter with the smallest sum of absolute differences,
+// like most PNG encoders do.
+func filterRow(out, cur, prev []byte, bpp int) {
+	best := -1
+	candidate := make([]byte, len(cur))
+
+	for filter := byte(0); filter < 5; filter++ {
+		sum := 0
+		for i := range cur {
+			var a, b, c byte
+			if i >= bpp {
+				a, c = cur[i-bpp], prev[i-bpp]
+			}
+			b = prev[i]
+
+			var v byte
+			switch filter {
+			case 0:
+				v = cur[i]
+			case 1:
+				v = cur[i] - a
+			case 2:
+				v = cur[i] - b
+			case 3:
+				v = cur[i] - byte((int(a)+int(b))/2)
+			case 4:
+				v = cur[i] - paeth(a, b, c)
+			}
+
+			candidate[i] = v
+			if int8(v) < 0 {
+				sum -= int(int8(v))
+			} else {
+				sum += int(v)
+			}
+		}
+
+		if best < 0 || sum < best {
+			best = sum
+			out[0] = filter
+			copy(out[1:], candidate)
+		}
+	}
+}
+
+func paeth(a, b, c byte) byte {
+	p := int(a) + int(b) - int(c)
+	pa, pb, pc := abs(p-int(a)), abs(p-int(b)), abs(p-int(c))
+	if pa <= pb && pa <= pc {
+		return a
+	}
+	if pb <= pc {
+		return b
+	}
+	return c
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -1,6 +1,7 @@
 package encode
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"fmt"
 	"image"
@@ -13,13 +14,14 @@ import (
 const (
 	WebPKMin                 = 0
 	WebPKMax                 = 0
-	DefaultScreenDelayMillis = 50
+	DefaultScreenDelayMillis = render.DefaultFrameDelay
 	DefaultMaxAgeSeconds     = 0 // 0 => no max age, cache forever!
 )
 
 type Screens struct {
 	roots             []render.Root
 	images            []image.Image
+	delays            []int32
 	delay             int32
 	MaxAge            int32
 	ShowFullAnimation bool
@@ -84,9 +86,15 @@ func (s *Screens) Hash() ([]byte, error) {
 	return h[:], nil
 }
 
+// Frames paints every frame of the screens, and returns them after
+// applying any filters.
+func (s *Screens) Frames(filters ...ImageFilter) ([]image.Image, error) {
+	return s.render(filters...)
+}
+
 func (s *Screens) render(filters ...ImageFilter) ([]image.Image, error) {
 	if s.images == nil {
-		s.images = render.PaintRoots(true, s.roots...)
+		s.images, s.delays = render.PaintRootsWithDelays(true, s.roots...)
 	}
 
 	if len(s.images) == 0 {
@@ -111,3 +119,73 @@ func (s *Screens) render(filters ...ImageFilter) ([]image.Image, error) {
 
 	return images, nil
 }
+
+// timedFrames returns the frames to encode, along with how long each
+// of them is shown in milliseconds. Every frame is shown for the
+// FrameDelay of the root it came from.
+// Identical consecutive frames are merged into a single longer one,
+// and if maxDuration is positive, frames past it are dropped.
+func (s *Screens) timedFrames(maxDuration int, filters ...ImageFilter) ([]image.Image, []int, error) {
+	images, err := s.render(filters...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var frames []image.Image
+	var delays []int
+	for i, im := range images {
+		delay := int(s.delay)
+		if i < len(s.delays) && s.delays[i] > 0 {
+			delay = int(s.delays[i])
+		}
+
+		if n := len(frames); n > 0 && sameImage(frames[n-1], im) {
+			delays[n-1] += delay
+			continue
+		}
+
+		frames = append(frames, im)
+		delays = append(delays, delay)
+	}
+
+	if maxDuration <= 0 {
+		return frames, delays, nil
+	}
+
+	remainingDuration := maxDuration
+	for i := range frames {
+		if delays[i] >= remainingDuration {
+			delays[i] = remainingDuration
+			return frames[:i+1], delays[:i+1], nil
+		}
+		remainingDuration -= delays[i]
+	}
+
+	return frames, delays, nil
+}
+
+// sameImage reports whether a and b have the same pixels.
+func sameImage(a, b image.Image) bool {
+	if a.Bounds() != b.Bounds() {
+		return false
+	}
+
+	ra, okA := a.(*image.RGBA)
+	rb, okB := b.(*image.RGBA)
+	if okA && okB && ra.Stride == rb.Stride {
+		return bytes.Equal(ra.Pix, rb.Pix)
+	}
+
+	bounds := a.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r1, g1, b1, a1 := a.At(x, y).RGBA()
+			r2, g2, b2, a2 := b.At(x, y).RGBA()
+			if r1 != r2 || g1 != g2 || b1 != b2 || a1 != a2 {
+				return false
+			}
+		}
+	}
+
+	return true
+}
@@ -71,6 +71,25 @@ def main(config):
     )
 `
 
+// A headline scrolling across a mostly static screen
+var BenchmarkMarqueeDotStar = `
+load("render.star", "render")
+
+def main(config):
+    return render.Root(
+        child = render.Column(
+            children = [
+                render.Text("Headlines", color = "#ff0"),
+                render.Marquee(
+                    width = 64,
+                    child = render.Text("Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod tempor incididunt"),
+                ),
+                render.Box(height = 16, color = "#036"),
+            ],
+        ),
+    )
+`
+
 func BenchmarkRunAndRender(b *testing.B) {
 	app, err := runtime.NewApplet("benchmark.star", []byte(BenchmarkDotStar))
 	if err != nil {
@@ -91,5 +110,64 @@ func BenchmarkRunAndRender(b *testing.B) {
 		if len(webp) == 0 {
 			b.Error()
 		}
+
+		b.ReportMetric(float64(len(webp)), "bytes")
+	}
+}
+
+func BenchmarkRunAndRenderGIF(b *testing.B) {
+	app, err := runtime.NewApplet("benchmark.star", []byte(BenchmarkDotStar))
+	if err != nil {
+		b.Error(err)
+	}
+
+	for i := 0; i < b.N; i++ {
+		roots, err := app.Run(context.Background())
+		if err != nil {
+			b.Error(err)
+		}
+
+		gif, err := ScreensFromRoots(roots).EncodeGIF(15000)
+		if err != nil {
+			b.Error(err)
+		}
+
+		if len(gif) == 0 {
+			b.Error()
+		}
+
+		b.ReportMetric(float64(len(gif)), "bytes")
+	}
+}
+
+func BenchmarkMarquee(b *testing.B) {
+	app, err := runtime.NewApplet("marquee.star", []byte(BenchmarkMarqueeDotStar))
+	if err != nil {
+		b.Error(err)
 	}
+
+	roots, err := app.Run(context.Background())
+	if err != nil {
+		b.Error(err)
+	}
+
+	b.Run("webp", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			webp, err := ScreensFromRoots(roots).EncodeWebP(15000)
+			if err != nil {
+				b.Error(err)
+			}
+			b.ReportMetric(float64(len(webp)), "bytes")
+		}
+	})
+
+	b.Run("gif", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			gif, err := ScreensFromRoots(roots).EncodeGIF(15000)
+			if err != nil {
+				b.Error(err)
+			}
+			b.ReportMetric(float64(len(gif)), "bytes")
+		}
+	})
 }
@@ -3,7 +3,13 @@ package encode
 import (
 	"bytes"
 	"context"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/draw"
 	"image/gif"
+	"image/png"
 	"strings"
 	"testing"
 
@@ -282,32 +288,6 @@ def main():
 	// Source above will produce a 70 frame animation
 	assert.Equal(t, 70, roots[0].Child.FrameCount())
 
-	// These decode gif/webp and return all frame delays and
-	// their sum in milliseconds.
-	gifDelays := func(gifData []byte) []int {
-		im, err := gif.DecodeAll(bytes.NewBuffer(gifData))
-		assert.NoError(t, err)
-		delays := []int{}
-		for _, d := range im.Delay {
-			delays = append(delays, d*10)
-		}
-		return delays
-	}
-	webpDelays := func(webpData []byte) []int {
-		decoder, err := webp.NewAnimationDecoder(webpData)
-		assert.NoError(t, err)
-		img, err := decoder.Decode()
-		assert.NoError(t, err)
-		delays := []int{}
-		last := 0
-		for _, t := range img.Timestamp {
-			d := t - last
-			last = t
-			delays = append(delays, d)
-		}
-		return delays
-	}
-
 	// With 500ms delay per frame, total duration will be
 	// 50000. The encode methods should truncate this down to
 	// whatever fits in the maxDuration.
@@ -317,16 +297,16 @@ def main():
 	assert.NoError(t, err)
 	webpData, err := ScreensFromRoots(roots).EncodeWebP(3000)
 	assert.NoError(t, err)
-	assert.Equal(t, []int{500, 500, 500, 500, 500, 500}, gifDelays(gifData))
-	assert.Equal(t, []int{500, 500, 500, 500, 500, 500}, webpDelays(webpData))
+	assert.Equal(t, []int{500, 500, 500, 500, 500, 500}, gifDelays(t, gifData))
+	assert.Equal(t, []int{500, 500, 500, 500, 500, 500}, webpDelays(t, webpData))
 
 	// 2200 ms -> 5 frames, with last given only 200ms
 	gifData, err = ScreensFromRoots(roots).EncodeGIF(2200)
 	assert.NoError(t, err)
 	webpData, err = ScreensFromRoots(roots).EncodeWebP(2200)
 	assert.NoError(t, err)
-	assert.Equal(t, []int{500, 500, 500, 500, 200}, gifDelays(gifData))
-	assert.Equal(t, []int{500, 500, 500, 500, 200}, webpDelays(webpData))
+	assert.Equal(t, []int{500, 500, 500, 500, 200}, gifDelays(t, gifData))
+	assert.Equal(t, []int{500, 500, 500, 500, 200}, webpDelays(t, webpData))
 
 	// 100 ms -> single frame. Its duration will differ between
 	// gif and webp, but is also irrelevant.
@@ -334,16 +314,16 @@ def main():
 	assert.NoError(t, err)
 	webpData, err = ScreensFromRoots(roots).EncodeWebP(100)
 	assert.NoError(t, err)
-	assert.Equal(t, []int{100}, gifDelays(gifData))
-	assert.Equal(t, []int{0}, webpDelays(webpData))
+	assert.Equal(t, []int{100}, gifDelays(t, gifData))
+	assert.Equal(t, []int{0}, webpDelays(t, webpData))
 
 	// 60000 ms -> all 100 frames, 500 ms each.
 	gifData, err = ScreensFromRoots(roots).EncodeGIF(60000)
 	assert.NoError(t, err)
 	webpData, err = ScreensFromRoots(roots).EncodeWebP(60000)
 	assert.NoError(t, err)
-	assert.Equal(t, gifDelays(gifData), webpDelays(webpData))
-	for _, d := range gifDelays(gifData) {
+	assert.Equal(t, gifDelays(t, gifData), webpDelays(t, webpData))
+	for _, d := range gifDelays(t, gifData) {
 		assert.Equal(t, 500, d)
 	}
 
@@ -352,9 +332,438 @@ def main():
 	assert.NoError(t, err)
 	webpData, err = ScreensFromRoots(roots).EncodeWebP(0)
 	assert.NoError(t, err)
-	assert.Equal(t, gifDelays(gifData), webpDelays(webpData))
-	for _, d := range gifDelays(gifData) {
+	assert.Equal(t, gifDelays(t, gifData), webpDelays(t, webpData))
+	for _, d := range gifDelays(t, gifData) {
 		assert.Equal(t, 500, d)
 	}
 
 }
+
+// These decode gif/webp and return all frame delays in milliseconds.
+func gifDelays(t *testing.T, gifData []byte) []int {
+	im, err := gif.DecodeAll(bytes.NewBuffer(gifData))
+	assert.NoError(t, err)
+	delays := []int{}
+	for _, d := range im.Delay {
+		delays = append(delays, d*10)
+	}
+	return delays
+}
+
+func webpDelays(t *testing.T, webpData []byte) []int {
+	decoder, err := webp.NewAnimationDecoder(webpData)
+	assert.NoError(t, err)
+	img, err := decoder.Decode()
+	assert.NoError(t, err)
+	delays := []int{}
+	last := 0
+	for _, t := range img.Timestamp {
+		d := t - last
+		last = t
+		delays = append(delays, d)
+	}
+	return delays
+}
+
+func TestPerRootDelays(t *testing.T) {
+	roots := []render.Root{
+		{Child: &render.Box{Width: 1, Color: color.White}, Delay: 1000},
+		{Child: &render.Animation{Children: []render.Widget{
+			&render.Box{Width: 2, Color: color.White},
+			&render.Box{Width: 3, Color: color.White},
+		}}},
+		{Child: &render.Box{Width: 4, Color: color.White}, Delay: 200},
+	}
+
+	// every root keeps its own delay, and the ones without use that of
+	// the first root
+	gifData, err := ScreensFromRoots(roots).EncodeGIF(0)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1000, 1000, 1000, 200}, gifDelays(t, gifData))
+
+	webpData, err := ScreensFromRoots(roots).EncodeWebP(0)
+	require.NoError(t, err)
+	assert.Equal(t, gifDelays(t, gifData), webpDelays(t, webpData))
+
+	// max duration cuts into the longer frames
+	gifData, err = ScreensFromRoots(roots).EncodeGIF(1500)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1000, 500}, gifDelays(t, gifData))
+
+	images, delays := render.PaintRootsWithDelays(true, roots...)
+	assert.Equal(t, 4, len(images))
+	assert.Equal(t, []int32{1000, 1000, 1000, 200}, delays)
+}
+
+func TestMergeIdenticalFrames(t *testing.T) {
+	box := func(w int) render.Widget {
+		return &render.Box{Width: w, Color: color.White}
+	}
+
+	// a screen held for 5 frames, then 2 frames of another
+	roots := []render.Root{{
+		Delay: 100,
+		Child: &render.Animation{Children: []render.Widget{
+			box(1), box(1), box(1), box(1), box(1), box(2), box(2), box(1),
+		}},
+	}}
+
+	gifData, err := ScreensFromRoots(roots).EncodeGIF(0)
+	require.NoError(t, err)
+	assert.Equal(t, []int{500, 200, 100}, gifDelays(t, gifData))
+
+	webpData, err := ScreensFromRoots(roots).EncodeWebP(0)
+	require.NoError(t, err)
+	assert.Equal(t, gifDelays(t, gifData), webpDelays(t, webpData))
+
+	// the frames themselves are left alone
+	frames, err := ScreensFromRoots(roots).Frames()
+	require.NoError(t, err)
+	assert.Equal(t, 8, len(frames))
+
+	// frames that only become identical after filtering are merged too
+	gifData, err = ScreensFromRoots(roots).EncodeGIF(0, func(im image.Image) (image.Image, error) {
+		return image.NewRGBA(im.Bounds()), nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int{800}, gifDelays(t, gifData))
+}
+
+func TestGIFDeltaFrames(t *testing.T) {
+	// a box moving across a static background
+	frames := []render.Widget{}
+	for x := 0; x < 4; x++ {
+		frames = append(frames, &render.Stack{Children: []render.Widget{
+			&render.Box{Color: color.RGBA{0, 0, 0x80, 0xff}},
+			&render.Padding{
+				Pad:   render.Insets{Left: x * 4, Top: 8},
+				Child: &render.Box{Width: 4, Height: 4, Color: color.RGBA{0xff, 0, 0, 0xff}},
+			},
+		}})
+	}
+	roots := []render.Root{{Child: &render.Animation{Children: frames}}}
+
+	painted, err := ScreensFromRoots(roots).Frames()
+	require.NoError(t, err)
+
+	gifData, err := ScreensFromRoots(roots).EncodeGIF(0)
+	require.NoError(t, err)
+	g, err := gif.DecodeAll(bytes.NewBuffer(gifData))
+	require.NoError(t, err)
+	require.Equal(t, 4, len(g.Image))
+
+	// only the first frame is whole, the others just hold what moved
+	assert.Equal(t, painted[0].Bounds(), g.Image[0].Bounds())
+	assert.Equal(t, image.Rect(0, 8, 8, 12), g.Image[1].Bounds())
+	assert.Equal(t, image.Rect(4, 8, 12, 12), g.Image[2].Bounds())
+	assert.Equal(t, image.Rect(8, 8, 16, 12), g.Image[3].Bounds())
+
+	// and drawing them on top of each other gives back every frame
+	canvas := image.NewRGBA(painted[0].Bounds())
+	for i, frame := range g.Image {
+		assert.Equal(t, byte(gif.DisposalNone), g.Disposal[i])
+		draw.Draw(canvas, frame.Bounds(), frame, frame.Bounds().Min, draw.Src)
+		assert.Equal(t, painted[i], canvas, "frame %d", i)
+	}
+}
+
+func TestChangedBounds(t *testing.T) {
+	a := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	b := image.NewRGBA(image.Rect(0, 0, 8, 8))
+
+	// nothing changed
+	assert.Equal(t, image.Rect(0, 0, 1, 1), changedBounds(a, b))
+
+	b.Set(2, 3, color.White)
+	b.Set(5, 1, color.White)
+	assert.Equal(t, image.Rect(2, 1, 6, 4), changedBounds(a, b))
+}
+
+// apngFrames decodes every frame of an APNG written by EncodeAPNG,
+// returning the canvas after each one along with their delays.
+func apngFrames(t *testing.T, data []byte) ([]*image.RGBA, []int) {
+	require.True(t, bytes.HasPrefix(data, pngSignature))
+	data = data[len(pngSignature):]
+
+	var ihdr []byte
+	var canvas *image.RGBA
+	var fctl []byte
+	frames := []*image.RGBA{}
+	delays := []int{}
+	seq := uint32(0)
+
+	// a frame is decoded by wrapping it in a PNG of its own
+	drawFrame := func(pixels []byte) {
+		header := append([]byte{}, ihdr...)
+		copy(header, fctl[4:12])
+
+		single := &bytes.Buffer{}
+		single.Write(pngSignature)
+		writeChunk(single, "IHDR", header)
+		writeChunk(single, "IDAT", pixels)
+		writeChunk(single, "IEND", nil)
+		im, err := png.Decode(single)
+		require.NoError(t, err)
+
+		offset := image.Pt(int(binary.BigEndian.Uint32(fctl[12:])), int(binary.BigEndian.Uint32(fctl[16:])))
+		draw.Draw(canvas, im.Bounds().Add(offset), im, image.Point{}, draw.Src)
+		frames = append(frames, image.NewRGBA(canvas.Bounds()))
+		copy(frames[len(frames)-1].Pix, canvas.Pix)
+
+		num, den := binary.BigEndian.Uint16(fctl[20:]), binary.BigEndian.Uint16(fctl[22:])
+		delays = append(delays, int(num)*1000/int(den))
+		assert.Equal(t, []byte{0, 0}, fctl[24:26], "dispose and blend ops")
+	}
+
+	for len(data) > 0 {
+		require.GreaterOrEqual(t, len(data), 12)
+		length := binary.BigEndian.Uint32(data)
+		name := string(data[4:8])
+		chunk := data[8 : 8+length]
+		assert.Equal(t, crc32.ChecksumIEEE(data[4:8+length]), binary.BigEndian.Uint32(data[8+length:]), name)
+		data = data[12+length:]
+
+		switch name {
+		case "IHDR":
+			ihdr = chunk
+			canvas = image.NewRGBA(image.Rect(0, 0, int(binary.BigEndian.Uint32(chunk)), int(binary.BigEndian.Uint32(chunk[4:]))))
+		case "acTL":
+			assert.Equal(t, uint32(0), binary.BigEndian.Uint32(chunk[4:]), "loops forever")
+		case "fcTL":
+			assert.Equal(t, seq, binary.BigEndian.Uint32(chunk))
+			seq++
+			fctl = chunk
+		case "IDAT":
+			drawFrame(chunk)
+		case "fdAT":
+			assert.Equal(t, seq, binary.BigEndian.Uint32(chunk))
+			seq++
+			drawFrame(chunk[4:])
+		case "IEND":
+			assert.Equal(t, 0, len(data))
+		default:
+			t.Fatalf("unexpected chunk %s", name)
+		}
+	}
+
+	return frames, delays
+}
+
+func TestAPNG(t *testing.T) {
+	// a box moving across a static background
+	frames := []render.Widget{}
+	for x := 0; x < 4; x++ {
+		frames = append(frames, &render.Stack{Children: []render.Widget{
+			&render.Box{Color: color.RGBA{0, 0, 0x80, 0xff}},
+			&render.Padding{
+				Pad:   render.Insets{Left: x * 4, Top: 8},
+				Child: &render.Box{Width: 4, Height: 4, Color: color.RGBA{0xff, 0, 0, 0xff}},
+			},
+		}})
+	}
+	roots := []render.Root{{Child: &render.Animation{Children: frames}, Delay: 80}}
+
+	painted, err := ScreensFromRoots(roots).Frames()
+	require.NoError(t, err)
+
+	data, err := ScreensFromRoots(roots).EncodeAPNG(0)
+	require.NoError(t, err)
+
+	// plain PNG decoders just see the first frame
+	first, err := png.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, color.RGBAModel, first.ColorModel())
+	assert.Equal(t, painted[0].At(4, 9), first.At(4, 9))
+	assert.Equal(t, painted[0].At(0, 0), first.At(0, 0))
+
+	decoded, delays := apngFrames(t, data)
+	assert.Equal(t, []int{80, 80, 80, 80}, delays)
+	require.Equal(t, 4, len(decoded))
+	for i := range decoded {
+		assert.Equal(t, painted[i], decoded[i], "frame %d", i)
+	}
+
+	// max duration applies as usual
+	data, err = ScreensFromRoots(roots).EncodeAPNG(200)
+	require.NoError(t, err)
+	_, delays = apngFrames(t, data)
+	assert.Equal(t, []int{80, 80, 40}, delays)
+}
+
+func TestAPNGTransparent(t *testing.T) {
+	a := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	a.Set(1, 1, color.RGBA{0x33, 0, 0, 0x33})
+	b := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	b.Set(2, 3, color.RGBA{0, 0xff, 0, 0xff})
+
+	data, err := ScreensFromImages(a, b).EncodeAPNG(0)
+	require.NoError(t, err)
+
+	// frames with alpha are kept as is
+	first, err := png.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, color.NRGBAModel, first.ColorModel())
+	assert.Equal(t, color.NRGBA{0xff, 0, 0, 0x33}, first.At(1, 1))
+
+	decoded, _ := apngFrames(t, data)
+	require.Equal(t, 2, len(decoded))
+	assert.Equal(t, a, decoded[0])
+	assert.Equal(t, b, decoded[1])
+}
+
+func TestRaw(t *testing.T) {
+	im := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	im.Set(0, 0, color.RGBA{0xff, 0x80, 0x10, 0xff})
+	im.Set(1, 0, color.RGBA{0x40, 0, 0, 0x80})
+	other := image.NewRGBA(image.Rect(0, 0, 2, 1))
+
+	screens := ScreensFromImages(im, im, other)
+	screens.delay = 30
+
+	data, err := screens.EncodeRaw(0, RawRGB888)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{
+		'P', 'X', 'L', 'T', 1, 2,
+		2, 0, 1, 0, 2, 0,
+		// identical frames are merged
+		60, 0, 0, 0,
+		0xff, 0x80, 0x10, 0x40, 0, 0,
+		30, 0, 0, 0,
+		0, 0, 0, 0, 0, 0,
+	}, data)
+
+	data, err = screens.EncodeRaw(0, RawRGB565)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{
+		'P', 'X', 'L', 'T', 1, 1,
+		2, 0, 1, 0, 2, 0,
+		60, 0, 0, 0,
+		0x02, 0xfc, 0x00, 0x40,
+		30, 0, 0, 0,
+		0, 0, 0, 0,
+	}, data)
+
+	// max duration applies as usual
+	data, err = screens.EncodeRaw(40, RawRGB565)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1, 0, 40, 0, 0, 0}, data[10:16])
+
+	_, err = screens.EncodeRaw(0, RawFormat(7))
+	assert.Error(t, err)
+}
+
+func TestPNGFrames(t *testing.T) {
+	roots := []render.Root{{
+		Delay: 100,
+		Child: &render.Animation{Children: []render.Widget{
+			&render.Box{Width: 1, Color: color.White},
+			&render.Box{Width: 1, Color: color.White},
+			&render.Box{Width: 2, Color: color.White},
+			&render.Box{Width: 3, Color: color.White},
+		}},
+	}}
+
+	painted, err := ScreensFromRoots(roots).Frames()
+	require.NoError(t, err)
+
+	frames, delays, err := ScreensFromRoots(roots).EncodePNGFrames(0)
+	require.NoError(t, err)
+	assert.Equal(t, []int{200, 100, 100}, delays)
+	require.Equal(t, 3, len(frames))
+	for i, data := range frames {
+		im, err := png.Decode(bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.True(t, sameImage(painted[i+1], im), "frame %d", i)
+	}
+
+	frames, delays, err = ScreensFromRoots(roots).EncodePNGFrames(250)
+	require.NoError(t, err)
+	assert.Equal(t, 2, len(frames))
+	assert.Equal(t, []int{200, 50}, delays)
+}
+
+func TestSpriteSheet(t *testing.T) {
+	roots := []render.Root{{
+		Delay: 100,
+		Child: &render.Animation{Children: []render.Widget{
+			&render.Box{Width: 1, Color: color.White},
+			&render.Box{Width: 2, Color: color.White},
+			&render.Box{Width: 3, Color: color.White},
+		}},
+	}}
+
+	painted, err := ScreensFromRoots(roots).Frames()
+	require.NoError(t, err)
+
+	// 3 frames fit a 2x2 grid
+	data, sheet, err := ScreensFromRoots(roots).EncodeSpriteSheet(0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, &SpriteSheet{
+		FrameWidth:  64,
+		FrameHeight: 32,
+		Columns:     2,
+		Rows:        2,
+		Duration:    300,
+		Frames: []SpriteFrame{
+			{X: 0, Y: 0, Delay: 100},
+			{X: 64, Y: 0, Delay: 100},
+			{X: 0, Y: 32, Delay: 100},
+		},
+	}, sheet)
+
+	im, err := png.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	require.Equal(t, image.Rect(0, 0, 128, 64), im.Bounds())
+	for i, frame := range sheet.Frames {
+		sub := im.(interface {
+			SubImage(image.Rectangle) image.Image
+		}).SubImage(image.Rect(frame.X, frame.Y, frame.X+64, frame.Y+32))
+		assert.True(t, sameImage(painted[i], toRGBA(sub)), "frame %d", i)
+	}
+
+	// or a single row, if asked for
+	data, sheet, err = ScreensFromRoots(roots).EncodeSpriteSheet(0, 5)
+	require.NoError(t, err)
+	assert.Equal(t, 3, sheet.Columns)
+	assert.Equal(t, 1, sheet.Rows)
+	im, err = png.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 192, 32), im.Bounds())
+}
+
+func TestTimeBasedInheritedDelay(t *testing.T) {
+	app, err := runtime.NewApplet("time_based.star", []byte(`
+load("render.star", "render")
+load("animation.star", "animation")
+
+def main():
+    return [
+        render.Root(delay = 100, child = render.Box(width = 1)),
+        render.Root(
+            time_based = True,
+            child = animation.Transformation(
+                child = render.Box(width = 4, height = 4, color = "#fff"),
+                duration = 500,
+                keyframes = [
+                    animation.Keyframe(percentage = 1.0, transforms = [animation.Translate(20, 0)]),
+                ],
+            ),
+        ),
+    ]
+`))
+	require.NoError(t, err)
+	roots, err := app.Run(context.Background())
+	require.NoError(t, err)
+
+	// the second root is shown with the delay of the first, so its
+	// 500ms animation is converted to 5 frames of 100ms
+	assert.Equal(t, 5, roots[1].Child.FrameCount())
+
+	gifData, err := ScreensFromRoots(roots).EncodeGIF(0)
+	require.NoError(t, err)
+	total := 0
+	for _, d := range gifDelays(t, gifData) {
+		total += d
+	}
+	assert.Equal(t, 600, total)
+}
@@ -0,0 +1,247 @@
+package encode
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"strconv"
+	"strings"
+
+	"tidbyt.dev/pixlet/internal/dither"
+	"tidbyt.dev/pixlet/render"
+)
+
+// A Quantizer maps a color to the closest one that can be shown, for
+// use with the dithering filters. Colors are not premultiplied, so
+// that semi-transparent pixels are quantized like opaque ones.
+type Quantizer func(c color.NRGBA) color.NRGBA
+
+// BitDepth returns a Quantizer that keeps only the given number of
+// bits of each color channel, e.g. 5, 6 and 5 for RGB565.
+func BitDepth(r, g, b int) Quantizer {
+	levels := [3]float64{}
+	for i, bits := range []int{r, g, b} {
+		if bits < 1 {
+			bits = 1
+		}
+		if bits > 8 {
+			bits = 8
+		}
+		levels[i] = float64(int(1)<<bits - 1)
+	}
+
+	quantize := func(v uint8, levels float64) uint8 {
+		return uint8(math.Round(math.Round(float64(v)/255*levels) / levels * 255))
+	}
+
+	return func(c color.NRGBA) color.NRGBA {
+		return color.NRGBA{
+			quantize(c.R, levels[0]),
+			quantize(c.G, levels[1]),
+			quantize(c.B, levels[2]),
+			c.A,
+		}
+	}
+}
+
+// Palette returns a Quantizer that picks the closest color of p.
+func Palette(p color.Palette) Quantizer {
+	return func(c color.NRGBA) color.NRGBA {
+		q := color.NRGBAModel.Convert(p.Convert(color.NRGBA{c.R, c.G, c.B, 0xff})).(color.NRGBA)
+		q.A = c.A
+		return q
+	}
+}
+
+// FloydSteinberg returns a filter that reduces the colors of an image
+// with q, spreading the error of each pixel to its neighbors.
+func FloydSteinberg(q Quantizer) ImageFilter {
+	return func(input image.Image) (image.Image, error) {
+		img := toNRGBA(input)
+		dither.FloydSteinberg(img, q)
+		return toRGBA(img), nil
+	}
+}
+
+// The 4x4 Bayer threshold matrix
+var bayer4 = [4][4]float64{
+	{0, 8, 2, 10},
+	{12, 4, 14, 6},
+	{3, 11, 1, 9},
+	{15, 7, 13, 5},
+}
+
+// Bayer returns a filter that reduces the colors of an image with q,
+// using ordered dithering with a 4x4 Bayer matrix. Unlike
+// FloydSteinberg, every pixel is dithered independently of the others,
+// so the pattern stays put in animations.
+func Bayer(q Quantizer) ImageFilter {
+	return func(input image.Image) (image.Image, error) {
+		img := toNRGBA(input)
+		w, h := img.Bounds().Dx(), img.Bounds().Dy()
+
+		// How far apart the colors of q are, estimated from how far it
+		// moves shades of gray. The threshold shifts each pixel by up
+		// to that much.
+		spread := 0.0
+		for v := 0; v < 256; v += 15 {
+			qc := q(color.NRGBA{uint8(v), uint8(v), uint8(v), 0xff})
+			for _, qv := range []uint8{qc.R, qc.G, qc.B} {
+				spread = math.Max(spread, 2*math.Abs(float64(qv)-float64(v)))
+			}
+		}
+
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				i := img.PixOffset(x, y)
+				t := ((bayer4[y%4][x%4]+0.5)/16 - 0.5) * spread
+
+				qc := q(color.NRGBA{
+					dither.Clamp(float64(img.Pix[i]) + t),
+					dither.Clamp(float64(img.Pix[i+1]) + t),
+					dither.Clamp(float64(img.Pix[i+2]) + t),
+					img.Pix[i+3],
+				})
+				img.Pix[i], img.Pix[i+1], img.Pix[i+2] = qc.R, qc.G, qc.B
+			}
+		}
+
+		return toRGBA(img), nil
+	}
+}
+
+// Gamma returns a filter that applies gamma correction to an image.
+// LED panels are linear, so a gamma of around 2.2 makes colors look
+// like they do on a regular screen.
+func Gamma(gamma float64) ImageFilter {
+	var table [256]uint8
+	for i := range table {
+		table[i] = dither.Clamp(math.Pow(float64(i)/255, gamma) * 255)
+	}
+
+	return func(input image.Image) (image.Image, error) {
+		img := toNRGBA(input)
+		for i := 0; i < len(img.Pix); i += 4 {
+			img.Pix[i] = table[img.Pix[i]]
+			img.Pix[i+1] = table[img.Pix[i+1]]
+			img.Pix[i+2] = table[img.Pix[i+2]]
+		}
+		return toRGBA(img), nil
+	}
+}
+
+// MaxBrightness returns a filter that dims every pixel brighter than
+// limit, which ranges from 0 to 1, keeping its hue.
+func MaxBrightness(limit float64) ImageFilter {
+	max := limit * 255
+
+	return func(input image.Image) (image.Image, error) {
+		img := toNRGBA(input)
+		for i := 0; i < len(img.Pix); i += 4 {
+			px := img.Pix[i : i+3 : i+3]
+
+			brightest := math.Max(float64(px[0]), math.Max(float64(px[1]), float64(px[2])))
+			if brightest <= max {
+				continue
+			}
+
+			scale := max / brightest
+			for j := range px {
+				px[j] = dither.Clamp(float64(px[j]) * scale)
+			}
+		}
+		return toRGBA(img), nil
+	}
+}
+
+// ParseFilter returns the filter described by spec, which is the name
+// of a filter followed by a colon and its argument:
+//
+//	gamma:2.2
+//	max-brightness:0.8
+//	floyd-steinberg:4        (4 bits per channel)
+//	floyd-steinberg:5,6,5    (bits per red, green and blue channel)
+//	bayer:#000,#f00,#fff     (a palette)
+func ParseFilter(spec string) (ImageFilter, error) {
+	name, arg, _ := strings.Cut(spec, ":")
+
+	switch name {
+	case "gamma", "max-brightness":
+		v, err := strconv.ParseFloat(arg, 64)
+		if err != nil || v <= 0 {
+			return nil, fmt.Errorf("%s: expected a positive number, found '%s'", name, arg)
+		}
+		if name == "gamma" {
+			return Gamma(v), nil
+		}
+		return MaxBrightness(v), nil
+
+	case "floyd-steinberg", "bayer":
+		q, err := parseQuantizer(arg)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		if name == "bayer" {
+			return Bayer(q), nil
+		}
+		return FloydSteinberg(q), nil
+
+	default:
+		return nil, fmt.Errorf("unknown filter '%s'", name)
+	}
+}
+
+// Parses either a bit depth, a bit depth per channel or a palette
+func parseQuantizer(arg string) (Quantizer, error) {
+	parts := strings.Split(arg, ",")
+
+	if strings.HasPrefix(arg, "#") {
+		p := color.Palette{}
+		for _, part := range parts {
+			c, err := render.ParseColor(part)
+			if err != nil {
+				return nil, err
+			}
+			p = append(p, c)
+		}
+		return Palette(p), nil
+	}
+
+	if len(parts) != 1 && len(parts) != 3 {
+		return nil, fmt.Errorf("expected a bit depth, 3 bit depths or a palette, found '%s'", arg)
+	}
+
+	bits := []int{}
+	for _, part := range parts {
+		b, err := strconv.Atoi(part)
+		if err != nil || b < 1 || b > 8 {
+			return nil, fmt.Errorf("bit depth must be between 1 and 8, found '%s'", part)
+		}
+		bits = append(bits, b)
+	}
+	if len(bits) == 1 {
+		bits = []int{bits[0], bits[0], bits[0]}
+	}
+
+	return BitDepth(bits[0], bits[1], bits[2]), nil
+}
+
+// Returns a copy of img that filters can modify in place. Its colors
+// aren't premultiplied, so that filters treat semi-transparent pixels
+// like opaque ones.
+func toNRGBA(img image.Image) *image.NRGBA {
+	b := img.Bounds()
+	nrgba := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(nrgba, nrgba.Bounds(), img, b.Min, draw.Src)
+	return nrgba
+}
+
+// Returns a copy of img in the format the encoders expect
+func toRGBA(img image.Image) *image.RGBA {
+	b := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, b.Min, draw.Src)
+	return rgba
+}
@@ -0,0 +1,182 @@
+package encode
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func uniformImage(w, h int, c color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(img, img.Bounds(), image.NewUniform(c), image.Point{}, draw.Src)
+	return img
+}
+
+// Counts the pixels of each color in img
+func colorCounts(img image.Image) map[color.RGBA]int {
+	counts := map[color.RGBA]int{}
+	for y := img.Bounds().Min.Y; y < img.Bounds().Max.Y; y++ {
+		for x := img.Bounds().Min.X; x < img.Bounds().Max.X; x++ {
+			counts[color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)]++
+		}
+	}
+	return counts
+}
+
+func TestBitDepth(t *testing.T) {
+	q := BitDepth(1, 2, 8)
+	assert.Equal(t, color.NRGBA{0xff, 0x55, 0x81, 0xff}, q(color.NRGBA{0x90, 0x60, 0x81, 0xff}))
+	assert.Equal(t, color.NRGBA{0, 0xaa, 0xfe, 0x80}, q(color.NRGBA{0x70, 0x90, 0xfe, 0x80}))
+}
+
+func TestPalette(t *testing.T) {
+	q := Palette(color.Palette{color.Black, color.White, color.RGBA{0xff, 0, 0, 0xff}})
+	assert.Equal(t, color.NRGBA{0xff, 0, 0, 0xff}, q(color.NRGBA{0xc0, 0x20, 0x10, 0xff}))
+	assert.Equal(t, color.NRGBA{0, 0, 0, 0xff}, q(color.NRGBA{0x20, 0x20, 0x20, 0xff}))
+}
+
+func TestFloydSteinberg(t *testing.T) {
+	gray := uniformImage(16, 16, color.RGBA{0x80, 0x80, 0x80, 0xff})
+
+	out, err := FloydSteinberg(BitDepth(1, 1, 1))(gray)
+	require.NoError(t, err)
+
+	// mid gray turns into an even mix of black and white
+	counts := colorCounts(out)
+	assert.Equal(t, 2, len(counts))
+	assert.InDelta(t, 128, counts[color.RGBA{0xff, 0xff, 0xff, 0xff}], 4)
+	assert.InDelta(t, 128, counts[color.RGBA{0, 0, 0, 0xff}], 4)
+
+	// the input is left alone
+	assert.Equal(t, 1, len(colorCounts(gray)))
+
+	// colors that can be shown as is are kept
+	white := uniformImage(4, 4, color.White)
+	out, err = FloydSteinberg(BitDepth(1, 1, 1))(white)
+	require.NoError(t, err)
+	assert.Equal(t, map[color.RGBA]int{{0xff, 0xff, 0xff, 0xff}: 16}, colorCounts(out))
+}
+
+func TestBayer(t *testing.T) {
+	gray := uniformImage(8, 8, color.RGBA{0x80, 0x80, 0x80, 0xff})
+
+	out, err := Bayer(Palette(color.Palette{color.Black, color.White}))(gray)
+	require.NoError(t, err)
+
+	counts := colorCounts(out)
+	assert.Equal(t, 32, counts[color.RGBA{0xff, 0xff, 0xff, 0xff}])
+	assert.Equal(t, 32, counts[color.RGBA{0, 0, 0, 0xff}])
+
+	// the pattern repeats every 4 pixels
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			assert.Equal(t, out.At(x, y), out.At(x+4, y+4))
+		}
+	}
+
+	// with all 8 bits, nothing is dithered
+	out, err = Bayer(BitDepth(8, 8, 8))(gray)
+	require.NoError(t, err)
+	assert.Equal(t, 1, len(colorCounts(out)))
+}
+
+func TestGamma(t *testing.T) {
+	img := uniformImage(2, 2, color.RGBA{0xff, 0x80, 0, 0xff})
+
+	out, err := Gamma(2.2)(img)
+	require.NoError(t, err)
+	assert.Equal(t, color.RGBA{0xff, 0x38, 0, 0xff}, out.At(1, 1))
+
+	out, err = Gamma(1)(img)
+	require.NoError(t, err)
+	assert.Equal(t, color.RGBA{0xff, 0x80, 0, 0xff}, out.At(1, 1))
+}
+
+func TestMaxBrightness(t *testing.T) {
+	img := uniformImage(2, 1, color.RGBA{0xff, 0x80, 0, 0xff})
+	img.Set(1, 0, color.RGBA{0x40, 0x20, 0x10, 0xff})
+
+	out, err := MaxBrightness(0.5)(img)
+	require.NoError(t, err)
+
+	// bright pixels are dimmed, keeping their hue
+	assert.Equal(t, color.RGBA{0x80, 0x40, 0, 0xff}, out.At(0, 0))
+
+	// dark pixels are left alone
+	assert.Equal(t, color.RGBA{0x40, 0x20, 0x10, 0xff}, out.At(1, 0))
+}
+
+func TestFiltersIgnoreAlpha(t *testing.T) {
+	// semi-transparent pixels are filtered by their color, not by how
+	// dark they look once premultiplied
+	img := image.NewNRGBA(image.Rect(0, 0, 1, 1))
+	img.Set(0, 0, color.NRGBA{0xff, 0xc0, 0, 0x80})
+
+	// premultiplying loses a little precision on the way out
+	assertColor := func(expected color.NRGBA, actual color.Color) {
+		c := color.NRGBAModel.Convert(actual).(color.NRGBA)
+		assert.InDelta(t, expected.R, c.R, 2)
+		assert.InDelta(t, expected.G, c.G, 2)
+		assert.InDelta(t, expected.B, c.B, 2)
+		assert.Equal(t, expected.A, c.A)
+	}
+
+	out, err := MaxBrightness(0.5)(img)
+	require.NoError(t, err)
+	assertColor(color.NRGBA{0x80, 0x60, 0, 0x80}, out.At(0, 0))
+
+	out, err = Gamma(2.2)(img)
+	require.NoError(t, err)
+	assertColor(color.NRGBA{0xff, 0x89, 0, 0x80}, out.At(0, 0))
+
+	out, err = FloydSteinberg(BitDepth(1, 1, 1))(img)
+	require.NoError(t, err)
+	assertColor(color.NRGBA{0xff, 0xff, 0, 0x80}, out.At(0, 0))
+}
+
+func TestParseFilter(t *testing.T) {
+	for _, spec := range []string{
+		"gamma:2.2",
+		"max-brightness:0.8",
+		"floyd-steinberg:4",
+		"floyd-steinberg:5,6,5",
+		"bayer:#000,#f00,#fff",
+	} {
+		f, err := ParseFilter(spec)
+		assert.NoError(t, err, spec)
+		assert.NotNil(t, f, spec)
+	}
+
+	for _, spec := range []string{
+		"",
+		"sharpen:1",
+		"gamma",
+		"gamma:-1",
+		"max-brightness:foo",
+		"floyd-steinberg:9",
+		"floyd-steinberg:5,6",
+		"bayer:#000,#zzz",
+	} {
+		_, err := ParseFilter(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestEncodeWithFilter(t *testing.T) {
+	gray := uniformImage(16, 16, color.RGBA{0x80, 0x80, 0x80, 0xff})
+
+	f, err := ParseFilter("floyd-steinberg:1")
+	require.NoError(t, err)
+
+	frames, err := ScreensFromImages(gray).Frames(f)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(frames))
+	assert.Equal(t, 2, len(colorCounts(frames[0])))
+
+	_, err = ScreensFromImages(gray).EncodeGIF(0, f)
+	assert.NoError(t, err)
+}
@@ -14,7 +14,7 @@ import (
 // Renders a screen to GIF. Optionally pass filters for postprocessing
 // each individual frame.
 func (s *Screens) EncodeGIF(maxDuration int, filters ...ImageFilter) ([]byte, error) {
-	images, err := s.render(filters...)
+	images, delays, err := s.timedFrames(maxDuration, filters...)
 	if err != nil {
 		return nil, err
 	}
@@ -25,31 +25,29 @@ func (s *Screens) EncodeGIF(maxDuration int, filters ...ImageFilter) ([]byte, er
 
 	g := &gif.GIF{}
 
-	remainingDuration := maxDuration
+	var prev *image.RGBA
 	for imIdx, im := range images {
 		imRGBA, ok := im.(*image.RGBA)
 		if !ok {
 			return nil, fmt.Errorf("image %d is %T, require RGBA", imIdx, im)
 		}
 
-		palette := quantize.MedianCutQuantizer{}.Quantize(make([]color.Color, 0, 256), im)
-		imPaletted := image.NewPaletted(imRGBA.Bounds(), palette)
-		draw.Draw(imPaletted, imRGBA.Bounds(), imRGBA, image.Point{0, 0}, draw.Src)
-
-		frameDelay := int(s.delay)
-		if maxDuration > 0 {
-			if frameDelay > remainingDuration {
-				frameDelay = remainingDuration
-			}
-			remainingDuration -= frameDelay
+		// Only the part that changed since the previous frame is
+		// encoded, and drawn on top of it.
+		bounds := imRGBA.Bounds()
+		if prev != nil {
+			bounds = changedBounds(prev, imRGBA)
 		}
+		prev = imRGBA
 
-		g.Image = append(g.Image, imPaletted)
-		g.Delay = append(g.Delay, frameDelay/10) // in 100ths of a second
+		sub := imRGBA.SubImage(bounds)
+		palette := quantize.MedianCutQuantizer{}.Quantize(make([]color.Color, 0, 256), sub)
+		imPaletted := image.NewPaletted(bounds, palette)
+		draw.Draw(imPaletted, bounds, imRGBA, bounds.Min, draw.Src)
 
-		if maxDuration > 0 && remainingDuration <= 0 {
-			break
-		}
+		g.Image = append(g.Image, imPaletted)
+		g.Delay = append(g.Delay, delays[imIdx]/10) // in 100ths of a second
+		g.Disposal = append(g.Disposal, gif.DisposalNone)
 	}
 
 	buf := &bytes.Buffer{}
@@ -60,3 +58,36 @@ func (s *Screens) EncodeGIF(maxDuration int, filters ...ImageFilter) ([]byte, er
 
 	return buf.Bytes(), nil
 }
+
+// changedBounds returns the smallest rectangle holding every pixel
+// that differs between a and b, which must have the same bounds. If
+// nothing changed, it's a single pixel, since GIF frames can't be
+// empty.
+func changedBounds(a, b *image.RGBA) image.Rectangle {
+	bounds := b.Bounds()
+	changed := image.Rectangle{}
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		rowA := a.Pix[a.PixOffset(bounds.Min.X, y):a.PixOffset(bounds.Max.X, y)]
+		rowB := b.Pix[b.PixOffset(bounds.Min.X, y):b.PixOffset(bounds.Max.X, y)]
+		if bytes.Equal(rowA, rowB) {
+			continue
+		}
+
+		minX, maxX := bounds.Max.X, bounds.Min.X
+		for x := 0; x < bounds.Dx(); x++ {
+			if !bytes.Equal(rowA[x*4:x*4+4], rowB[x*4:x*4+4]) {
+				if bounds.Min.X+x < minX {
+					minX = bounds.Min.X + x
+				}
+				maxX = bounds.Min.X + x + 1
+			}
+		}
+		changed = changed.Union(image.Rect(minX, y, maxX, y+1))
+	}
+
+	if changed.Empty() {
+		return image.Rectangle{bounds.Min, bounds.Min.Add(image.Point{1, 1})}
+	}
+	return changed
+}
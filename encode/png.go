@@ -0,0 +1,107 @@
+package encode
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"math"
+)
+
+// Renders every frame of a screen to a PNG of its own, for tools that
+// would rather not decode animations. Optionally pass filters for
+// postprocessing each individual frame.
+//
+// Along with the PNGs, it returns how long each frame is shown in
+// milliseconds. As with the other formats, identical consecutive
+// frames are merged.
+func (s *Screens) EncodePNGFrames(maxDuration int, filters ...ImageFilter) ([][]byte, []int, error) {
+	images, delays, err := s.timedFrames(maxDuration, filters...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	frames := make([][]byte, len(images))
+	for i, im := range images {
+		buf := &bytes.Buffer{}
+		if err := png.Encode(buf, im); err != nil {
+			return nil, nil, fmt.Errorf("encoding frame %d: %w", i, err)
+		}
+		frames[i] = buf.Bytes()
+	}
+
+	return frames, delays, nil
+}
+
+// SpriteSheet describes the layout of a sprite sheet made by
+// EncodeSpriteSheet.
+type SpriteSheet struct {
+	FrameWidth  int `json:"frame_width"`
+	FrameHeight int `json:"frame_height"`
+	Columns     int `json:"columns"`
+	Rows        int `json:"rows"`
+
+	// Duration is the total of all delays, in milliseconds
+	Duration int `json:"duration"`
+
+	Frames []SpriteFrame `json:"frames"`
+}
+
+// SpriteFrame is the position of a frame in a sprite sheet, and how
+// long it's shown in milliseconds.
+type SpriteFrame struct {
+	X     int `json:"x"`
+	Y     int `json:"y"`
+	Delay int `json:"delay"`
+}
+
+// Renders a screen to a single PNG, with the frames laid out left to
+// right and top to bottom in a grid. If columns is 0, the grid is
+// about as wide as it's tall. Optionally pass filters for
+// postprocessing each individual frame.
+//
+// Along with the PNG, it returns where every frame ended up.
+func (s *Screens) EncodeSpriteSheet(maxDuration int, columns int, filters ...ImageFilter) ([]byte, *SpriteSheet, error) {
+	images, delays, err := s.timedFrames(maxDuration, filters...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sheet := &SpriteSheet{Frames: []SpriteFrame{}}
+	if len(images) == 0 {
+		return []byte{}, sheet, nil
+	}
+
+	if columns <= 0 {
+		columns = int(math.Ceil(math.Sqrt(float64(len(images)))))
+	}
+	if columns > len(images) {
+		columns = len(images)
+	}
+
+	bounds := images[0].Bounds()
+	sheet.FrameWidth = bounds.Dx()
+	sheet.FrameHeight = bounds.Dy()
+	sheet.Columns = columns
+	sheet.Rows = (len(images) + columns - 1) / columns
+
+	out := image.NewRGBA(image.Rect(0, 0, sheet.Columns*sheet.FrameWidth, sheet.Rows*sheet.FrameHeight))
+	for i, im := range images {
+		x := (i % columns) * sheet.FrameWidth
+		y := (i / columns) * sheet.FrameHeight
+
+		dst := image.Rect(x, y, x+sheet.FrameWidth, y+sheet.FrameHeight)
+		draw.Draw(out, dst, im, im.Bounds().Min, draw.Src)
+
+		sheet.Frames = append(sheet.Frames, SpriteFrame{X: x, Y: y, Delay: delays[i]})
+		sheet.Duration += delays[i]
+	}
+
+	buf := &bytes.Buffer{}
+	if err := png.Encode(buf, out); err != nil {
+		return nil, nil, fmt.Errorf("encoding: %w", err)
+	}
+
+	return buf.Bytes(), sheet, nil
+}
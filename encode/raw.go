@@ -0,0 +1,106 @@
+package encode
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+)
+
+// RawFormat is the pixel format of a raw frame stream.
+type RawFormat uint8
+
+const (
+	// 16 bits per pixel: 5 bits red, 6 green and 5 blue, stored as a
+	// little endian uint16 with red in the top bits.
+	RawRGB565 RawFormat = 1
+
+	// 24 bits per pixel: one byte each of red, green and blue.
+	RawRGB888 RawFormat = 2
+)
+
+// RawMagic starts every raw frame stream.
+const RawMagic = "PXLT"
+
+// RawVersion is the version of the raw frame stream layout.
+const RawVersion = 1
+
+func (f RawFormat) String() string {
+	switch f {
+	case RawRGB565:
+		return "rgb565"
+	case RawRGB888:
+		return "rgb888"
+	}
+	return fmt.Sprintf("RawFormat(%d)", uint8(f))
+}
+
+// Renders a screen to a stream of raw frames, for displays that can't
+// decode images themselves. Optionally pass filters for postprocessing
+// each individual frame.
+//
+// The stream starts with a header, all numbers little endian:
+//
+//	magic       4 bytes, "PXLT"
+//	version     uint8, 1
+//	format      uint8, 1 for RGB565 and 2 for RGB888
+//	width       uint16
+//	height      uint16
+//	frame count uint16
+//
+// Each frame follows as a uint32 delay in milliseconds and then its
+// pixels, row by row from the top left. Transparent pixels are drawn
+// on black.
+func (s *Screens) EncodeRaw(maxDuration int, format RawFormat, filters ...ImageFilter) ([]byte, error) {
+	if format != RawRGB565 && format != RawRGB888 {
+		return nil, fmt.Errorf("unsupported raw format: %s", format)
+	}
+
+	images, delays, err := s.timedFrames(maxDuration, filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(images) > 0xffff {
+		return nil, fmt.Errorf("too many frames for raw stream: %d", len(images))
+	}
+
+	width, height := 0, 0
+	if len(images) > 0 {
+		width, height = images[0].Bounds().Dx(), images[0].Bounds().Dy()
+	}
+
+	buf := &bytes.Buffer{}
+	buf.WriteString(RawMagic)
+	buf.WriteByte(RawVersion)
+	buf.WriteByte(byte(format))
+	binary.Write(buf, binary.LittleEndian, uint16(width))
+	binary.Write(buf, binary.LittleEndian, uint16(height))
+	binary.Write(buf, binary.LittleEndian, uint16(len(images)))
+
+	for imIdx, im := range images {
+		imRGBA, ok := im.(*image.RGBA)
+		if !ok {
+			imRGBA = toRGBA(im)
+		}
+
+		binary.Write(buf, binary.LittleEndian, uint32(delays[imIdx]))
+
+		// pixels are premultiplied, which is the same as drawing
+		// them on black
+		bounds := imRGBA.Bounds()
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				c := imRGBA.RGBAAt(x, y)
+				if format == RawRGB888 {
+					buf.Write([]byte{c.R, c.G, c.B})
+					continue
+				}
+				px := uint16(c.R>>3)<<11 | uint16(c.G>>2)<<5 | uint16(c.B>>3)
+				buf.Write([]byte{byte(px), byte(px >> 8)})
+			}
+		}
+	}
+
+	return buf.Bytes(), nil
+}
@@ -12,7 +12,7 @@ import (
 // Renders a screen to WebP. Optionally pass filters for
 // postprocessing each individual frame.
 func (s *Screens) EncodeWebP(maxDuration int, filters ...ImageFilter) ([]byte, error) {
-	images, err := s.render(filters...)
+	images, delays, err := s.timedFrames(maxDuration, filters...)
 	if err != nil {
 		return nil, err
 	}
@@ -33,24 +33,13 @@ func (s *Screens) EncodeWebP(maxDuration int, filters ...ImageFilter) ([]byte, e
 	}
 	defer anim.Close()
 
-	remainingDuration := time.Duration(maxDuration) * time.Millisecond
-	for _, im := range images {
-		frameDuration := time.Duration(s.delay) * time.Millisecond
-
-		if maxDuration > 0 {
-			if frameDuration > remainingDuration {
-				frameDuration = remainingDuration
-			}
-			remainingDuration -= frameDuration
-		}
-
+	// Frames are added whole. The encoder finds the part of each one
+	// that changed since the previous frame, and only encodes that.
+	for i, im := range images {
+		frameDuration := time.Duration(delays[i]) * time.Millisecond
 		if err := anim.AddFrame(im, frameDuration); err != nil {
 			return nil, fmt.Errorf("%s: %w", "adding frame", err)
 		}
-
-		if maxDuration > 0 && remainingDuration <= 0 {
-			break
-		}
 	}
 
 	buf, err := anim.Assemble()
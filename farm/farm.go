@@ -0,0 +1,260 @@
+// Package farm renders many applets in parallel. Jobs pair an applet with a
+// config, and are run on a bounded pool of workers. Applets are compiled
+// once and reused by every job that renders them.
+package farm
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	goruntime "runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"tidbyt.dev/pixlet/encode"
+	"tidbyt.dev/pixlet/runtime"
+	"tidbyt.dev/pixlet/tools"
+)
+
+// Job is a single render of an applet.
+type Job struct {
+	// ID identifies the job in results, and names its output file.
+	ID string `json:"id" yaml:"id"`
+
+	// Path is the applet to render, either a single .star file or a
+	// directory.
+	Path string `json:"path" yaml:"path"`
+
+	// Config is passed to the applet's main function.
+	Config map[string]string `json:"config,omitempty" yaml:"config"`
+}
+
+// Result is the outcome of a single job.
+type Result struct {
+	Job Job
+
+	// Image is the encoded WebP or GIF.
+	Image []byte
+
+	// Frames is the number of frames rendered.
+	Frames int
+
+	// Load is the time spent compiling the applet. It's zero when a
+	// previously compiled applet was reused.
+	Load time.Duration
+
+	// Run is the time spent executing the applet.
+	Run time.Duration
+
+	// Encode is the time spent painting and encoding frames.
+	Encode time.Duration
+
+	Err error
+}
+
+// Option configures a Farm.
+type Option func(*Farm)
+
+// WithWorkers sets the number of jobs that are run in parallel. By default,
+// one job is run per CPU.
+func WithWorkers(n int) Option {
+	return func(f *Farm) {
+		f.workers = n
+	}
+}
+
+// WithTimeout sets the maximum time an applet may run for in a single job.
+func WithTimeout(d time.Duration) Option {
+	return func(f *Farm) {
+		f.timeout = d
+	}
+}
+
+// WithMaxDuration sets the maximum duration of encoded animations in
+// milliseconds, unless an applet requests its full animation is shown.
+func WithMaxDuration(ms int) Option {
+	return func(f *Farm) {
+		f.maxDuration = ms
+	}
+}
+
+// WithGIF encodes GIF instead of WebP.
+func WithGIF() Option {
+	return func(f *Farm) {
+		f.gif = true
+	}
+}
+
+// WithAppletOptions sets options used when compiling applets.
+func WithAppletOptions(opts ...runtime.AppletOption) Option {
+	return func(f *Farm) {
+		f.appletOpts = append(f.appletOpts, opts...)
+	}
+}
+
+// Farm runs jobs on a pool of workers.
+//
+// Applets share the process-wide cache and HTTP client configured with
+// runtime.InitCache and runtime.InitHTTP.
+type Farm struct {
+	workers     int
+	timeout     time.Duration
+	maxDuration int
+	gif         bool
+	appletOpts  []runtime.AppletOption
+
+	applets map[string]*compiledApplet
+	mutex   sync.Mutex
+}
+
+type compiledApplet struct {
+	once   sync.Once
+	applet *runtime.Applet
+	err    error
+}
+
+// New creates a Farm.
+func New(opts ...Option) *Farm {
+	f := &Farm{
+		workers: goruntime.NumCPU(),
+		applets: map[string]*compiledApplet{},
+	}
+
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	if f.workers < 1 {
+		f.workers = 1
+	}
+
+	return f
+}
+
+// Render runs all jobs, and returns their results in the same order. Errors
+// are reported per job, so that one failing applet doesn't affect others.
+func (f *Farm) Render(ctx context.Context, jobs []Job) []*Result {
+	results := make([]*Result, len(jobs))
+
+	var wg sync.WaitGroup
+	sem := make(chan bool, f.workers)
+	for i, job := range jobs {
+		wg.Add(1)
+		sem <- true
+
+		go func(i int, job Job) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			results[i] = f.renderJob(ctx, job)
+		}(i, job)
+	}
+
+	wg.Wait()
+	return results
+}
+
+func (f *Farm) renderJob(ctx context.Context, job Job) *Result {
+	result := &Result{Job: job}
+
+	start := time.Now()
+	applet, loaded, err := f.applet(job.Path)
+	if loaded {
+		result.Load = time.Since(start)
+	}
+	if err != nil {
+		result.Err = fmt.Errorf("failed to load applet: %w", err)
+		return result
+	}
+
+	if f.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(
+			ctx,
+			f.timeout,
+			fmt.Errorf("timeout after %s", f.timeout),
+		)
+		defer cancel()
+	}
+
+	start = time.Now()
+	roots, err := applet.RunWithConfig(ctx, job.Config)
+	result.Run = time.Since(start)
+	if err != nil {
+		result.Err = fmt.Errorf("error running script: %w", err)
+		return result
+	}
+
+	start = time.Now()
+	screens := encode.ScreensFromRoots(roots)
+
+	maxDuration := f.maxDuration
+	if screens.ShowFullAnimation {
+		maxDuration = 0
+	}
+
+	if f.gif {
+		result.Image, err = screens.EncodeGIF(maxDuration)
+	} else {
+		result.Image, err = screens.EncodeWebP(maxDuration)
+	}
+	result.Encode = time.Since(start)
+	if err != nil {
+		result.Err = fmt.Errorf("error rendering: %w", err)
+		return result
+	}
+
+	frames, _ := screens.Frames()
+	result.Frames = len(frames)
+
+	return result
+}
+
+// applet returns the compiled applet at path, compiling it if this is the
+// first job to use it. Jobs that need the same applet at the same time wait
+// for a single compilation. loaded is true if this call compiled it.
+func (f *Farm) applet(path string) (applet *runtime.Applet, loaded bool, err error) {
+	key := filepath.Clean(path)
+
+	f.mutex.Lock()
+	c, ok := f.applets[key]
+	if !ok {
+		c = &compiledApplet{}
+		f.applets[key] = c
+	}
+	f.mutex.Unlock()
+
+	c.once.Do(func() {
+		loaded = true
+		c.applet, c.err = LoadApplet(path, f.appletOpts...)
+	})
+
+	return c.applet, loaded, c.err
+}
+
+// LoadApplet compiles the applet at path, which is either a single .star
+// file or a directory.
+func LoadApplet(path string, opts ...runtime.AppletOption) (*runtime.Applet, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+
+	var fsys fs.FS
+	if info.IsDir() {
+		fsys = os.DirFS(path)
+	} else {
+		if !strings.HasSuffix(path, ".star") {
+			return nil, fmt.Errorf("script file must have suffix .star: %s", path)
+		}
+
+		fsys = tools.NewSingleFileFS(path)
+	}
+
+	return runtime.NewAppletFromFS(filepath.Base(path), fsys, opts...)
+}
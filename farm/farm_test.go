@@ -0,0 +1,179 @@
+package farm
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.starlark.net/starlark"
+
+	"tidbyt.dev/pixlet/runtime"
+)
+
+var counterSource = `
+load("render.star", "render")
+
+def main(config):
+    n = int(config.get("frames", "1"))
+    return render.Root(
+        child = render.Animation(
+            children = [render.Box(width = i + 1, height = 1) for i in range(n)],
+        ),
+    )
+`
+
+var slowSource = `
+load("render.star", "render")
+
+def main(config):
+    for i in range(1000000000):
+        pass
+    return render.Root(child = render.Box())
+`
+
+func writeApp(t *testing.T, dir, name, src string) string {
+	// every app gets its own directory, since single file apps can't
+	// share one
+	dir = filepath.Join(dir, strings.TrimSuffix(name, ".star"))
+	require.NoError(t, os.Mkdir(dir, 0755))
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(src), 0644))
+	return path
+}
+
+func TestFarmRender(t *testing.T) {
+	dir := t.TempDir()
+	counter := writeApp(t, dir, "counter.star", counterSource)
+	broken := writeApp(t, dir, "broken.star", "def main(:")
+
+	var loads atomic.Int32
+	f := New(
+		WithWorkers(3),
+		WithAppletOptions(func(a *runtime.Applet) error {
+			loads.Add(1)
+			return nil
+		}),
+	)
+
+	var jobs []Job
+	for i := 1; i <= 10; i++ {
+		jobs = append(jobs, Job{
+			ID:     fmt.Sprintf("counter-%d", i),
+			Path:   counter,
+			Config: map[string]string{"frames": fmt.Sprint(i)},
+		})
+	}
+	jobs = append(jobs, Job{ID: "broken", Path: broken})
+
+	results := f.Render(context.Background(), jobs)
+	require.Len(t, results, 11)
+
+	for i, r := range results[:10] {
+		assert.Equal(t, jobs[i].ID, r.Job.ID)
+		assert.NoError(t, r.Err)
+		assert.Equal(t, i+1, r.Frames)
+		assert.NotEmpty(t, r.Image)
+	}
+
+	assert.ErrorContains(t, results[10].Err, "failed to load applet")
+
+	// each applet was only compiled once, and only one job paid for it
+	assert.Equal(t, int32(2), loads.Load())
+	compiled := 0
+	for _, r := range results[:10] {
+		if r.Load > 0 {
+			compiled++
+		}
+	}
+	assert.Equal(t, 1, compiled)
+}
+
+func TestFarmTimeout(t *testing.T) {
+	dir := t.TempDir()
+	slow := writeApp(t, dir, "slow.star", slowSource)
+	fast := writeApp(t, dir, "fast.star", counterSource)
+
+	f := New(WithTimeout(100 * time.Millisecond))
+	results := f.Render(context.Background(), []Job{
+		{ID: "slow", Path: slow},
+		{ID: "fast", Path: fast},
+	})
+
+	assert.ErrorContains(t, results[0].Err, "timeout after 100ms")
+	assert.NoError(t, results[1].Err)
+}
+
+func TestFarmSharedCache(t *testing.T) {
+	src := `
+load("cache.star", "cache")
+load("render.star", "render")
+
+def main(config):
+    n = int(cache.get("n") or "0") + 1
+    cache.set("n", str(n))
+    print(n)
+    return render.Root(child = render.Box())
+`
+	app := writeApp(t, t.TempDir(), "cached.star", src)
+
+	runtime.InitCache(runtime.NewInMemoryCache())
+	defer runtime.InitCache(nil)
+
+	var printed []string
+	f := New(
+		WithWorkers(1),
+		WithAppletOptions(runtime.WithPrintFunc(func(_ *starlark.Thread, msg string) {
+			printed = append(printed, msg)
+		})),
+	)
+
+	results := f.Render(context.Background(), []Job{
+		{ID: "a", Path: app},
+		{ID: "b", Path: app},
+		{ID: "c", Path: app},
+	})
+	for _, r := range results {
+		require.NoError(t, r.Err)
+	}
+
+	// every job sees what earlier jobs cached
+	assert.Equal(t, []string{"1", "2", "3"}, printed)
+}
+
+func TestLoadJobs(t *testing.T) {
+	jobs, err := LoadJobs(strings.NewReader(`
+jobs:
+  - id: nyc
+    path: apps/clock
+    config:
+      timezone: America/New_York
+  - path: /abs/hello.star
+`), "/base")
+	require.NoError(t, err)
+	assert.Equal(t, []Job{
+		{ID: "nyc", Path: "/base/apps/clock", Config: map[string]string{"timezone": "America/New_York"}},
+		{ID: "hello-1", Path: "/abs/hello.star"},
+	}, jobs)
+
+	_, err = LoadJobs(strings.NewReader(`
+jobs:
+  - id: a
+    path: x.star
+  - id: a
+    path: y.star
+`), "")
+	assert.ErrorContains(t, err, "duplicate")
+
+	_, err = LoadJobs(strings.NewReader(`
+jobs:
+  - id: a
+`), "")
+	assert.ErrorContains(t, err, "no path")
+}
@@ -0,0 +1,66 @@
+package farm
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var validJobID = regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
+
+type jobsFile struct {
+	Jobs []Job `json:"jobs" yaml:"jobs"`
+}
+
+// LoadJobs reads a list of jobs from an io.Reader, with the most common
+// reader being a file from os.Open. The file is YAML (or JSON), in the form:
+//
+//	jobs:
+//	  - id: clock-nyc
+//	    path: examples/clock
+//	    config:
+//	      timezone: America/New_York
+//
+// Relative paths are resolved against baseDir. Jobs without an ID are named
+// after their applet and position in the list.
+func LoadJobs(r io.Reader, baseDir string) ([]Job, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("could not read jobs: %w", err)
+	}
+
+	f := &jobsFile{}
+	if err := yaml.Unmarshal(b, f); err != nil {
+		return nil, fmt.Errorf("could not unmarshal jobs: %w", err)
+	}
+
+	ids := map[string]bool{}
+	for i := range f.Jobs {
+		job := &f.Jobs[i]
+
+		if job.Path == "" {
+			return nil, fmt.Errorf("job %d has no path", i)
+		}
+		if !filepath.IsAbs(job.Path) {
+			job.Path = filepath.Join(baseDir, job.Path)
+		}
+
+		if job.ID == "" {
+			name := strings.TrimSuffix(filepath.Base(job.Path), ".star")
+			job.ID = fmt.Sprintf("%s-%d", name, i)
+		}
+		if !validJobID.MatchString(job.ID) {
+			return nil, fmt.Errorf("job ID %q may only contain letters, digits, '-', '_' and '.'", job.ID)
+		}
+		if ids[job.ID] {
+			return nil, fmt.Errorf("duplicate job ID %q", job.ID)
+		}
+		ids[job.ID] = true
+	}
+
+	return f.Jobs, nil
+}
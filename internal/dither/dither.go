@@ -0,0 +1,54 @@
+// Package dither reduces the colors of images, for the Image widget
+// and the filters of the encode package alike.
+package dither
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// FloydSteinberg reduces the colors of img in place with quantize,
+// spreading the error of each pixel to its neighbors. Alpha is left
+// alone.
+func FloydSteinberg(img *image.NRGBA, quantize func(color.NRGBA) color.NRGBA) {
+	b := img.Bounds()
+	width, height := b.Dx(), b.Dy()
+
+	// error carried over to the current and next row, per channel
+	cur := make([]float64, (width+2)*3)
+	next := make([]float64, (width+2)*3)
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			i := img.PixOffset(b.Min.X+x, b.Min.Y+y)
+			pix := img.Pix[i : i+4 : i+4]
+
+			var v [3]float64
+			for c := 0; c < 3; c++ {
+				v[c] = float64(pix[c]) + cur[(x+1)*3+c]
+			}
+
+			qc := quantize(color.NRGBA{Clamp(v[0]), Clamp(v[1]), Clamp(v[2]), pix[3]})
+			pix[0], pix[1], pix[2] = qc.R, qc.G, qc.B
+
+			for c, qv := range []uint8{qc.R, qc.G, qc.B} {
+				e := v[c] - float64(qv)
+				cur[(x+2)*3+c] += e * 7 / 16
+				next[x*3+c] += e * 3 / 16
+				next[(x+1)*3+c] += e * 5 / 16
+				next[(x+2)*3+c] += e * 1 / 16
+			}
+		}
+
+		cur, next = next, cur
+		for i := range next {
+			next[i] = 0
+		}
+	}
+}
+
+// Clamp rounds v to the closest value a uint8 can hold.
+func Clamp(v float64) uint8 {
+	return uint8(math.Round(math.Max(0, math.Min(255, v))))
+}
@@ -31,6 +31,10 @@ func init() {
 	rootCmd.AddCommand(cmd.LintCmd)
 	rootCmd.AddCommand(cmd.CheckCmd)
 	rootCmd.AddCommand(cmd.SetAuthCmd)
+	rootCmd.AddCommand(cmd.SnapshotCmd)
+	rootCmd.AddCommand(cmd.TestCmd)
+	rootCmd.AddCommand(cmd.RenderManyCmd)
+	rootCmd.AddCommand(cmd.GlyphsCmd)
 	rootCmd.AddCommand(community.CommunityCmd)
 }
 
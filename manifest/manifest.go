@@ -32,11 +32,29 @@ type Manifest struct {
 	// "Max Timkovich"
 	Author string `json:"author" yaml:"author"`
 
+	// Fonts registers font files that ship with the applet under names,
+	// which can then be used as the font of text widgets.
+	Fonts []Font `json:"fonts,omitempty" yaml:"fonts,omitempty"`
+
 	// Source is the starlark source code for this applet using the go `embed`
 	// module.
 	Source []byte `json:"-" yaml:"-"`
 }
 
+// Font is a font file that ships with an applet.
+type Font struct {
+	// Name is what the applet passes as the font of text widgets.
+	Name string `json:"name" yaml:"name"`
+
+	// Path is the location of the font file, relative to the applet's
+	// directory. BDF, PCF, TrueType and OpenType fonts are supported.
+	Path string `json:"path" yaml:"path"`
+
+	// Size is the size in pixels to rasterize scalable fonts at. It's
+	// ignored for bitmap fonts.
+	Size float64 `json:"size,omitempty" yaml:"size,omitempty"`
+}
+
 // LoadManifest reads a manifest from an io.Reader, with the most common reader
 // being a file from os.Open. It returns a manifest or an error if it could not
 // be parsed.
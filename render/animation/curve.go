@@ -31,6 +31,10 @@ func (lc LinearCurve) Transform(t float64) float64 {
 	return t
 }
 
+func (lc LinearCurve) String() string {
+	return "linear"
+}
+
 // Bezier curve defined by a, b, c and d.
 type CubicBezierCurve struct {
 	a, b, c, d float64
@@ -56,6 +60,14 @@ func (cb CubicBezierCurve) Transform(t float64) float64 {
 	return math.NaN()
 }
 
+// String returns the curve in the form accepted by ParseCurve.
+func (cb CubicBezierCurve) String() string {
+	f := func(v float64) string {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprintf("cubic-bezier(%s, %s, %s, %s)", f(cb.a), f(cb.b), f(cb.c), f(cb.d))
+}
+
 func (cb CubicBezierCurve) computeBezier(t, e, f float64) float64 {
 	return 3*e*(1-t)*(1-t)*t + 3*f*(1-t)*t*t + t*t*t
 }
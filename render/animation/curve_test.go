@@ -1,6 +1,7 @@
 package animation
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -59,3 +60,13 @@ func TestParseCurve(t *testing.T) {
 	ParseAndAssertCurve(t, "cubic-bezier(0.68, -0.6, 0.32, 1.6)", CubicBezierCurve{0.68, -0.6, 0.32, 1.6})
 	ParseAndAssertCurve(t, "cubic-bezier(.68, -.6, .32, 1.6)", CubicBezierCurve{0.68, -0.6, 0.32, 1.6})
 }
+
+func TestCurveString(t *testing.T) {
+	for _, c := range []Curve{LinearCurve{}, EaseIn, EaseOut, EaseInOut, CubicBezierCurve{-0.5, 0.25, 1.5, 0}} {
+		parsed, err := ParseCurve(c.(fmt.Stringer).String())
+		assert.NoError(t, err)
+		assert.Equal(t, c, parsed)
+	}
+
+	assert.Equal(t, "cubic-bezier(0.65, 0, 0.35, 1)", EaseInOut.String())
+}
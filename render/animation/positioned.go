@@ -18,10 +18,10 @@ import (
 // DOC(XEnd): Horizontal end coordinate
 // DOC(YStart): Vertical start coordinate
 // DOC(YEnd): Vertical end coordinate
-// DOC(Duration): Duration of animation in frames
+// DOC(Duration): Duration of animation in frames, or milliseconds if the root is time based
 // DOC(Curve): Easing curve to use, default is 'linear'
-// DOC(Delay): Delay before animation in frames
-// DOC(Hold): Delay after animation in frames
+// DOC(Delay): Delay before animation in frames, or milliseconds if the root is time based
+// DOC(Hold): Delay after animation in frames, or milliseconds if the root is time based
 //
 type AnimatedPositioned struct {
 	render.Widget
@@ -34,6 +34,19 @@ type AnimatedPositioned struct {
 	Curve    Curve         `starlark:"curve,required"`
 	Delay    int           `starlark:"delay"`
 	Hold     int           `starlark:"hold"`
+
+	frameDelay int
+}
+
+func (o *AnimatedPositioned) SetFrameDelay(delay int) {
+	o.frameDelay = delay
+}
+
+// frames returns the delay, duration and hold in frames
+func (o AnimatedPositioned) frames() (int, int, int) {
+	return render.DurationFrames(o.Delay, o.frameDelay),
+		render.DurationFrames(o.Duration, o.frameDelay),
+		render.DurationFrames(o.Hold, o.frameDelay)
 }
 
 func (o AnimatedPositioned) PaintBounds(bounds image.Rectangle, frameIdx int) image.Rectangle {
@@ -43,12 +56,13 @@ func (o AnimatedPositioned) PaintBounds(bounds image.Rectangle, frameIdx int) im
 func (o AnimatedPositioned) Paint(dc *gg.Context, bounds image.Rectangle, frameIdx int) {
 	var position float64
 
-	if frameIdx < o.Delay {
+	delay, duration, _ := o.frames()
+	if frameIdx < delay {
 		position = 0.0
-	} else if frameIdx >= o.Delay+o.Duration {
+	} else if frameIdx >= delay+duration {
 		position = 0.9999999999
 	} else {
-		position = o.Curve.Transform(float64(frameIdx-o.Delay) / float64(o.Duration))
+		position = o.Curve.Transform(float64(frameIdx-delay) / float64(duration))
 	}
 
 	dx := 1
@@ -73,5 +87,6 @@ func (o AnimatedPositioned) Paint(dc *gg.Context, bounds image.Rectangle, frameI
 }
 
 func (o AnimatedPositioned) FrameCount() int {
-	return o.Duration + o.Delay + o.Hold
+	delay, duration, hold := o.frames()
+	return duration + delay + hold
 }
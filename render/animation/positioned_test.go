@@ -391,3 +391,41 @@ func TestPositionedChildAnimation(t *testing.T) {
 		"..........",
 	}, im))
 }
+
+func TestPositionedTimeBased(t *testing.T) {
+	o := &AnimatedPositioned{
+		Child: render.Box{
+			Width:  1,
+			Height: 2,
+			Color:  color.RGBA{0x00, 0xff, 0x00, 0xff},
+		},
+		Duration: 250,
+		XStart:   0,
+		XEnd:     4,
+		Delay:    150,
+		Hold:     100,
+		Curve:    LinearCurve{},
+	}
+
+	// at 50ms per frame, this is the same as TestPositionedDelayAndHold
+	root := render.Root{Child: o, Delay: 50, TimeBased: true}
+	assert.NoError(t, root.Init())
+	assert.Equal(t, 10, o.FrameCount())
+
+	im := render.PaintWidget(o, image.Rect(0, 0, 5, 2), 5)
+	assert.Equal(t, nil, render.CheckImage([]string{
+		"..g..",
+		"..g..",
+	}, im))
+
+	// at 25ms per frame, it takes twice the frames to get there
+	root.Delay = 25
+	assert.NoError(t, root.Init())
+	assert.Equal(t, 20, o.FrameCount())
+
+	im = render.PaintWidget(o, image.Rect(0, 0, 5, 2), 10)
+	assert.Equal(t, nil, render.CheckImage([]string{
+		"..g..",
+		"..g..",
+	}, im))
+}
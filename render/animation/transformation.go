@@ -130,8 +130,8 @@ func findKeyframes(arr []Keyframe, p float64) (Keyframe, Keyframe, error) {
 //
 // DOC(Child): Widget to animate
 // DOC(Keyframes): List of animation keyframes
-// DOC(Duration): Duration of animation (in frames)
-// DOC(Delay): Duration to wait before animation (in frames)
+// DOC(Duration): Duration of animation (in frames, or milliseconds if the root is time based)
+// DOC(Delay): Duration to wait before animation (in frames, or milliseconds if the root is time based)
 // DOC(Width): Width of the animation canvas
 // DOC(Height): Height of the animation canvas
 // DOC(Origin): Origin for transforms, default is '50%, 50%'
@@ -175,6 +175,8 @@ type Transformation struct {
 	FillMode     FillMode      `starlark:"fill_mode"`
 	Rounding     Rounding      `starlark:"rounding"`
 	WaitForChild bool          `starlark:"wait_for_child"`
+
+	frameDelay int
 }
 
 func (self *Transformation) Init() error {
@@ -183,8 +185,17 @@ func (self *Transformation) Init() error {
 	return nil
 }
 
+func (self *Transformation) SetFrameDelay(delay int) {
+	self.frameDelay = delay
+}
+
+// frames returns the delay and duration in frames
+func (self *Transformation) frames() (int, int) {
+	return render.DurationFrames(self.Delay, self.frameDelay), render.DurationFrames(self.Duration, self.frameDelay)
+}
+
 func (self *Transformation) FrameCount() int {
-	fc := self.Direction.FrameCount(self.Delay, self.Duration)
+	fc := self.Direction.FrameCount(self.frames())
 	cfc := self.Child.FrameCount()
 
 	if self.WaitForChild && cfc > fc {
@@ -216,9 +227,10 @@ func (self *Transformation) Paint(dc *gg.Context, bounds image.Rectangle, frameI
 	origin := self.Origin.Transform(cb)
 
 	// Calculate the overall animation progress.
+	delay, duration := self.frames()
 	progress := self.Direction.Progress(
-		self.Delay,
-		self.Duration,
+		delay,
+		duration,
 		self.FillMode.Value(),
 		frameIdx,
 	)
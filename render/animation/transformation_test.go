@@ -410,3 +410,55 @@ func TestTransformationAll(t *testing.T) {
 		"..⁘◎○.░▒⎕",
 	}, im))
 }
+
+func TestTransformationTimeBased(t *testing.T) {
+	transformation := func(duration, delay int) *Transformation {
+		return &Transformation{
+			Child: render.Box{Width: 1, Height: 1, Color: color.RGBA{0xff, 0, 0, 0xff}},
+			Keyframes: processKeyframes([]Keyframe{
+				{
+					Percentage: Percentage{0.0},
+					Curve:      LinearCurve{},
+					Transforms: []Transform{Translate{Vec2f{X: 0.0, Y: 0.0}}},
+				},
+				{
+					Percentage: Percentage{1.0},
+					Curve:      LinearCurve{},
+					Transforms: []Transform{Translate{Vec2f{X: 4.0, Y: 0.0}}},
+				},
+			}),
+			Duration:  duration,
+			Delay:     delay,
+			Width:     5,
+			Height:    1,
+			Direction: DefaultDirection,
+			FillMode:  DefaultFillMode,
+			Rounding:  DefaultRounding,
+		}
+	}
+
+	frames := transformation(8, 2)
+
+	// 400ms at 50ms per frame is 8 frames, after a delay of 2
+	timed := transformation(400, 100)
+	root := render.Root{Child: timed, Delay: 50, TimeBased: true}
+	assert.NoError(t, root.Init())
+
+	assert.Equal(t, frames.FrameCount(), timed.FrameCount())
+	for i := 0; i < frames.FrameCount(); i++ {
+		assert.Equal(
+			t,
+			render.PaintWidget(frames, image.Rect(0, 0, 5, 1), i),
+			render.PaintWidget(timed, image.Rect(0, 0, 5, 1), i),
+			"frame %d", i,
+		)
+	}
+
+	// at 100ms per frame, it's over in half the frames
+	root.Delay = 100
+	assert.NoError(t, root.Init())
+	assert.Equal(t, 6, timed.FrameCount())
+	assert.Equal(t, nil, render.CheckImage([]string{"r...."}, render.PaintWidget(timed, image.Rect(0, 0, 5, 1), 1)))
+	assert.Equal(t, nil, render.CheckImage([]string{".r..."}, render.PaintWidget(timed, image.Rect(0, 0, 5, 1), 2)))
+	assert.Equal(t, nil, render.CheckImage([]string{"....r"}, render.PaintWidget(timed, image.Rect(0, 0, 5, 1), 4)))
+}
@@ -0,0 +1,189 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/tidbyt/gg"
+)
+
+// BarChart draws a bar chart. Each entry in `data` is either a single
+// number, or a list of numbers drawn side by side as a group of bars.
+// With `stacked`, the numbers of a group are instead stacked on top of
+// each other in a single bar, positive ones going up and negative ones
+// going down.
+//
+// The bars of a series, i.e. the n:th number of every group, are drawn
+// in the n:th color of `colors`.
+//
+// Bars grow from 0, and the range of values spanned by the chart can
+// be set with `lim`. By default, it fits all bars.
+//
+// DOC(Data): A list of numbers, or of lists of numbers
+// DOC(Width): Limits BarChart width
+// DOC(Height): Limits BarChart height
+// DOC(Colors): List of colors, one per series, default is '#fff'
+// DOC(Horizontal): Draw bars growing to the right, instead of upwards
+// DOC(Stacked): Stack the bars of each group instead of drawing them side by side
+// DOC(Spacing): Pixels between groups of bars
+// DOC(Lim): Limit values to a range
+//
+// EXAMPLE BEGIN
+// render.BarChart(
+//   data = [
+//     (3, 1),
+//     (5, 2),
+//     (4, 4),
+//     (7, 3),
+//     (6, 5),
+//   ],
+//   width = 64,
+//   height = 32,
+//   colors = ["#0f0", "#f80"],
+//   spacing = 2,
+// )
+// EXAMPLE END
+type BarChart struct {
+	Widget
+
+	Data       [][]float64   `starlark:"data,required"`
+	Width      int           `starlark:"width,required"`
+	Height     int           `starlark:"height,required"`
+	Colors     []color.Color `starlark:"colors"`
+	Horizontal bool          `starlark:"horizontal"`
+	Stacked    bool          `starlark:"stacked"`
+	Spacing    int           `starlark:"spacing"`
+	Lim        [2]float64    `starlark:"lim"`
+}
+
+// Computes the range of values to draw
+func (b BarChart) limits() (float64, float64) {
+	lo, hi := 0.0, 0.0
+	for _, group := range b.Data {
+		pos, neg := 0.0, 0.0
+		for _, v := range group {
+			if math.IsNaN(v) {
+				continue
+			}
+
+			if b.Stacked {
+				if v >= 0 {
+					pos += v
+				} else {
+					neg += v
+				}
+			} else {
+				pos = math.Max(pos, v)
+				neg = math.Min(neg, v)
+			}
+		}
+		hi = math.Max(hi, pos)
+		lo = math.Min(lo, neg)
+	}
+
+	// an empty range is what you get when lim isn't set at all
+	if b.Lim[0] != b.Lim[1] {
+		if !math.IsNaN(b.Lim[0]) {
+			lo = b.Lim[0]
+		}
+		if !math.IsNaN(b.Lim[1]) {
+			hi = b.Lim[1]
+		}
+	}
+
+	if hi <= lo {
+		hi = lo + 1
+	}
+
+	return lo, hi
+}
+
+func (b BarChart) PaintBounds(bounds image.Rectangle, frameIdx int) image.Rectangle {
+	return image.Rect(0, 0, b.Width, b.Height)
+}
+
+func (b BarChart) Paint(dc *gg.Context, bounds image.Rectangle, frameIdx int) {
+	if len(b.Data) == 0 {
+		return
+	}
+
+	lo, hi := b.limits()
+
+	// the axis bars are laid out along, and the one they grow along
+	main, length := b.Width, b.Height
+	if b.Horizontal {
+		main, length = b.Height, b.Width
+	}
+
+	scale := func(v float64) int {
+		v = math.Max(lo, math.Min(hi, v))
+		return int(math.Round((v - lo) / (hi - lo) * float64(length)))
+	}
+
+	// draws a bar covering [start, end) along the main axis, and the
+	// values from v0 to v1
+	bar := func(start, end int, v0, v1 float64, c color.Color) {
+		p0, p1 := scale(v0), scale(v1)
+		if p1 <= p0 {
+			return
+		}
+
+		dc.SetColor(c)
+		if b.Horizontal {
+			dc.DrawRectangle(float64(p0), float64(start), float64(p1-p0), float64(end-start))
+		} else {
+			dc.DrawRectangle(float64(start), float64(b.Height-p1), float64(end-start), float64(p1-p0))
+		}
+		dc.Fill()
+	}
+
+	series := 1
+	if !b.Stacked {
+		for _, group := range b.Data {
+			if len(group) > series {
+				series = len(group)
+			}
+		}
+	}
+
+	// every bar is equally wide. pixels left over are left empty.
+	barWidth := (main - b.Spacing*(len(b.Data)-1)) / len(b.Data) / series
+	if barWidth < 1 {
+		barWidth = 1
+	}
+	groupWidth := barWidth * series
+
+	for i, group := range b.Data {
+		start := i * (groupWidth + b.Spacing)
+
+		pos, neg := 0.0, 0.0
+		for j, v := range group {
+			if math.IsNaN(v) {
+				continue
+			}
+
+			var c color.Color = DefaultPlotColor
+			if len(b.Colors) > 0 {
+				c = b.Colors[j%len(b.Colors)]
+			}
+
+			if b.Stacked {
+				if v >= 0 {
+					bar(start, start+barWidth, pos, pos+v, c)
+					pos += v
+				} else {
+					bar(start, start+barWidth, neg+v, neg, c)
+					neg += v
+				}
+			} else {
+				x := start + j*barWidth
+				bar(x, x+barWidth, math.Min(0, v), math.Max(0, v), c)
+			}
+		}
+	}
+}
+
+func (b BarChart) FrameCount() int {
+	return 1
+}
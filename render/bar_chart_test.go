@@ -0,0 +1,122 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBarChartLimits(t *testing.T) {
+	b := BarChart{Data: [][]float64{{1, 2}, {-1, 3}}, Lim: Empty}
+
+	check := func(lo, hi float64) {
+		a, b := b.limits()
+		assert.Equal(t, lo, a)
+		assert.Equal(t, hi, b)
+	}
+
+	// Without limits, bars grow from 0 to the extreme values
+	check(-1, 3)
+
+	// Stacked bars extend to the sums of their values
+	b.Stacked = true
+	check(-1, 3)
+	b.Data = [][]float64{{1, 2}, {-1, -2, 1}}
+	check(-3, 3)
+
+	// Limits override either end
+	b.Lim = [2]float64{-1, Empty[1]}
+	check(-1, 3)
+	b.Lim = [2]float64{Empty[0], 10}
+	check(-3, 10)
+
+	// Zero value is the same as not setting any
+	b.Lim = [2]float64{}
+	check(-3, 3)
+
+	// Nonsensical limits are made sensical
+	b.Lim = [2]float64{5, 2}
+	check(5, 6)
+}
+
+func TestBarChart(t *testing.T) {
+	b := BarChart{
+		Data:    [][]float64{{1}, {2}, {3}},
+		Width:   5,
+		Height:  3,
+		Spacing: 1,
+		Lim:     Empty,
+	}
+	im := PaintWidget(b, image.Rect(0, 0, 100, 100), 0)
+	assert.Equal(t, nil, checkImage([]string{
+		"....w",
+		"..w.w",
+		"w.w.w",
+	}, im))
+
+	// Values outside the limits are cut off
+	b.Lim = [2]float64{0, 2}
+	im = PaintWidget(b, image.Rect(0, 0, 100, 100), 0)
+	assert.Equal(t, nil, checkImage([]string{
+		"..w.w",
+		"w.w.w",
+		"w.w.w",
+	}, im))
+}
+
+func TestBarChartGrouped(t *testing.T) {
+	b := BarChart{
+		Data: [][]float64{{1, 2}, {2, 1}},
+		Colors: []color.Color{
+			color.RGBA{0xff, 0, 0, 0xff},
+			color.RGBA{0, 0xff, 0, 0xff},
+		},
+		Width:  4,
+		Height: 2,
+		Lim:    Empty,
+	}
+	im := PaintWidget(b, image.Rect(0, 0, 100, 100), 0)
+	assert.Equal(t, nil, checkImage([]string{
+		".gr.",
+		"rgrg",
+	}, im))
+}
+
+func TestBarChartStacked(t *testing.T) {
+	b := BarChart{
+		Data: [][]float64{{1, 2}, {-1, 1}},
+		Colors: []color.Color{
+			color.RGBA{0xff, 0, 0, 0xff},
+			color.RGBA{0, 0xff, 0, 0xff},
+		},
+		Width:   2,
+		Height:  4,
+		Stacked: true,
+		Lim:     Empty,
+	}
+	im := PaintWidget(b, image.Rect(0, 0, 100, 100), 0)
+	assert.Equal(t, nil, checkImage([]string{
+		"g.",
+		"g.",
+		"rg",
+		".r",
+	}, im))
+}
+
+func TestBarChartHorizontal(t *testing.T) {
+	b := BarChart{
+		Data:       [][]float64{{1}, {2}, {4}},
+		Width:      4,
+		Height:     3,
+		Horizontal: true,
+		Lim:        Empty,
+	}
+	im := PaintWidget(b, image.Rect(0, 0, 100, 100), 0)
+	assert.Equal(t, nil, checkImage([]string{
+		"w...",
+		"ww..",
+		"wwww",
+	}, im))
+}
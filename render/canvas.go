@@ -0,0 +1,576 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"sort"
+
+	"github.com/tidbyt/gg"
+)
+
+// Canvas draws a list of shapes and text, one pixel at a time and
+// without antialiasing.
+//
+// Each drawing op is drawn in order, on top of those before it.
+// Coordinates are in pixels, with 0, 0 in the top left corner of the
+// canvas. Anything outside of the canvas is cut off, and arcs and
+// curves reaching more than 1024 pixels past its edges aren't drawn.
+//
+// To animate, ops can be limited to a range of frames with
+// `start_frame` and `end_frame`. The canvas has as many frames as
+// needed to show all of them.
+//
+// DOC(Width): Width of the canvas
+// DOC(Height): Height of the canvas
+// DOC(Ops): List of drawing ops
+//
+// EXAMPLE BEGIN
+// render.Canvas(
+//   width = 32,
+//   height = 16,
+//   ops = [
+//     render.Rect(x = 0, y = 0, width = 32, height = 16, color = "#333"),
+//     render.Polygon(points = [(2, 13), (8, 3), (14, 13)], color = "#0af"),
+//     render.Arc(x = 23, y = 8, radius = 6, color = "#f80"),
+//     render.Line(x0 = 17, y0 = 14, x1 = 29, y1 = 2, color = "#fff"),
+//   ],
+// )
+// EXAMPLE END
+type Canvas struct {
+	Widget
+
+	Width  int        `starlark:"width,required"`
+	Height int        `starlark:"height,required"`
+	Ops    []CanvasOp `starlark:"ops"`
+
+	fonts FontSource
+}
+
+// CanvasOp is a drawing op on a Canvas.
+type CanvasOp interface {
+	// frames returns the first frame the op is drawn on, and the
+	// frame after the last one, or 0 if it's drawn on all frames
+	// from the first on.
+	frames() (int, int)
+
+	draw(c *canvasPainter)
+}
+
+// canvasMargin is how far outside of a Canvas ops may reach. Lines
+// reaching further are clipped, and arcs and curves aren't drawn, so
+// that painting doesn't take forever.
+const canvasMargin = 1024
+
+// canvasPainter draws single pixels on a Canvas, cut off at its edges.
+type canvasPainter struct {
+	dc            *gg.Context
+	width, height int
+}
+
+// visible returns the part of r that's on the canvas.
+func (c *canvasPainter) visible(r image.Rectangle) image.Rectangle {
+	return r.Intersect(image.Rect(0, 0, c.width, c.height))
+}
+
+// near returns whether x, y is within canvasMargin of the canvas.
+func (c *canvasPainter) near(x, y float64) bool {
+	return x >= -canvasMargin && x <= float64(c.width+canvasMargin) &&
+		y >= -canvasMargin && y <= float64(c.height+canvasMargin)
+}
+
+func (c *canvasPainter) setPixel(x, y int, col color.Color) {
+	if x < 0 || x >= c.width || y < 0 || y >= c.height {
+		return
+	}
+
+	c.dc.SetColor(col)
+	tx, ty := c.dc.TransformPoint(float64(x), float64(y))
+	c.dc.SetPixel(int(tx), int(ty))
+}
+
+func (c *canvasPainter) line(x0, y0, x1, y1 int, col color.Color) {
+	if !c.near(float64(x0), float64(y0)) || !c.near(float64(x1), float64(y1)) {
+		var ok bool
+		if x0, y0, x1, y1, ok = c.clipLine(x0, y0, x1, y1); !ok {
+			return
+		}
+	}
+
+	pl := &PolyLine{Vertices: []PathPoint{{x0, y0}, {x1, y1}}}
+	for i := 0; i < pl.Length(); i++ {
+		x, y := pl.Point(i)
+		c.setPixel(x, y, col)
+	}
+}
+
+// clipLine cuts the line from x0, y0 to x1, y1 down to the part that's
+// on the canvas, give or take a pixel, using the Liang-Barsky
+// algorithm. It returns false if none of it is.
+func (c *canvasPainter) clipLine(x0, y0, x1, y1 int) (int, int, int, int, bool) {
+	fx, fy := float64(x0), float64(y0)
+	dx, dy := float64(x1-x0), float64(y1-y0)
+
+	t0, t1 := 0.0, 1.0
+	for _, edge := range [][2]float64{
+		{-dx, fx + 1},
+		{dx, float64(c.width) - fx},
+		{-dy, fy + 1},
+		{dy, float64(c.height) - fy},
+	} {
+		p, q := edge[0], edge[1]
+		if p == 0 {
+			if q < 0 {
+				return 0, 0, 0, 0, false
+			}
+			continue
+		}
+
+		t := q / p
+		if p < 0 {
+			t0 = math.Max(t0, t)
+		} else {
+			t1 = math.Min(t1, t)
+		}
+		if t0 > t1 {
+			return 0, 0, 0, 0, false
+		}
+	}
+
+	return int(math.Round(fx + t0*dx)), int(math.Round(fy + t0*dy)),
+		int(math.Round(fx + t1*dx)), int(math.Round(fy + t1*dy)), true
+}
+
+// SetFontSource makes the fonts in src available to Label ops, in addition
+// to the built-in ones.
+func (c *Canvas) SetFontSource(src FontSource) {
+	c.fonts = src
+}
+
+func (c *Canvas) Init() error {
+	for _, op := range c.Ops {
+		if l, ok := op.(*Label); ok {
+			if err := l.init(c.fonts); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// MissingGlyphs returns the characters in Label ops that neither their
+// fonts nor their fallbacks can draw.
+func (c *Canvas) MissingGlyphs() []rune {
+	var missing []rune
+	seen := map[rune]bool{}
+	for _, op := range c.Ops {
+		l, ok := op.(*Label)
+		if !ok || l.text == nil {
+			continue
+		}
+		for _, r := range l.text.MissingGlyphs() {
+			if !seen[r] {
+				seen[r] = true
+				missing = append(missing, r)
+			}
+		}
+	}
+	return missing
+}
+
+func (c Canvas) PaintBounds(bounds image.Rectangle, frameIdx int) image.Rectangle {
+	return image.Rect(0, 0, c.Width, c.Height)
+}
+
+func (c Canvas) Paint(dc *gg.Context, bounds image.Rectangle, frameIdx int) {
+	p := &canvasPainter{dc: dc, width: c.Width, height: c.Height}
+
+	for _, op := range c.Ops {
+		start, end := op.frames()
+		if frameIdx < start || (end > 0 && frameIdx >= end) {
+			continue
+		}
+		op.draw(p)
+	}
+}
+
+func (c Canvas) FrameCount() int {
+	count := 1
+	for _, op := range c.Ops {
+		start, end := op.frames()
+		if start+1 > count {
+			count = start + 1
+		}
+		if end > count {
+			count = end
+		}
+	}
+	return count
+}
+
+func abs(a int) int {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+func opColor(c color.Color) color.Color {
+	if c == nil {
+		return color.White
+	}
+	return c
+}
+
+// Pixel draws a single pixel on a Canvas.
+//
+// DOC(X): Column of the pixel
+// DOC(Y): Row of the pixel
+// DOC(Color): Color of the pixel, default is '#fff'
+// DOC(StartFrame): First frame to draw on
+// DOC(EndFrame): Frame to stop drawing on, default is never
+type Pixel struct {
+	X          int         `starlark:"x,required"`
+	Y          int         `starlark:"y,required"`
+	Color      color.Color `starlark:"color"`
+	StartFrame int         `starlark:"start_frame"`
+	EndFrame   int         `starlark:"end_frame"`
+}
+
+func (p *Pixel) frames() (int, int) { return p.StartFrame, p.EndFrame }
+
+func (p *Pixel) draw(c *canvasPainter) {
+	c.setPixel(p.X, p.Y, opColor(p.Color))
+}
+
+// Line draws a straight line on a Canvas, including both of its ends.
+//
+// DOC(X0): Column of the start of the line
+// DOC(Y0): Row of the start of the line
+// DOC(X1): Column of the end of the line
+// DOC(Y1): Row of the end of the line
+// DOC(Color): Color of the line, default is '#fff'
+// DOC(StartFrame): First frame to draw on
+// DOC(EndFrame): Frame to stop drawing on, default is never
+type Line struct {
+	X0         int         `starlark:"x0,required"`
+	Y0         int         `starlark:"y0,required"`
+	X1         int         `starlark:"x1,required"`
+	Y1         int         `starlark:"y1,required"`
+	Color      color.Color `starlark:"color"`
+	StartFrame int         `starlark:"start_frame"`
+	EndFrame   int         `starlark:"end_frame"`
+}
+
+func (l *Line) frames() (int, int) { return l.StartFrame, l.EndFrame }
+
+func (l *Line) draw(c *canvasPainter) {
+	c.line(l.X0, l.Y0, l.X1, l.Y1, opColor(l.Color))
+}
+
+// Rect draws the outline of a rectangle on a Canvas, or with `fill`,
+// a filled rectangle.
+//
+// DOC(X): Column of the left edge
+// DOC(Y): Row of the top edge
+// DOC(Width): Width of the rectangle
+// DOC(Height): Height of the rectangle
+// DOC(Color): Color of the rectangle, default is '#fff'
+// DOC(Fill): Fill the rectangle instead of only drawing its outline
+// DOC(StartFrame): First frame to draw on
+// DOC(EndFrame): Frame to stop drawing on, default is never
+type Rect struct {
+	X          int         `starlark:"x,required"`
+	Y          int         `starlark:"y,required"`
+	Width      int         `starlark:"width,required"`
+	Height     int         `starlark:"height,required"`
+	Color      color.Color `starlark:"color"`
+	Fill       bool        `starlark:"fill"`
+	StartFrame int         `starlark:"start_frame"`
+	EndFrame   int         `starlark:"end_frame"`
+}
+
+func (r *Rect) frames() (int, int) { return r.StartFrame, r.EndFrame }
+
+func (r *Rect) draw(c *canvasPainter) {
+	col := opColor(r.Color)
+	area := c.visible(image.Rect(r.X, r.Y, r.X+r.Width, r.Y+r.Height))
+	for y := area.Min.Y; y < area.Max.Y; y++ {
+		for x := area.Min.X; x < area.Max.X; x++ {
+			edge := x == r.X || x == r.X+r.Width-1 || y == r.Y || y == r.Y+r.Height-1
+			if r.Fill || edge {
+				c.setPixel(x, y, col)
+			}
+		}
+	}
+}
+
+// Polygon draws a filled polygon on a Canvas. Pixels on its edges are
+// included.
+//
+// DOC(Points): A list of 2-tuples of coordinates of the corners
+// DOC(Color): Color of the polygon, default is '#fff'
+// DOC(StartFrame): First frame to draw on
+// DOC(EndFrame): Frame to stop drawing on, default is never
+type Polygon struct {
+	Points     [][2]float64 `starlark:"points,required"`
+	Color      color.Color  `starlark:"color"`
+	StartFrame int          `starlark:"start_frame"`
+	EndFrame   int          `starlark:"end_frame"`
+}
+
+func (p *Polygon) frames() (int, int) { return p.StartFrame, p.EndFrame }
+
+func (p *Polygon) draw(c *canvasPainter) {
+	if len(p.Points) == 0 {
+		return
+	}
+	col := opColor(p.Color)
+
+	minY, maxY := p.Points[0][1], p.Points[0][1]
+	for _, pt := range p.Points {
+		minY = math.Min(minY, pt[1])
+		maxY = math.Max(maxY, pt[1])
+	}
+
+	// fill the inside, one row at a time, using the even-odd rule
+	minY = math.Max(minY, 0)
+	maxY = math.Min(maxY, float64(c.height-1))
+	for y := int(math.Ceil(minY)); float64(y) <= maxY; y++ {
+		fy := float64(y)
+		xs := []float64{}
+		for i := range p.Points {
+			a, b := p.Points[i], p.Points[(i+1)%len(p.Points)]
+			if (a[1] <= fy && fy < b[1]) || (b[1] <= fy && fy < a[1]) {
+				xs = append(xs, a[0]+(fy-a[1])/(b[1]-a[1])*(b[0]-a[0]))
+			}
+		}
+		sort.Float64s(xs)
+
+		for i := 0; i+1 < len(xs); i += 2 {
+			minX := math.Max(xs[i], 0)
+			maxX := math.Min(xs[i+1], float64(c.width-1))
+			for x := int(math.Ceil(minX)); float64(x) <= maxX; x++ {
+				c.setPixel(x, y, col)
+			}
+		}
+	}
+
+	// and then the edges, which the fill leaves out in places
+	for i := range p.Points {
+		a, b := p.Points[i], p.Points[(i+1)%len(p.Points)]
+		c.line(
+			int(math.Round(a[0])), int(math.Round(a[1])),
+			int(math.Round(b[0])), int(math.Round(b[1])),
+			col,
+		)
+	}
+}
+
+// Arc draws part of the outline of a circle on a Canvas, or all of it
+// unless `start` and `end` are set. Angles are in degrees, clockwise
+// from the right.
+//
+// DOC(X): Column of the center of the circle
+// DOC(Y): Row of the center of the circle
+// DOC(Radius): Radius of the circle
+// DOC(Start): Angle to start drawing at
+// DOC(End): Angle to stop drawing at
+// DOC(Color): Color of the arc, default is '#fff'
+// DOC(StartFrame): First frame to draw on
+// DOC(EndFrame): Frame to stop drawing on, default is never
+type Arc struct {
+	X          int         `starlark:"x,required"`
+	Y          int         `starlark:"y,required"`
+	Radius     int         `starlark:"radius,required"`
+	Start      float64     `starlark:"start"`
+	End        float64     `starlark:"end"`
+	Color      color.Color `starlark:"color"`
+	StartFrame int         `starlark:"start_frame"`
+	EndFrame   int         `starlark:"end_frame"`
+}
+
+func (a *Arc) frames() (int, int) { return a.StartFrame, a.EndFrame }
+
+// Returns whether the point at dx, dy from the center is within the
+// angles of the arc
+func (a *Arc) covers(dx, dy int) bool {
+	span := a.End - a.Start
+	if span == 0 || math.Abs(span) >= 360 {
+		return true
+	}
+
+	start, end := a.Start, a.End
+	if span < 0 {
+		start, end = end, start
+	}
+
+	angle := math.Atan2(float64(dy), float64(dx)) * 180 / math.Pi
+	angle = math.Mod(angle-start, 360)
+	if angle < 0 {
+		angle += 360
+	}
+	return angle <= end-start
+}
+
+func (a *Arc) draw(c *canvasPainter) {
+	col := opColor(a.Color)
+
+	if a.Radius <= 0 {
+		c.setPixel(a.X, a.Y, col)
+		return
+	}
+
+	if !c.near(float64(a.X-a.Radius), float64(a.Y-a.Radius)) || !c.near(float64(a.X+a.Radius), float64(a.Y+a.Radius)) {
+		return
+	}
+
+	// midpoint circle, mirrored into all eight octants
+	x, y := a.Radius, 0
+	err := 1 - a.Radius
+	for x >= y {
+		for _, pt := range [][2]int{
+			{x, y}, {y, x}, {-y, x}, {-x, y},
+			{-x, -y}, {-y, -x}, {y, -x}, {x, -y},
+		} {
+			if a.covers(pt[0], pt[1]) {
+				c.setPixel(a.X+pt[0], a.Y+pt[1], col)
+			}
+		}
+
+		y++
+		if err < 0 {
+			err += 2*y + 1
+		} else {
+			x--
+			err += 2*(y-x) + 1
+		}
+	}
+}
+
+// Bezier draws a Bézier curve on a Canvas. The curve starts at the
+// first point and ends at the last one, and is pulled towards the
+// points in between.
+//
+// DOC(Points): A list of 2-tuples of coordinates of the control points
+// DOC(Color): Color of the curve, default is '#fff'
+// DOC(StartFrame): First frame to draw on
+// DOC(EndFrame): Frame to stop drawing on, default is never
+type Bezier struct {
+	Points     [][2]float64 `starlark:"points,required"`
+	Color      color.Color  `starlark:"color"`
+	StartFrame int          `starlark:"start_frame"`
+	EndFrame   int          `starlark:"end_frame"`
+}
+
+func (b *Bezier) frames() (int, int) { return b.StartFrame, b.EndFrame }
+
+// Returns the point at t along the curve, using De Casteljau's
+// algorithm
+func (b *Bezier) at(t float64) (float64, float64) {
+	pts := make([][2]float64, len(b.Points))
+	copy(pts, b.Points)
+	for n := len(pts) - 1; n > 0; n-- {
+		for i := 0; i < n; i++ {
+			pts[i][0] += (pts[i+1][0] - pts[i][0]) * t
+			pts[i][1] += (pts[i+1][1] - pts[i][1]) * t
+		}
+	}
+	return pts[0][0], pts[0][1]
+}
+
+func (b *Bezier) draw(c *canvasPainter) {
+	if len(b.Points) == 0 {
+		return
+	}
+
+	// the curve stays within its control points
+	for _, pt := range b.Points {
+		if !c.near(pt[0], pt[1]) {
+			return
+		}
+	}
+
+	// the curve is no longer than the lines between its control
+	// points, so this many steps are enough to not skip any pixels
+	length := 0.0
+	for i := 1; i < len(b.Points); i++ {
+		length += math.Hypot(b.Points[i][0]-b.Points[i-1][0], b.Points[i][1]-b.Points[i-1][1])
+	}
+	steps := int(math.Ceil(length))
+	if steps < 1 {
+		steps = 1
+	}
+
+	pl := &PolyLine{}
+	for i := 0; i <= steps; i++ {
+		x, y := b.at(float64(i) / float64(steps))
+		pt := PathPoint{int(math.Round(x)), int(math.Round(y))}
+		n := len(pl.Vertices)
+		if n > 0 && pl.Vertices[n-1] == pt {
+			continue
+		}
+
+		// skip corners, which would make the curve look thicker
+		if n > 1 && abs(pt.X-pl.Vertices[n-2].X) <= 1 && abs(pt.Y-pl.Vertices[n-2].Y) <= 1 {
+			pl.Vertices[n-1] = pt
+			continue
+		}
+		pl.Vertices = append(pl.Vertices, pt)
+	}
+
+	col := opColor(b.Color)
+	if len(pl.Vertices) == 1 {
+		c.setPixel(pl.Vertices[0].X, pl.Vertices[0].Y, col)
+		return
+	}
+	for i := 0; i < pl.Length(); i++ {
+		x, y := pl.Point(i)
+		c.setPixel(x, y, col)
+	}
+}
+
+// Label draws text on a Canvas, with its top left corner at x, y.
+//
+// DOC(X): Column of the left edge of the text
+// DOC(Y): Row of the top edge of the text
+// DOC(Content): The text string to draw
+// DOC(Font): Desired font face
+// DOC(Fallback): Fonts to draw characters missing from `font` with, in order of preference
+// DOC(Color): Desired font color
+// DOC(StartFrame): First frame to draw on
+// DOC(EndFrame): Frame to stop drawing on, default is never
+type Label struct {
+	X          int         `starlark:"x,required"`
+	Y          int         `starlark:"y,required"`
+	Content    string      `starlark:"content,required"`
+	Font       string      `starlark:"font"`
+	Fallback   []string    `starlark:"fallback"`
+	Color      color.Color `starlark:"color"`
+	StartFrame int         `starlark:"start_frame"`
+	EndFrame   int         `starlark:"end_frame"`
+
+	text *Text
+}
+
+func (l *Label) frames() (int, int) { return l.StartFrame, l.EndFrame }
+
+func (l *Label) init(src FontSource) error {
+	l.text = &Text{Content: l.Content, Font: l.Font, Fallback: l.Fallback, Color: l.Color}
+	l.text.SetFontSource(src)
+	return l.text.Init()
+}
+
+func (l *Label) draw(c *canvasPainter) {
+	img := l.text.img
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			col := img.At(x, y)
+			if _, _, _, a := col.RGBA(); a == 0 {
+				continue
+			}
+			c.setPixel(l.X+x-b.Min.X, l.Y+y-b.Min.Y, col)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanvasPixelLineRect(t *testing.T) {
+	c := &Canvas{
+		Width:  6,
+		Height: 5,
+		Ops: []CanvasOp{
+			&Rect{X: 0, Y: 0, Width: 4, Height: 3, Color: color.RGBA{0, 0, 0xff, 0xff}},
+			&Rect{X: 4, Y: 3, Width: 2, Height: 2, Color: color.RGBA{0, 0xff, 0, 0xff}, Fill: true},
+			&Line{X0: 0, Y0: 4, X1: 3, Y1: 1},
+			&Pixel{X: 5, Y: 0, Color: color.RGBA{0xff, 0, 0, 0xff}},
+		},
+	}
+	require.NoError(t, c.Init())
+
+	assert.Equal(t, nil, checkImage([]string{
+		"bbbb.r",
+		"b..w..",
+		"bbwb..",
+		".w..gg",
+		"w...gg",
+	}, PaintWidget(c, image.Rect(0, 0, 100, 100), 0)))
+}
+
+func TestCanvasPolygon(t *testing.T) {
+	c := &Canvas{
+		Width:  7,
+		Height: 5,
+		Ops: []CanvasOp{
+			&Polygon{Points: [][2]float64{{0, 4}, {3, 0}, {6, 4}}},
+		},
+	}
+	require.NoError(t, c.Init())
+
+	assert.Equal(t, nil, checkImage([]string{
+		"...w...",
+		"..www..",
+		"..wwww.",
+		".wwwww.",
+		"wwwwwww",
+	}, PaintWidget(c, image.Rect(0, 0, 100, 100), 0)))
+}
+
+func TestCanvasArc(t *testing.T) {
+	c := &Canvas{
+		Width:  7,
+		Height: 7,
+		Ops: []CanvasOp{
+			&Arc{X: 3, Y: 3, Radius: 3},
+		},
+	}
+	require.NoError(t, c.Init())
+
+	assert.Equal(t, nil, checkImage([]string{
+		"..www..",
+		".w...w.",
+		"w.....w",
+		"w.....w",
+		"w.....w",
+		".w...w.",
+		"..www..",
+	}, PaintWidget(c, image.Rect(0, 0, 100, 100), 0)))
+
+	// Angles are clockwise from the right, so this is the bottom half
+	c.Ops = []CanvasOp{&Arc{X: 3, Y: 3, Radius: 3, Start: 0, End: 180}}
+	assert.Equal(t, nil, checkImage([]string{
+		".......",
+		".......",
+		".......",
+		"w.....w",
+		"w.....w",
+		".w...w.",
+		"..www..",
+	}, PaintWidget(c, image.Rect(0, 0, 100, 100), 0)))
+}
+
+func TestCanvasBezier(t *testing.T) {
+	c := &Canvas{
+		Width:  7,
+		Height: 4,
+		Ops: []CanvasOp{
+			&Bezier{Points: [][2]float64{{0, 3}, {3, -3}, {6, 3}}},
+		},
+	}
+	require.NoError(t, c.Init())
+
+	assert.Equal(t, nil, checkImage([]string{
+		"...w...",
+		"..w.w..",
+		".w...w.",
+		"w.....w",
+	}, PaintWidget(c, image.Rect(0, 0, 100, 100), 0)))
+}
+
+func TestCanvasLabel(t *testing.T) {
+	c := &Canvas{
+		Width:  7,
+		Height: 8,
+		Ops: []CanvasOp{
+			&Label{X: 2, Y: 1, Content: "A", Color: color.RGBA{0xff, 0, 0, 0xff}},
+		},
+	}
+	require.NoError(t, c.Init())
+
+	// Text is cut off at the edge of the canvas
+	assert.Equal(t, nil, checkImage([]string{
+		".......",
+		".......",
+		"...rr..",
+		"..r..r.",
+		"..r..r.",
+		"..rrrr.",
+		"..r..r.",
+		"..r..r.",
+	}, PaintWidget(c, image.Rect(0, 0, 100, 100), 0)))
+
+	c.Ops = []CanvasOp{&Label{X: 0, Y: 0, Content: "A", Font: "nope"}}
+	assert.Error(t, c.Init())
+
+	// Labels report the glyphs their fonts are missing, like Text
+	c.Ops = []CanvasOp{
+		&Label{Content: "aΩ", Font: "tom-thumb"},
+		&Label{Content: "Ω", Font: "tom-thumb", Fallback: []string{"6x13"}},
+		&Rect{Width: 1, Height: 1},
+	}
+	require.NoError(t, c.Init())
+	assert.Equal(t, []rune{'Ω'}, c.MissingGlyphs())
+}
+
+func TestCanvasFrames(t *testing.T) {
+	c := &Canvas{
+		Width:  3,
+		Height: 1,
+		Ops: []CanvasOp{
+			&Pixel{X: 0, Y: 0},
+			&Pixel{X: 1, Y: 0, StartFrame: 1, EndFrame: 3},
+			&Pixel{X: 2, Y: 0, StartFrame: 2},
+		},
+	}
+	require.NoError(t, c.Init())
+	assert.Equal(t, 3, c.FrameCount())
+
+	for i, expected := range []string{"w..", "ww.", "www", "w.w"} {
+		assert.Equal(t, nil, checkImage([]string{expected}, PaintWidget(c, image.Rect(0, 0, 100, 100), i)), "frame %d", i)
+	}
+
+	c.Ops = nil
+	assert.Equal(t, 1, c.FrameCount())
+}
+
+func TestCanvasFarOutside(t *testing.T) {
+	c := &Canvas{
+		Width:  5,
+		Height: 4,
+		Ops: []CanvasOp{
+			&Rect{X: -100000, Y: -100000, Width: 200000, Height: 200000, Color: color.RGBA{0, 0, 0xff, 0xff}, Fill: true},
+			&Rect{X: -100000, Y: 1, Width: 200000, Height: 100000},
+			&Polygon{
+				Points: [][2]float64{{-1e9, 3}, {1e9, 3}, {1e9, 1e9}},
+				Color:  color.RGBA{0, 0xff, 0, 0xff},
+			},
+			&Line{X0: -1000000, Y0: 0, X1: 1000000, Y1: 0, Color: color.RGBA{0xff, 0, 0, 0xff}},
+			&Line{X0: 2, Y0: 0, X1: 2, Y1: 1000000000, Color: color.RGBA{0xff, 0, 0, 0xff}},
+
+			// too far out to be drawn at all
+			&Arc{X: 2, Y: 2, Radius: 1000000000},
+			&Bezier{Points: [][2]float64{{0, 0}, {1e9, 1e9}, {4, 3}}},
+		},
+	}
+	require.NoError(t, c.Init())
+
+	assert.Equal(t, nil, checkImage([]string{
+		"rrrrr",
+		"wwrww",
+		"bbrbb",
+		"ggrgg",
+	}, PaintWidget(c, image.Rect(0, 0, 100, 100), 0)))
+}
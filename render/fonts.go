@@ -5,15 +5,33 @@ package render
 import (
 	"encoding/base64"
 	"fmt"
+	"image"
+	"path"
+	"strings"
 	"sync"
+	"unicode"
 
 	"github.com/zachomedia/go-bdf"
 	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
 )
 
 var fontCache = map[string]font.Face{}
 var fontMutex = &sync.Mutex{}
 
+// DefaultFontSize is the size in pixels that scalable fonts are rasterized
+// at, unless another size is given.
+var DefaultFontSize = 8.0
+
+// FontSource provides fonts in addition to the built-in ones, such as the
+// font files that ship with an applet.
+type FontSource interface {
+	// Font returns the face registered under name, or nil if there is
+	// none.
+	Font(name string) (font.Face, error)
+}
+
 func GetFontList() []string {
 	fontNames := []string{}
 	for key := range fontDataRaw {
@@ -48,3 +66,307 @@ func GetFont(name string) (font.Face, error) {
 	fontCache[name] = f.NewFace()
 	return fontCache[name], nil
 }
+
+// LookupFont returns the face registered under name in src, falling back to
+// the built-in fonts. src may be nil.
+func LookupFont(src FontSource, name string) (font.Face, error) {
+	if src != nil {
+		face, err := src.Font(name)
+		if err != nil {
+			return nil, err
+		}
+		if face != nil {
+			return face, nil
+		}
+	}
+
+	return GetFont(name)
+}
+
+// HasGlyph reports whether face can draw r. Unlike face.GlyphAdvance, it
+// doesn't count characters that BDF fonts would draw with their default
+// character.
+func HasGlyph(face font.Face, r rune) bool {
+	switch f := face.(type) {
+	case *bdf.Face:
+		_, ok := f.Font.CharMap[r]
+		return ok
+
+	case *syncFace:
+		f.mutex.Lock()
+		defer f.mutex.Unlock()
+		return HasGlyph(f.face, r)
+
+	case *fontChain:
+		for _, face := range f.faces {
+			if HasGlyph(face, r) {
+				return true
+			}
+		}
+		return false
+
+	default:
+		_, ok := face.GlyphAdvance(r)
+		return ok
+	}
+}
+
+// MissingGlyphs returns the characters in s that none of faces can draw,
+// in order of their first appearance. Control characters such as line
+// breaks are never considered missing.
+func MissingGlyphs(s string, faces ...font.Face) []rune {
+	var missing []rune
+	seen := map[rune]bool{}
+
+	for _, r := range s {
+		if seen[r] || unicode.IsControl(r) {
+			continue
+		}
+		seen[r] = true
+
+		drawable := false
+		for _, face := range faces {
+			if HasGlyph(face, r) {
+				drawable = true
+				break
+			}
+		}
+		if !drawable {
+			missing = append(missing, r)
+		}
+	}
+
+	return missing
+}
+
+// LoadFontChain looks up the font name and its fallbacks, and returns a face
+// that draws every character of content with the first of them that has
+// it. Characters that none of them have are drawn as the first font would
+// draw them, and returned as missing.
+//
+// Fallbacks that content doesn't need are left out, so that they don't
+// affect the face's metrics.
+func LoadFontChain(src FontSource, name string, fallback []string, content string) (font.Face, []rune, error) {
+	primary, err := LookupFont(src, name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	faces := []font.Face{primary}
+	for _, fb := range fallback {
+		face, err := LookupFont(src, fb)
+		if err != nil {
+			return nil, nil, fmt.Errorf("fallback: %w", err)
+		}
+		faces = append(faces, face)
+	}
+
+	used := make([]bool, len(faces))
+	used[0] = true
+	var missing []rune
+	seen := map[rune]bool{}
+	for _, r := range content {
+		if seen[r] || unicode.IsControl(r) {
+			continue
+		}
+		seen[r] = true
+
+		found := false
+		for i, face := range faces {
+			if HasGlyph(face, r) {
+				used[i] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, r)
+		}
+	}
+
+	chain := &fontChain{}
+	for i, face := range faces {
+		if used[i] {
+			chain.faces = append(chain.faces, face)
+		}
+	}
+	if len(chain.faces) == 1 {
+		return primary, missing, nil
+	}
+
+	return chain, missing, nil
+}
+
+// fontChain draws every glyph with the first of its faces that has it.
+// Its metrics are large enough to fit the glyphs of all faces.
+type fontChain struct {
+	faces []font.Face
+}
+
+func (c *fontChain) faceFor(r rune) font.Face {
+	for _, face := range c.faces {
+		if HasGlyph(face, r) {
+			return face
+		}
+	}
+	return c.faces[0]
+}
+
+func (c *fontChain) Close() error {
+	return nil
+}
+
+func (c *fontChain) Glyph(dot fixed.Point26_6, r rune) (image.Rectangle, image.Image, image.Point, fixed.Int26_6, bool) {
+	return c.faceFor(r).Glyph(dot, r)
+}
+
+func (c *fontChain) GlyphBounds(r rune) (fixed.Rectangle26_6, fixed.Int26_6, bool) {
+	return c.faceFor(r).GlyphBounds(r)
+}
+
+func (c *fontChain) GlyphAdvance(r rune) (fixed.Int26_6, bool) {
+	return c.faceFor(r).GlyphAdvance(r)
+}
+
+func (c *fontChain) Kern(r0, r1 rune) fixed.Int26_6 {
+	face := c.faceFor(r0)
+	if face != c.faceFor(r1) {
+		return 0
+	}
+	return face.Kern(r0, r1)
+}
+
+func (c *fontChain) Metrics() font.Metrics {
+	m := c.faces[0].Metrics()
+	for _, face := range c.faces[1:] {
+		fm := face.Metrics()
+		if fm.Ascent > m.Ascent {
+			m.Ascent = fm.Ascent
+		}
+		if fm.Descent > m.Descent {
+			m.Descent = fm.Descent
+		}
+	}
+	if m.Ascent+m.Descent > m.Height {
+		m.Height = m.Ascent + m.Descent
+	}
+	return m
+}
+
+// IsFontFile reports whether filename looks like a font that ParseFont can
+// parse.
+func IsFontFile(filename string) bool {
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".bdf", ".pcf", ".ttf", ".otf":
+		return true
+	default:
+		return false
+	}
+}
+
+// ParseFont parses a BDF, PCF, TrueType or OpenType font, picking the format
+// based on the extension of filename. Scalable fonts are rasterized at size
+// pixels, which is ignored for bitmap fonts.
+func ParseFont(filename string, data []byte, size float64) (font.Face, error) {
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".bdf":
+		f, err := bdf.Parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("parsing BDF font: %w", err)
+		}
+		return f.NewFace(), nil
+
+	case ".pcf":
+		f, err := parsePCF(data)
+		if err != nil {
+			return nil, fmt.Errorf("parsing PCF font: %w", err)
+		}
+		return f.NewFace(), nil
+
+	case ".ttf", ".otf":
+		f, err := opentype.Parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("parsing OpenType font: %w", err)
+		}
+
+		if size <= 0 {
+			size = DefaultFontSize
+		}
+
+		face, err := opentype.NewFace(f, &opentype.FaceOptions{
+			Size:    size,
+			DPI:     72,
+			Hinting: font.HintingFull,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("rasterizing OpenType font: %w", err)
+		}
+		return &syncFace{face: face}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported font format '%s'", path.Ext(filename))
+	}
+}
+
+// syncFace makes a face safe for concurrent use. OpenType faces keep
+// rasterization buffers around and hand out glyph masks that point into
+// them, so calls are serialized and masks copied before they're returned.
+type syncFace struct {
+	face  font.Face
+	mutex sync.Mutex
+}
+
+func (f *syncFace) Close() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return f.face.Close()
+}
+
+func (f *syncFace) Glyph(dot fixed.Point26_6, r rune) (image.Rectangle, image.Image, image.Point, fixed.Int26_6, bool) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	dr, mask, maskp, advance, ok := f.face.Glyph(dot, r)
+	if !ok {
+		return dr, mask, maskp, advance, ok
+	}
+
+	b := image.Rectangle{Min: maskp, Max: maskp.Add(dr.Size())}
+	copied := image.NewAlpha(b)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			copied.Set(x, y, mask.At(x, y))
+		}
+	}
+
+	return dr, copied, maskp, advance, ok
+}
+
+func (f *syncFace) GlyphBounds(r rune) (fixed.Rectangle26_6, fixed.Int26_6, bool) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return f.face.GlyphBounds(r)
+}
+
+func (f *syncFace) GlyphAdvance(r rune) (fixed.Int26_6, bool) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return f.face.GlyphAdvance(r)
+}
+
+func (f *syncFace) Kern(r0, r1 rune) fixed.Int26_6 {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return f.face.Kern(r0, r1)
+}
+
+func (f *syncFace) Metrics() font.Metrics {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return f.face.Metrics()
+}
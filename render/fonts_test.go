@@ -0,0 +1,248 @@
+package render
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"math/bits"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zachomedia/go-bdf"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// encodePCF writes a BDF font as PCF, in the byte and bit order given by
+// msb, and padding glyph rows to 4 bytes.
+func encodePCF(f *bdf.Font, msb bool) []byte {
+	var order binary.ByteOrder = binary.LittleEndian
+	format := uint32(2) // glyph pad 4
+	if msb {
+		order = binary.BigEndian
+		format |= pcfByteMask | pcfBitMask
+	}
+
+	table := func(format uint32, data ...interface{}) []byte {
+		buf := &bytes.Buffer{}
+		binary.Write(buf, binary.LittleEndian, format)
+		for _, d := range data {
+			binary.Write(buf, order, d)
+		}
+		return buf.Bytes()
+	}
+
+	accelerators := table(format, [8]byte{}, int32(f.Ascent), int32(f.Descent))
+
+	var metrics []interface{}
+	metrics = append(metrics, int16(len(f.Characters)))
+	var offsets []int32
+	var glyphs []byte
+	for _, c := range f.Characters {
+		w, h := c.Alpha.Rect.Dx(), c.Alpha.Rect.Dy()
+		metrics = append(metrics, []uint8{
+			uint8(c.LowerPoint[0] + 0x80),
+			uint8(c.LowerPoint[0] + w + 0x80),
+			uint8(c.Advance[0] + 0x80),
+			uint8(h + c.LowerPoint[1] + 0x80),
+			uint8(-c.LowerPoint[1] + 0x80),
+		})
+
+		offsets = append(offsets, int32(len(glyphs)))
+		stride := ((w+7)/8 + 3) / 4 * 4
+		for y := 0; y < h; y++ {
+			row := make([]byte, stride)
+			for x := 0; x < w; x++ {
+				if c.Alpha.Pix[y*c.Alpha.Stride+x] != 0 {
+					row[x/8] |= 0x80 >> (x % 8)
+				}
+			}
+			if !msb {
+				for i, b := range row {
+					row[i] = bits.Reverse8(b)
+				}
+			}
+			glyphs = append(glyphs, row...)
+		}
+	}
+	metricsTable := table(format|pcfCompressedMetrics, metrics...)
+
+	bitmaps := table(format, int32(len(offsets)), offsets,
+		[4]int32{0, 0, int32(len(glyphs)), 0}, glyphs)
+
+	runes := []rune{}
+	for r := range f.CharMap {
+		runes = append(runes, r)
+	}
+	sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+	minByte1, maxByte1 := runes[0]>>8, runes[len(runes)-1]>>8
+	indices := make([]uint16, (maxByte1-minByte1+1)*256)
+	for i := range indices {
+		indices[i] = pcfNoGlyph
+	}
+	for i, c := range f.Characters {
+		indices[c.Encoding-minByte1*256] = uint16(i)
+	}
+	encodings := table(format, int16(0), int16(255), int16(minByte1), int16(maxByte1), int16(f.DefaultChar), indices)
+
+	tables := []struct {
+		typ  uint32
+		data []byte
+	}{
+		{pcfBDFAccelerators, accelerators},
+		{pcfMetrics, metricsTable},
+		{pcfBitmaps, bitmaps},
+		{pcfBDFEncodings, encodings},
+	}
+
+	out := &bytes.Buffer{}
+	out.WriteString(pcfMagic)
+	binary.Write(out, binary.LittleEndian, int32(len(tables)))
+	offset := 8 + 16*len(tables)
+	for _, t := range tables {
+		binary.Write(out, binary.LittleEndian, []uint32{
+			t.typ,
+			binary.LittleEndian.Uint32(t.data),
+			uint32(len(t.data)),
+			uint32(offset),
+		})
+		offset += len(t.data)
+	}
+	for _, t := range tables {
+		out.Write(t.data)
+	}
+
+	return out.Bytes()
+}
+
+func TestParseFontPCF(t *testing.T) {
+	data, err := base64.StdEncoding.DecodeString(fontDataRaw["tom-thumb"])
+	require.NoError(t, err)
+	expected, err := bdf.Parse(data)
+	require.NoError(t, err)
+
+	for _, msb := range []bool{true, false} {
+		face, err := ParseFont("tom-thumb.pcf", encodePCF(expected, msb), 0)
+		require.NoError(t, err)
+
+		actual := face.(*bdf.Face).Font
+		assert.Equal(t, expected.Ascent, actual.Ascent)
+		assert.Equal(t, expected.Descent, actual.Descent)
+		require.Equal(t, len(expected.CharMap), len(actual.CharMap))
+
+		for r, c := range expected.CharMap {
+			assert.Equal(t, c.Advance, actual.CharMap[r].Advance)
+			assert.Equal(t, c.LowerPoint, actual.CharMap[r].LowerPoint)
+			assert.Equal(t, c.Alpha.Pix, actual.CharMap[r].Alpha.Pix)
+		}
+	}
+
+	text := &Text{Content: "Hi", Font: "tom-thumb"}
+	require.NoError(t, text.Init())
+	pcfText := &Text{Content: "Hi", Font: "tom-thumb.pcf"}
+	pcfText.SetFontSource(testFontSource{"tom-thumb.pcf": encodePCF(expected, true)})
+	require.NoError(t, pcfText.Init())
+	assert.Equal(t, text.img, pcfText.img)
+}
+
+func TestParseFontTrueType(t *testing.T) {
+	large, err := ParseFont("goregular.ttf", goregular.TTF, 16)
+	require.NoError(t, err)
+	small, err := ParseFont("goregular.TTF", goregular.TTF, 0)
+	require.NoError(t, err)
+
+	largeAdvance, ok := large.GlyphAdvance('M')
+	require.True(t, ok)
+	smallAdvance, ok := small.GlyphAdvance('M')
+	require.True(t, ok)
+	assert.InDelta(t, 2*smallAdvance.Round(), largeAdvance.Round(), 1)
+
+	text := &Text{Content: "Hi", Font: "goregular.ttf"}
+	text.SetFontSource(testFontSource{"goregular.ttf": goregular.TTF})
+	require.NoError(t, text.Init())
+	w, h := text.Size()
+	assert.Greater(t, w, 0)
+	assert.Greater(t, h, 0)
+}
+
+func TestParseFontInvalid(t *testing.T) {
+	_, err := ParseFont("font.woff", goregular.TTF, 0)
+	assert.Error(t, err)
+
+	_, err = ParseFont("font.pcf", goregular.TTF, 0)
+	assert.Error(t, err)
+
+	_, err = ParseFont("font.pcf", []byte(pcfMagic+"\xff\xff\xff\x7f"), 0)
+	assert.Error(t, err)
+
+	_, err = ParseFont("font.ttf", []byte("not a font"), 0)
+	assert.Error(t, err)
+}
+
+func TestLookupFont(t *testing.T) {
+	face, err := LookupFont(nil, "tb-8")
+	require.NoError(t, err)
+	assert.NotNil(t, face)
+
+	face, err = LookupFont(testFontSource{}, "tb-8")
+	require.NoError(t, err)
+	assert.NotNil(t, face)
+
+	_, err = LookupFont(testFontSource{}, "no-such-font")
+	assert.Error(t, err)
+}
+
+type testFontSource map[string][]byte
+
+func (s testFontSource) Font(name string) (font.Face, error) {
+	data, ok := s[name]
+	if !ok {
+		return nil, nil
+	}
+	return ParseFont(name, data, 0)
+}
+
+func TestLoadFontChain(t *testing.T) {
+	tomThumb, err := GetFont("tom-thumb")
+	require.NoError(t, err)
+	large, err := GetFont("6x13")
+	require.NoError(t, err)
+
+	// fallbacks that aren't needed are left out
+	face, missing, err := LoadFontChain(nil, "tom-thumb", []string{"6x13"}, "abc")
+	require.NoError(t, err)
+	assert.Equal(t, tomThumb, face)
+	assert.Empty(t, missing)
+
+	face, missing, err = LoadFontChain(nil, "tom-thumb", []string{"6x13"}, "aΩ\n東")
+	require.NoError(t, err)
+	assert.Equal(t, []rune{'東'}, missing)
+	assert.True(t, HasGlyph(face, 'a'))
+	assert.True(t, HasGlyph(face, 'Ω'))
+	assert.False(t, HasGlyph(face, '東'))
+	assert.Equal(t, large.Metrics().Ascent, face.Metrics().Ascent)
+
+	// glyphs come from the first face that has them
+	advance, _ := face.GlyphAdvance('a')
+	expected, _ := tomThumb.GlyphAdvance('a')
+	assert.Equal(t, expected, advance)
+	advance, _ = face.GlyphAdvance('Ω')
+	expected, _ = large.GlyphAdvance('Ω')
+	assert.Equal(t, expected, advance)
+
+	_, _, err = LoadFontChain(nil, "tom-thumb", []string{"no-such-font"}, "a")
+	assert.Error(t, err)
+}
+
+func TestMissingGlyphs(t *testing.T) {
+	tomThumb, err := GetFont("tom-thumb")
+	require.NoError(t, err)
+	large, err := GetFont("6x13")
+	require.NoError(t, err)
+
+	assert.Equal(t, []rune{'Ω', '東'}, MissingGlyphs("aΩb東Ω\n", tomThumb))
+	assert.Equal(t, []rune{'東'}, MissingGlyphs("aΩb東Ω\n", tomThumb, large))
+	assert.Empty(t, MissingGlyphs("ab", tomThumb))
+}
@@ -0,0 +1,167 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/tidbyt/gg"
+)
+
+// Gauge shows how far a value is between the limits of a range, by
+// filling part of a half circle, or of a straight bar if `chart_type`
+// is "linear". Linear gauges fill from left to right, unless they're
+// taller than wide, in which case they fill from the bottom up.
+//
+// To change color as the value grows, pass parallel lists
+// `thresholds` and `threshold_colors`. The gauge is drawn in the
+// color of the highest threshold the value has reached.
+//
+// DOC(Value): The value to show
+// DOC(Width): Limits Gauge width
+// DOC(Height): Limits Gauge height
+// DOC(Color): Fill color, default is '#fff'
+// DOC(TrackColor): Color of the unfilled part, default is a dimmed fill color
+// DOC(Thresholds): List of values in ascending order, at which the fill color changes
+// DOC(ThresholdColors): List of colors corresponding to each threshold
+// DOC(Lim): Range of values, default is 0 to 100
+// DOC(ChartType): Specifies the type of gauge to render, "arc" or "linear", default is "arc"
+// DOC(Thickness): Thickness of the arc, default is a quarter of its radius
+//
+// EXAMPLE BEGIN
+// render.Gauge(
+//   value = 72,
+//   width = 32,
+//   height = 16,
+//   thresholds = [50, 80],
+//   threshold_colors = ["#fa0", "#f00"],
+//   color = "#0f0",
+// )
+// EXAMPLE END
+type Gauge struct {
+	Widget
+
+	Value           float64       `starlark:"value,required"`
+	Width           int           `starlark:"width,required"`
+	Height          int           `starlark:"height,required"`
+	Color           color.Color   `starlark:"color"`
+	TrackColor      color.Color   `starlark:"track_color"`
+	Thresholds      []float64     `starlark:"thresholds"`
+	ThresholdColors []color.Color `starlark:"threshold_colors"`
+	Lim             [2]float64    `starlark:"lim"`
+	ChartType       string        `starlark:"chart_type"`
+	Thickness       int           `starlark:"thickness"`
+}
+
+// Returns how large a part of the gauge to fill, from 0 to 1
+func (g Gauge) fraction() float64 {
+	lo, hi := 0.0, 100.0
+	if g.Lim[0] != g.Lim[1] {
+		if !math.IsNaN(g.Lim[0]) {
+			lo = g.Lim[0]
+		}
+		if !math.IsNaN(g.Lim[1]) {
+			hi = g.Lim[1]
+		}
+	}
+	if hi <= lo {
+		hi = lo + 1
+	}
+
+	if math.IsNaN(g.Value) {
+		return 0
+	}
+	return math.Max(0, math.Min(1, (g.Value-lo)/(hi-lo)))
+}
+
+// Returns the fill and track colors
+func (g Gauge) colors() (color.Color, color.Color) {
+	var fill color.Color = DefaultPlotColor
+	if g.Color != nil {
+		fill = g.Color
+	}
+	for i, t := range g.Thresholds {
+		if i < len(g.ThresholdColors) && g.Value >= t {
+			fill = g.ThresholdColors[i]
+		}
+	}
+
+	track := dampenColor(fill, FillDampFactor)
+	if g.TrackColor != nil {
+		track = g.TrackColor
+	}
+
+	return fill, track
+}
+
+func (g Gauge) PaintBounds(bounds image.Rectangle, frameIdx int) image.Rectangle {
+	return image.Rect(0, 0, g.Width, g.Height)
+}
+
+func (g Gauge) Paint(dc *gg.Context, bounds image.Rectangle, frameIdx int) {
+	if g.ChartType == "linear" {
+		g.paintLinear(dc)
+	} else {
+		g.paintArc(dc)
+	}
+}
+
+func (g Gauge) paintLinear(dc *gg.Context) {
+	fill, track := g.colors()
+
+	dc.SetColor(track)
+	dc.DrawRectangle(0, 0, float64(g.Width), float64(g.Height))
+	dc.Fill()
+
+	dc.SetColor(fill)
+	if g.Height > g.Width {
+		h := int(math.Round(g.fraction() * float64(g.Height)))
+		dc.DrawRectangle(0, float64(g.Height-h), float64(g.Width), float64(h))
+	} else {
+		w := int(math.Round(g.fraction() * float64(g.Width)))
+		dc.DrawRectangle(0, 0, float64(w), float64(g.Height))
+	}
+	dc.Fill()
+}
+
+// The arc is a half circle, as large as will fit, standing on the
+// bottom edge. Each pixel is colored depending on whether its center
+// is inside the arc, and if so, which side of the value it falls on.
+func (g Gauge) paintArc(dc *gg.Context) {
+	fill, track := g.colors()
+
+	r := math.Min(float64(g.Width)/2, float64(g.Height))
+	cx, cy := float64(g.Width)/2, float64(g.Height)
+
+	thickness := float64(g.Thickness)
+	if thickness <= 0 {
+		thickness = math.Max(1, math.Round(r/4))
+	}
+
+	frac := g.fraction()
+	for y := g.Height - int(math.Ceil(r)); y < g.Height; y++ {
+		for x := 0; x < g.Width; x++ {
+			dx := float64(x) + 0.5 - cx
+			dy := cy - (float64(y) + 0.5)
+			dist := math.Hypot(dx, dy)
+			if dist > r || dist <= r-thickness {
+				continue
+			}
+
+			// 0 on the left, 1 on the right
+			pos := 1 - math.Atan2(dy, dx)/math.Pi
+			if pos <= frac {
+				dc.SetColor(fill)
+			} else {
+				dc.SetColor(track)
+			}
+
+			tx, ty := dc.TransformPoint(float64(x), float64(y))
+			dc.SetPixel(int(tx), int(ty))
+		}
+	}
+}
+
+func (g Gauge) FrameCount() int {
+	return 1
+}
@@ -0,0 +1,124 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGaugeLinear(t *testing.T) {
+	g := Gauge{
+		Value:      50,
+		Width:      4,
+		Height:     1,
+		Color:      color.RGBA{0xff, 0xff, 0xff, 0xff},
+		TrackColor: color.RGBA{0, 0, 0xff, 0xff},
+		ChartType:  "linear",
+	}
+	im := PaintWidget(g, image.Rect(0, 0, 100, 100), 0)
+	assert.Equal(t, nil, checkImage([]string{
+		"wwbb",
+	}, im))
+
+	// Values are clamped to the limits
+	g.Value = 17
+	g.Lim = [2]float64{Empty[0], 10}
+	im = PaintWidget(g, image.Rect(0, 0, 100, 100), 0)
+	assert.Equal(t, nil, checkImage([]string{
+		"wwww",
+	}, im))
+	g.Value = 3
+	g.Lim = [2]float64{4, 8}
+	im = PaintWidget(g, image.Rect(0, 0, 100, 100), 0)
+	assert.Equal(t, nil, checkImage([]string{
+		"bbbb",
+	}, im))
+
+	// Tall gauges fill from the bottom
+	g.Value = 25
+	g.Lim = Empty
+	g.Width, g.Height = 1, 4
+	im = PaintWidget(g, image.Rect(0, 0, 100, 100), 0)
+	assert.Equal(t, nil, checkImage([]string{
+		"b",
+		"b",
+		"b",
+		"w",
+	}, im))
+}
+
+func TestGaugeThresholds(t *testing.T) {
+	g := Gauge{
+		Width:      4,
+		Height:     1,
+		TrackColor: color.RGBA{0, 0, 0xff, 0xff},
+		Thresholds: []float64{50, 75},
+		ThresholdColors: []color.Color{
+			color.RGBA{0, 0xff, 0, 0xff},
+			color.RGBA{0xff, 0, 0, 0xff},
+		},
+		ChartType: "linear",
+	}
+
+	g.Value = 25
+	im := PaintWidget(g, image.Rect(0, 0, 100, 100), 0)
+	assert.Equal(t, nil, checkImage([]string{"wbbb"}, im))
+
+	g.Value = 50
+	im = PaintWidget(g, image.Rect(0, 0, 100, 100), 0)
+	assert.Equal(t, nil, checkImage([]string{"ggbb"}, im))
+
+	g.Value = 100
+	im = PaintWidget(g, image.Rect(0, 0, 100, 100), 0)
+	assert.Equal(t, nil, checkImage([]string{"rrrr"}, im))
+}
+
+func TestGaugeArc(t *testing.T) {
+	g := Gauge{
+		Value:      50,
+		Width:      8,
+		Height:     4,
+		Color:      color.RGBA{0xff, 0xff, 0xff, 0xff},
+		TrackColor: color.RGBA{0, 0, 0xff, 0xff},
+		Thickness:  1,
+	}
+	im := PaintWidget(g, image.Rect(0, 0, 100, 100), 0)
+	assert.Equal(t, nil, checkImage([]string{
+		"..wwbb..",
+		".w....b.",
+		"w......b",
+		"w......b",
+	}, im))
+
+	// By default, the arc is a quarter of the radius thick
+	g.Value = 100
+	g.Thickness = 0
+	g.Width, g.Height = 16, 8
+	im = PaintWidget(g, image.Rect(0, 0, 100, 100), 0)
+	assert.Equal(t, nil, checkImage([]string{
+		".....wwwwww.....",
+		"...wwwwwwwwww...",
+		"..wwww....wwww..",
+		".www........www.",
+		".ww..........ww.",
+		"www..........www",
+		"ww............ww",
+		"ww............ww",
+	}, im))
+
+	// Gauges taller than half their width stand on the bottom edge
+	g.Value = 50
+	g.Thickness = 1
+	g.Width, g.Height = 8, 6
+	im = PaintWidget(g, image.Rect(0, 0, 100, 100), 0)
+	assert.Equal(t, nil, checkImage([]string{
+		"........",
+		"........",
+		"..wwbb..",
+		".w....b.",
+		"w......b",
+		"w......b",
+	}, im))
+}
@@ -9,6 +9,7 @@ import (
 	"image/draw"
 	"image/gif"
 	"image/jpeg"
+	"math"
 
 	// register image formats
 	_ "image/jpeg"
@@ -18,14 +19,33 @@ import (
 	"github.com/srwiley/oksvg"
 	"github.com/srwiley/rasterx"
 	"github.com/tidbyt/gg"
+
+	"tidbyt.dev/pixlet/internal/dither"
 )
 
 // Image renders the binary image data passed via `src`. Supported
 // formats include PNG, JPEG, GIF, and SVG.
 //
 // If `width` or `height` are set, the image will be scaled
-// accordingly, with nearest neighbor interpolation. Otherwise the
-// image's original dimensions are used.
+// accordingly, with nearest neighbor interpolation unless another
+// `resample` mode is given. Otherwise the image's original dimensions
+// are used. When both are set, `fit` decides what happens if the
+// aspect ratio doesn't match: "fill" stretches the image, "contain"
+// scales it to fit inside the box and centers it, and "cover" scales
+// it to cover the box and cuts off what doesn't fit.
+//
+// Part of the source image can be selected with `crop`, a tuple of x,
+// y, width and height in source pixels. Cropping happens before
+// scaling.
+//
+// The image can also be adjusted to look better on an LED panel.
+// `brightness` and `contrast` range from -1 to 1, with 0 leaving the
+// image as is. `grayscale` removes all color, and `dither` reduces
+// each color channel to the given number of bits using Floyd-Steinberg
+// dithering, which avoids banding in gradients.
+//
+// All of this is done once, when the widget is created, so it adds no
+// cost to rendering frames.
 //
 // If the image data encodes an animated GIF, the Image instance will
 // also be animated. Frame delay (in milliseconds) can be read from
@@ -35,12 +55,27 @@ import (
 // DOC(Width): Scale image to this width
 // DOC(Height): Scale image to this height
 // DOC(Delay): (Read-only) Frame delay in ms, for animated GIFs
+// DOC(Resample): Interpolation used when scaling, "nearest", "bilinear", "bicubic" or "lanczos"
+// DOC(Fit): How to scale into width and height, "fill", "contain" or "cover", default is "fill"
+// DOC(Crop): Part of the source image to use, as (x, y, width, height)
+// DOC(Brightness): Brightness adjustment, from -1 to 1
+// DOC(Contrast): Contrast adjustment, from -1 to 1
+// DOC(Grayscale): Remove all color from the image
+// DOC(Dither): Dither to this many bits per color channel, default is no dithering
 type Image struct {
 	Widget
 	Src           string `starlark:"src,required"`
 	Width, Height int
 	Delay         int `starlark:"delay,readonly"`
 
+	Resample   string          `starlark:"resample"`
+	Fit        string          `starlark:"fit"`
+	Crop       image.Rectangle `starlark:"crop"`
+	Brightness float64         `starlark:"brightness"`
+	Contrast   float64         `starlark:"contrast"`
+	Grayscale  bool            `starlark:"grayscale"`
+	Dither     int             `starlark:"dither"`
+
 	imgs []image.Image
 }
 
@@ -174,24 +209,149 @@ func (p *Image) Init() error {
 		return err
 	}
 
+	if !p.Crop.Empty() {
+		for i := 0; i < len(p.imgs); i++ {
+			p.imgs[i] = cropImage(p.imgs[i], p.Crop)
+		}
+	}
+
+	if p.Width != 0 || p.Height != 0 {
+		if err := p.scale(); err != nil {
+			return err
+		}
+	}
+
+	if p.Brightness != 0 || p.Contrast != 0 || p.Grayscale || p.Dither > 0 {
+		for i := 0; i < len(p.imgs); i++ {
+			p.imgs[i] = p.adjust(p.imgs[i])
+		}
+	}
+
+	return nil
+}
+
+// Returns the part of img inside r, given relative to the top left
+// corner of img. The result is always positioned at 0, 0.
+func cropImage(img image.Image, r image.Rectangle) image.Image {
+	r = r.Add(img.Bounds().Min).Intersect(img.Bounds())
+
+	cropped := image.NewNRGBA(image.Rect(0, 0, r.Dx(), r.Dy()))
+	draw.Draw(cropped, cropped.Bounds(), img, r.Min, draw.Src)
+	return cropped
+}
+
+func (p *Image) scale() error {
+	var interp resize.InterpolationFunction
+	switch p.Resample {
+	case "", "nearest":
+		interp = resize.NearestNeighbor
+	case "bilinear":
+		interp = resize.Bilinear
+	case "bicubic":
+		interp = resize.Bicubic
+	case "lanczos":
+		interp = resize.Lanczos3
+	default:
+		return fmt.Errorf("unknown resample mode '%s'", p.Resample)
+	}
+
 	w := p.imgs[0].Bounds().Dx()
 	h := p.imgs[0].Bounds().Dy()
+	if w == 0 || h == 0 {
+		return nil
+	}
 
-	if p.Width != 0 || p.Height != 0 {
-		nw, nh := p.Width, p.Height
-		if nw == 0 {
-			// scale width, maintaining original aspect ratio
-			nw = int(float64(nh) * (float64(w) / float64(h)))
+	nw, nh := p.Width, p.Height
+	if nw == 0 {
+		// scale width, maintaining original aspect ratio
+		nw = int(float64(nh) * (float64(w) / float64(h)))
+	}
+	if nh == 0 {
+		// scale height, maintaining original aspect ratio
+		nh = int(float64(nw) * (float64(h) / float64(w)))
+	}
+
+	// the size to scale to, which differs from the final size of the
+	// image when only part of it is to be shown
+	sw, sh := nw, nh
+	sx := float64(nw) / float64(w)
+	sy := float64(nh) / float64(h)
+	switch p.Fit {
+	case "", "fill":
+	case "contain", "cover":
+		// only matters when both width and height are given
+		if p.Width == 0 || p.Height == 0 {
+			break
 		}
-		if nh == 0 {
-			// scale height, maintaining original aspect ratio
-			nh = int(float64(nw) * (float64(h) / float64(w)))
+
+		s := math.Min(sx, sy)
+		if p.Fit == "cover" {
+			s = math.Max(sx, sy)
 		}
+		sw = int(math.Round(float64(w) * s))
+		sh = int(math.Round(float64(h) * s))
+	default:
+		return fmt.Errorf("unknown fit mode '%s'", p.Fit)
+	}
 
-		for i := 0; i < len(p.imgs); i++ {
-			p.imgs[i] = resize.Resize(uint(nw), uint(nh), p.imgs[i], resize.NearestNeighbor)
+	for i := 0; i < len(p.imgs); i++ {
+		scaled := resize.Resize(uint(sw), uint(sh), p.imgs[i], interp)
+
+		if sw != nw || sh != nh {
+			// center the scaled image in the box, leaving it
+			// transparent around it, or cutting it off
+			boxed := image.NewNRGBA(image.Rect(0, 0, nw, nh))
+			offset := image.Pt((nw-sw)/2, (nh-sh)/2)
+			draw.Draw(boxed, scaled.Bounds().Add(offset), scaled, scaled.Bounds().Min, draw.Src)
+			scaled = boxed
 		}
+
+		p.imgs[i] = scaled
 	}
 
 	return nil
 }
+
+// Returns a copy of img with brightness, contrast, grayscale and
+// dithering applied.
+func (p *Image) adjust(img image.Image) image.Image {
+	b := img.Bounds()
+	adjusted := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(adjusted, adjusted.Bounds(), img, b.Min, draw.Src)
+
+	// contrast stretches values away from the middle, so that -1 makes
+	// everything gray and 1 makes everything either black or white
+	contrast := 1 + p.Contrast
+	if p.Contrast > 0 {
+		contrast = 1 / math.Max(1-p.Contrast, 1.0/255)
+	}
+
+	for i := 0; i < len(adjusted.Pix); i += 4 {
+		px := adjusted.Pix[i : i+3 : i+3]
+
+		if p.Grayscale {
+			// Rec. 601 luma
+			y := 0.299*float64(px[0]) + 0.587*float64(px[1]) + 0.114*float64(px[2])
+			px[0], px[1], px[2] = dither.Clamp(y), dither.Clamp(y), dither.Clamp(y)
+		}
+
+		for j := range px {
+			v := float64(px[j])/255 + p.Brightness
+			v = (v-0.5)*contrast + 0.5
+			px[j] = dither.Clamp(v * 255)
+		}
+	}
+
+	if p.Dither > 0 && p.Dither < 8 {
+		levels := float64(int(1)<<p.Dither - 1)
+		quantize := func(v uint8) uint8 {
+			return dither.Clamp(math.Round(float64(v)/255*levels) / levels * 255)
+		}
+
+		dither.FloydSteinberg(adjusted, func(c color.NRGBA) color.NRGBA {
+			return color.NRGBA{quantize(c.R), quantize(c.G), quantize(c.B), c.A}
+		})
+	}
+
+	return adjusted
+}
@@ -1,8 +1,12 @@
 package render
 
 import (
+	"bytes"
 	"encoding/base64"
 	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -104,6 +108,134 @@ func TestImageScaleAspectRatioHeight(t *testing.T) {
 	assert.Equal(t, 6, im.Bounds().Dy())
 }
 
+func TestImageCrop(t *testing.T) {
+	raw, _ := base64.StdEncoding.DecodeString(testPNG)
+	img := &Image{Src: string(raw), Crop: image.Rect(3, 4, 7, 7)}
+	assert.NoError(t, img.Init())
+
+	assert.Equal(t, nil, checkImage([]string{
+		".rr.",
+		"rrrr",
+		"rrrr",
+	}, PaintWidget(img, image.Rect(0, 0, 100, 100), 0)))
+
+	// crop is clipped to the image
+	img = &Image{Src: string(raw), Crop: image.Rect(8, 10, 20, 20)}
+	assert.NoError(t, img.Init())
+
+	assert.Equal(t, nil, checkImage([]string{
+		".r",
+		"rr",
+	}, PaintWidget(img, image.Rect(0, 0, 100, 100), 0)))
+}
+
+func TestImageFit(t *testing.T) {
+	raw, _ := base64.StdEncoding.DecodeString(testPNG)
+
+	// fill stretches the image to the box
+	img := &Image{Src: string(raw), Width: 10, Height: 6, Fit: "fill"}
+	assert.NoError(t, img.Init())
+	w, h := img.Size()
+	assert.Equal(t, 10, w)
+	assert.Equal(t, 6, h)
+
+	// cover keeps the scale, and cuts off top and bottom
+	img = &Image{Src: string(raw), Width: 10, Height: 6, Fit: "cover"}
+	assert.NoError(t, img.Init())
+	assert.Equal(t, nil, checkImage([]string{
+		"r...rr...r",
+		"r...rr...r",
+		"r.rrrrrr.r",
+		"r.rrrrrr.r",
+		"r...rr...r",
+		"r...rr...r",
+	}, PaintWidget(img, image.Rect(0, 0, 100, 100), 0)))
+
+	// contain leaves the sides transparent
+	img = &Image{Src: string(raw), Width: 14, Height: 12, Fit: "contain"}
+	assert.NoError(t, img.Init())
+	assert.Equal(t, nil, checkImage([]string{
+		"..rrrrrrrrrr..",
+		"..r........r..",
+		"..r...rr...r..",
+		"..r...rr...r..",
+		"..r...rr...r..",
+		"..r.rrrrrr.r..",
+		"..r.rrrrrr.r..",
+		"..r...rr...r..",
+		"..r...rr...r..",
+		"..r...rr...r..",
+		"..r........r..",
+		"..rrrrrrrrrr..",
+	}, PaintWidget(img, image.Rect(0, 0, 100, 100), 0)))
+
+	img = &Image{Src: string(raw), Width: 10, Height: 6, Fit: "squash"}
+	assert.Error(t, img.Init())
+
+	img = &Image{Src: string(raw), Width: 10, Resample: "sinc"}
+	assert.Error(t, img.Init())
+}
+
+func TestImageResample(t *testing.T) {
+	raw, _ := base64.StdEncoding.DecodeString(testPNG)
+
+	for _, mode := range []string{"nearest", "bilinear", "bicubic", "lanczos"} {
+		img := &Image{Src: string(raw), Width: 20, Resample: mode}
+		assert.NoError(t, img.Init())
+
+		w, h := img.Size()
+		assert.Equal(t, 20, w)
+		assert.Equal(t, 24, h)
+	}
+}
+
+func TestImageAdjust(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	draw.Draw(src, src.Bounds(), image.NewUniform(color.NRGBA{0xff, 0, 0, 0xff}), image.Point{}, draw.Src)
+	buf := &bytes.Buffer{}
+	assert.NoError(t, png.Encode(buf, src))
+
+	// lifting brightness all the way turns red into white
+	img := &Image{Src: buf.String(), Brightness: 1}
+	assert.NoError(t, img.Init())
+	assert.Equal(t, color.NRGBA{0xff, 0xff, 0xff, 0xff}, color.NRGBAModel.Convert(img.imgs[0].At(0, 0)))
+
+	// no contrast at all leaves only gray
+	img = &Image{Src: buf.String(), Contrast: -1}
+	assert.NoError(t, img.Init())
+	assert.Equal(t, color.NRGBA{0x80, 0x80, 0x80, 0xff}, color.NRGBAModel.Convert(img.imgs[0].At(0, 0)))
+
+	img = &Image{Src: buf.String(), Grayscale: true}
+	assert.NoError(t, img.Init())
+	assert.Equal(t, color.NRGBA{76, 76, 76, 0xff}, color.NRGBAModel.Convert(img.imgs[0].At(0, 0)))
+}
+
+func TestImageDither(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 8, 8))
+	draw.Draw(src, src.Bounds(), image.NewUniform(color.NRGBA{0x80, 0x80, 0x80, 0xff}), image.Point{}, draw.Src)
+	buf := &bytes.Buffer{}
+	assert.NoError(t, png.Encode(buf, src))
+
+	// with 1 bit per channel, mid gray turns into an even mix of
+	// black and white pixels
+	img := &Image{Src: buf.String(), Dither: 1}
+	assert.NoError(t, img.Init())
+
+	white := 0
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			c := color.NRGBAModel.Convert(img.imgs[0].At(x, y)).(color.NRGBA)
+			assert.Contains(t, []uint8{0, 0xff}, c.R)
+			assert.Equal(t, c.R, c.G)
+			assert.Equal(t, c.R, c.B)
+			if c.R == 0xff {
+				white++
+			}
+		}
+	}
+	assert.InDelta(t, 32, white, 2)
+}
+
 func TestImageAnimatedGif(t *testing.T) {
 	// Animated 5x4 GIF with 4 frames:
 	//
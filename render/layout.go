@@ -0,0 +1,247 @@
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"reflect"
+
+	"github.com/tidbyt/gg"
+)
+
+// LayoutNode describes where a widget ended up when a frame was
+// painted, as recorded by InspectLayout.
+type LayoutNode struct {
+	// Type is the type of the widget, e.g. render.Box
+	Type string
+
+	// Bounds is the area returned by the widget's PaintBounds, in
+	// frame coordinates. If the widget was painted more than once,
+	// it covers all of them.
+	Bounds image.Rectangle
+
+	// Painted is false if the widget wasn't painted in the frame,
+	// e.g. a child of a Sequence that isn't showing yet.
+	Painted bool
+
+	FrameCount int
+	Children   []*LayoutNode
+}
+
+func (n *LayoutNode) MarshalJSON() ([]byte, error) {
+	type bounds struct {
+		X      int `json:"x"`
+		Y      int `json:"y"`
+		Width  int `json:"width"`
+		Height int `json:"height"`
+	}
+
+	return json.Marshal(struct {
+		Type       string        `json:"type"`
+		Bounds     bounds        `json:"bounds"`
+		Painted    bool          `json:"painted"`
+		FrameCount int           `json:"frame_count"`
+		Children   []*LayoutNode `json:"children,omitempty"`
+	}{
+		Type:       n.Type,
+		Bounds:     bounds{n.Bounds.Min.X, n.Bounds.Min.Y, n.Bounds.Dx(), n.Bounds.Dy()},
+		Painted:    n.Painted,
+		FrameCount: n.FrameCount,
+		Children:   n.Children,
+	})
+}
+
+// InspectLayout paints a single frame of r, recording the bounds of
+// every widget in the tree as it's painted. It returns the layout of
+// the root's child along with the painted frame.
+//
+// The tree itself is left untouched; widgets are painted from a copy.
+func InspectLayout(r Root, frameIdx int) (*LayoutNode, image.Image) {
+	updateFrameSize()
+
+	node := &LayoutNode{}
+	r.Child = probeLayout(r.Child, node)
+
+	return node, r.paintFrame(true, frameIdx)
+}
+
+// InspectRootsLayout is like InspectLayout, but frameIdx counts the
+// frames of every root in turn, the way they are shown one after the
+// other.
+func InspectRootsLayout(roots []Root, frameIdx int) (*LayoutNode, image.Image, error) {
+	idx := frameIdx
+	for _, r := range roots {
+		if idx < 0 {
+			break
+		}
+		if n := r.Child.FrameCount(); idx >= n {
+			idx -= n
+			continue
+		}
+
+		node, frame := InspectLayout(r, idx)
+		return node, frame, nil
+	}
+
+	return nil, nil, fmt.Errorf("layout frame %d out of range", frameIdx)
+}
+
+// layoutProbe wraps a widget to record where it's painted.
+type layoutProbe struct {
+	Widget
+	node *LayoutNode
+}
+
+func (p *layoutProbe) Paint(dc *gg.Context, bounds image.Rectangle, frameIdx int) {
+	pb := transformRect(dc, p.Widget.PaintBounds(bounds, frameIdx))
+	if p.node.Painted {
+		pb = pb.Union(p.node.Bounds)
+	}
+	p.node.Bounds = pb
+	p.node.Painted = true
+
+	p.Widget.Paint(dc, bounds, frameIdx)
+}
+
+// probeLayout returns a copy of w whose children, and w itself, are
+// wrapped in layout probes writing to node.
+func probeLayout(w Widget, node *LayoutNode) Widget {
+	node.Type = widgetTypeName(w)
+	node.FrameCount = w.FrameCount()
+
+	v := reflect.ValueOf(w)
+	var copied reflect.Value
+	switch {
+	case v.Kind() == reflect.Ptr && v.Elem().Kind() == reflect.Struct:
+		copied = reflect.New(v.Elem().Type())
+		copied.Elem().Set(v.Elem())
+	case v.Kind() == reflect.Struct:
+		copied = reflect.New(v.Type())
+		copied.Elem().Set(v)
+	default:
+		return &layoutProbe{Widget: w, node: node}
+	}
+
+	s := copied.Elem()
+	for i := 0; i < s.NumField(); i++ {
+		field := s.Type().Field(i)
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+
+		switch field.Type {
+		case widgetType:
+			if child, ok := s.Field(i).Interface().(Widget); ok {
+				s.Field(i).Set(reflect.ValueOf(probeChild(child, node)))
+			}
+
+		case widgetSliceType:
+			children := s.Field(i).Interface().([]Widget)
+			probed := make([]Widget, len(children))
+			for j, child := range children {
+				probed[j] = probeChild(child, node)
+			}
+			s.Field(i).Set(reflect.ValueOf(probed))
+		}
+	}
+
+	if v.Kind() == reflect.Struct {
+		copied = s
+	}
+	return &layoutProbe{Widget: copied.Interface().(Widget), node: node}
+}
+
+func probeChild(child Widget, parent *LayoutNode) Widget {
+	node := &LayoutNode{}
+	parent.Children = append(parent.Children, node)
+	return probeLayout(child, node)
+}
+
+func widgetTypeName(w Widget) string {
+	t := reflect.TypeOf(w)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.String()
+}
+
+// transformRect returns the smallest rectangle holding r after it's
+// been transformed by the current matrix of dc.
+func transformRect(dc *gg.Context, r image.Rectangle) image.Rectangle {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+
+	for _, p := range []image.Point{r.Min, {r.Max.X, r.Min.Y}, {r.Min.X, r.Max.Y}, r.Max} {
+		x, y := dc.TransformPoint(float64(p.X), float64(p.Y))
+		minX, minY = math.Min(minX, x), math.Min(minY, y)
+		maxX, maxY = math.Max(maxX, x), math.Max(maxY, y)
+	}
+
+	// allow for rounding errors of rotations
+	const eps = 1e-6
+	return image.Rect(
+		int(math.Floor(minX+eps)),
+		int(math.Floor(minY+eps)),
+		int(math.Ceil(maxX-eps)),
+		int(math.Ceil(maxY-eps)),
+	)
+}
+
+// Outline colors, by depth in the tree
+var layoutColors = []color.RGBA{
+	{0xff, 0x40, 0x40, 0xff},
+	{0x40, 0xff, 0x40, 0xff},
+	{0x40, 0x80, 0xff, 0xff},
+	{0xff, 0xff, 0x40, 0xff},
+	{0xff, 0x40, 0xff, 0xff},
+	{0x40, 0xff, 0xff, 0xff},
+}
+
+// LayoutOverlay magnifies frame by scale and outlines the bounds of
+// every painted widget in layout on top of it. Outlines are colored by
+// how deep the widget is in the tree, and children are drawn over
+// their parents.
+func LayoutOverlay(frame image.Image, layout *LayoutNode, scale int) *image.RGBA {
+	if scale < 1 {
+		scale = 1
+	}
+
+	fb := frame.Bounds()
+	out := image.NewRGBA(image.Rect(0, 0, fb.Dx()*scale, fb.Dy()*scale))
+	for y := 0; y < out.Bounds().Dy(); y++ {
+		for x := 0; x < out.Bounds().Dx(); x++ {
+			out.Set(x, y, frame.At(fb.Min.X+x/scale, fb.Min.Y+y/scale))
+		}
+	}
+
+	var outline func(n *LayoutNode, depth int)
+	outline = func(n *LayoutNode, depth int) {
+		if n.Painted && !n.Bounds.Empty() {
+			r := image.Rect(
+				n.Bounds.Min.X*scale,
+				n.Bounds.Min.Y*scale,
+				n.Bounds.Max.X*scale,
+				n.Bounds.Max.Y*scale,
+			)
+			c := image.NewUniform(layoutColors[depth%len(layoutColors)])
+			for _, edge := range []image.Rectangle{
+				image.Rect(r.Min.X, r.Min.Y, r.Max.X, r.Min.Y+1),
+				image.Rect(r.Min.X, r.Max.Y-1, r.Max.X, r.Max.Y),
+				image.Rect(r.Min.X, r.Min.Y, r.Min.X+1, r.Max.Y),
+				image.Rect(r.Max.X-1, r.Min.Y, r.Max.X, r.Max.Y),
+			} {
+				draw.Draw(out, edge, c, image.Point{}, draw.Src)
+			}
+		}
+
+		for _, child := range n.Children {
+			outline(child, depth+1)
+		}
+	}
+	outline(layout, 0)
+
+	return out
+}
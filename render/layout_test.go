@@ -0,0 +1,130 @@
+package render
+
+import (
+	"encoding/json"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspectLayout(t *testing.T) {
+	box := &Box{Width: 3, Height: 3, Color: color.White}
+	tree := &Row{
+		Children: []Widget{
+			&Box{Width: 2, Height: 2},
+			&Padding{Pad: Insets{Left: 1, Top: 2}, Child: box},
+			&Sequence{Children: []Widget{
+				&Box{Width: 1, Height: 1},
+				&Box{Width: 5, Height: 5},
+			}},
+		},
+	}
+
+	layout, frame := InspectLayout(Root{Child: tree}, 0)
+
+	assert.Equal(t, "render.Row", layout.Type)
+	assert.Equal(t, image.Rect(0, 0, 7, 5), layout.Bounds)
+	assert.True(t, layout.Painted)
+	assert.Equal(t, 2, layout.FrameCount)
+	require.Equal(t, 3, len(layout.Children))
+
+	assert.Equal(t, image.Rect(0, 0, 2, 2), layout.Children[0].Bounds)
+
+	padding := layout.Children[1]
+	assert.Equal(t, "render.Padding", padding.Type)
+	assert.Equal(t, image.Rect(2, 0, 6, 5), padding.Bounds)
+	require.Equal(t, 1, len(padding.Children))
+	assert.Equal(t, image.Rect(3, 2, 6, 5), padding.Children[0].Bounds)
+
+	// only the first child of the sequence is shown in frame 0
+	seq := layout.Children[2]
+	require.Equal(t, 2, len(seq.Children))
+	assert.True(t, seq.Children[0].Painted)
+	assert.Equal(t, image.Rect(6, 0, 7, 1), seq.Children[0].Bounds)
+	assert.False(t, seq.Children[1].Painted)
+
+	// the frame is painted as usual
+	assert.Equal(t, color.RGBA{0xff, 0xff, 0xff, 0xff}, frame.At(3, 2))
+	assert.Equal(t, color.RGBA{0, 0, 0, 0xff}, frame.At(2, 2))
+
+	// and the tree is left alone
+	assert.Same(t, box, tree.Children[1].(*Padding).Child)
+
+	layout, _ = InspectLayout(Root{Child: tree}, 1)
+	assert.False(t, layout.Children[2].Children[0].Painted)
+	assert.Equal(t, image.Rect(6, 0, 11, 5), layout.Children[2].Children[1].Bounds)
+}
+
+func TestLayoutJSON(t *testing.T) {
+	layout := &LayoutNode{
+		Type:       "render.Padding",
+		Bounds:     image.Rect(1, 2, 4, 6),
+		Painted:    true,
+		FrameCount: 1,
+		Children: []*LayoutNode{{
+			Type:       "render.Text",
+			FrameCount: 1,
+		}},
+	}
+
+	data, err := json.Marshal(layout)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "render.Padding",
+		"bounds": {"x": 1, "y": 2, "width": 3, "height": 4},
+		"painted": true,
+		"frame_count": 1,
+		"children": [{
+			"type": "render.Text",
+			"bounds": {"x": 0, "y": 0, "width": 0, "height": 0},
+			"painted": false,
+			"frame_count": 1
+		}]
+	}`, string(data))
+}
+
+func TestLayoutOverlay(t *testing.T) {
+	tree := &Padding{Pad: Insets{Left: 1, Top: 1, Right: 1, Bottom: 1}, Child: &Box{Width: 2, Height: 2}}
+
+	layout, frame := InspectLayout(Root{Child: tree}, 0)
+	overlay := LayoutOverlay(frame, layout, 2)
+
+	assert.Equal(t, image.Rect(0, 0, FrameWidth*2, FrameHeight*2), overlay.Bounds())
+
+	// the padding is outlined at depth 0, the box inside it at depth 1
+	assert.Equal(t, layoutColors[0], overlay.At(0, 0))
+	assert.Equal(t, layoutColors[0], overlay.At(7, 7))
+	assert.Equal(t, layoutColors[1], overlay.At(2, 2))
+	assert.Equal(t, layoutColors[1], overlay.At(5, 5))
+	assert.Equal(t, color.RGBA{0, 0, 0, 0xff}, overlay.At(3, 3))
+	assert.Equal(t, color.RGBA{0, 0, 0, 0xff}, overlay.At(6, 6))
+}
+
+func TestInspectRootsLayout(t *testing.T) {
+	roots := []Root{
+		{Child: &Sequence{Children: []Widget{
+			&Box{Width: 1, Height: 1},
+			&Box{Width: 2, Height: 2},
+		}}},
+		{Child: &Box{Width: 3, Height: 3}},
+	}
+
+	layout, _, err := InspectRootsLayout(roots, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "render.Sequence", layout.Type)
+	assert.Equal(t, image.Rect(0, 0, 2, 2), layout.Bounds)
+
+	// frames of later roots come after those of earlier ones
+	layout, _, err = InspectRootsLayout(roots, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "render.Box", layout.Type)
+	assert.Equal(t, image.Rect(0, 0, 3, 3), layout.Bounds)
+
+	_, _, err = InspectRootsLayout(roots, 3)
+	assert.Error(t, err)
+	_, _, err = InspectRootsLayout(roots, -1)
+	assert.Error(t, err)
+}
@@ -34,7 +34,7 @@ import (
 // DOC(OffsetEnd): Position of child at end of animation
 // DOC(ScrollDirection): Direction to scroll, 'vertical' or 'horizontal', default is horizontal
 // DOC(Align): Alignment when contents fit on screen, 'start', 'center' or 'end', default is start
-// DOC(Delay): Delay the scroll of the animation by a certain number of frames, default is 0
+// DOC(Delay): Delay the scroll of the animation by a certain number of frames, or milliseconds if the root is time based, default is 0
 //
 // EXAMPLE BEGIN
 // render.Marquee(
@@ -54,6 +54,12 @@ type Marquee struct {
 	ScrollDirection string `starlark:"scroll_direction"`
 	Align           string `starlark:"align"`
 	Delay           int    `starlark:"delay"`
+
+	frameDelay int
+}
+
+func (m *Marquee) SetFrameDelay(delay int) {
+	m.frameDelay = delay
 }
 
 func (m Marquee) PaintBounds(bounds image.Rectangle, frameIdx int) image.Rectangle {
@@ -100,7 +106,7 @@ func (m Marquee) FrameCount() int {
 		offend = -cw
 	}
 
-	delay := m.Delay
+	delay := DurationFrames(m.Delay, m.frameDelay)
 	// If start and end offsets are identical, do not
 	// repeat these identical frames after another.
 	if offstart == offend {
@@ -136,7 +142,7 @@ func (m Marquee) Paint(dc *gg.Context, bounds image.Rectangle, frameIdx int) {
 		offend = -cw
 	}
 
-	delay := m.Delay
+	delay := DurationFrames(m.Delay, m.frameDelay)
 	loopIdx := cw + offstart + delay
 	endIdx := cw + offstart + size - offend + delay
 
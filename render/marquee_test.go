@@ -705,3 +705,34 @@ func TestMarqueeVerticalScroll(t *testing.T) {
 	assert.Equal(t, nil, checkImage([]string{".", ".", ".", ".", ".", "."}, PaintWidget(m, im, 9)))
 	assert.Equal(t, nil, checkImage([]string{".", ".", ".", ".", ".", "."}, PaintWidget(m, im, 1024)))
 }
+
+func TestMarqueeTimeBasedDelay(t *testing.T) {
+	child := Row{
+		Children: []Widget{
+			Box{Width: 1, Height: 1, Color: color.RGBA{0xff, 0, 0, 0xff}},
+			Box{Width: 2, Height: 1, Color: color.RGBA{0, 0xff, 0, 0xff}},
+			Box{Width: 4, Height: 1, Color: color.RGBA{0, 0, 0xff, 0xff}},
+		},
+	}
+	m := &Marquee{
+		Width:       6,
+		Child:       child,
+		Delay:       100,
+		OffsetStart: 2,
+	}
+	im := image.Rect(0, 0, 100, 100)
+
+	// 100ms at 50ms per frame is the same as a delay of 2 frames
+	root := Root{Child: m, Delay: 50, TimeBased: true}
+	assert.NoError(t, root.Init())
+	assert.Equal(t, 18, m.FrameCount())
+	assert.Equal(t, nil, checkImage([]string{"..rggb"}, PaintWidget(m, im, 2)))
+	assert.Equal(t, nil, checkImage([]string{".rggbb"}, PaintWidget(m, im, 3)))
+
+	// at 25ms per frame, the delay takes twice as many frames
+	root.Delay = 25
+	assert.NoError(t, root.Init())
+	assert.Equal(t, 20, m.FrameCount())
+	assert.Equal(t, nil, checkImage([]string{"..rggb"}, PaintWidget(m, im, 4)))
+	assert.Equal(t, nil, checkImage([]string{".rggbb"}, PaintWidget(m, im, 5)))
+}
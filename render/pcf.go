@@ -0,0 +1,359 @@
+package render
+
+import (
+	"encoding/binary"
+	"fmt"
+	"image"
+	"math/bits"
+
+	"github.com/zachomedia/go-bdf"
+)
+
+// PCF is the compiled form of BDF that X11 font servers use. The format is
+// described at https://fontforge.org/docs/techref/pcf-format.html.
+
+const (
+	pcfMagic = "\x01fcp"
+
+	pcfAccelerators    = 1 << 1
+	pcfMetrics         = 1 << 2
+	pcfBitmaps         = 1 << 3
+	pcfBDFEncodings    = 1 << 5
+	pcfBDFAccelerators = 1 << 8
+
+	pcfGlyphPadMask      = 3 << 0
+	pcfByteMask          = 1 << 2
+	pcfBitMask           = 1 << 3
+	pcfScanUnitMask      = 3 << 4
+	pcfCompressedMetrics = 0x100
+
+	pcfNoGlyph = 0xffff
+)
+
+type pcfTable struct {
+	format uint32
+	size   uint32
+	offset uint32
+}
+
+type pcfMetric struct {
+	left, right, width, ascent, descent int
+}
+
+// pcfReader reads values from a PCF table in the table's byte order.
+type pcfReader struct {
+	data   []byte
+	pos    int
+	format uint32
+	order  binary.ByteOrder
+	err    error
+}
+
+func newPCFReader(data []byte, table pcfTable) (*pcfReader, error) {
+	end := int(table.offset) + int(table.size)
+	if int(table.offset) < 0 || end > len(data) || table.size < 4 {
+		return nil, fmt.Errorf("table out of bounds")
+	}
+
+	r := &pcfReader{data: data[table.offset:end]}
+
+	// the format is always little endian, and has to match the one in
+	// the table of contents
+	r.format = binary.LittleEndian.Uint32(r.data)
+	r.pos = 4
+	if r.format != table.format {
+		return nil, fmt.Errorf("table format mismatch")
+	}
+
+	if r.format&pcfByteMask != 0 {
+		r.order = binary.BigEndian
+	} else {
+		r.order = binary.LittleEndian
+	}
+
+	return r, nil
+}
+
+func (r *pcfReader) bytes(n int) []byte {
+	if n < 0 {
+		r.err = fmt.Errorf("invalid length %d", n)
+		return nil
+	}
+	if r.err != nil || r.pos+n > len(r.data) {
+		if r.err == nil {
+			r.err = fmt.Errorf("unexpected end of table")
+		}
+		return make([]byte, n)
+	}
+
+	b := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return b
+}
+
+func (r *pcfReader) uint8() int {
+	return int(r.bytes(1)[0])
+}
+
+func (r *pcfReader) int16() int {
+	return int(int16(r.order.Uint16(r.bytes(2))))
+}
+
+func (r *pcfReader) uint16() int {
+	return int(r.order.Uint16(r.bytes(2)))
+}
+
+func (r *pcfReader) int32() int {
+	return int(int32(r.order.Uint32(r.bytes(4))))
+}
+
+// parsePCF parses a PCF font into the same structure as BDF fonts, so that
+// it can be drawn the same way. Glyphs are assumed to be encoded as Unicode
+// code points.
+func parsePCF(data []byte) (*bdf.Font, error) {
+	if len(data) < 8 || string(data[:4]) != pcfMagic {
+		return nil, fmt.Errorf("not a PCF font")
+	}
+
+	count := int(binary.LittleEndian.Uint32(data[4:]))
+	if count < 0 || 8+count*16 > len(data) {
+		return nil, fmt.Errorf("invalid table count %d", count)
+	}
+
+	tables := map[uint32]pcfTable{}
+	for i := 0; i < count; i++ {
+		entry := data[8+i*16:]
+		tables[binary.LittleEndian.Uint32(entry)] = pcfTable{
+			format: binary.LittleEndian.Uint32(entry[4:]),
+			size:   binary.LittleEndian.Uint32(entry[8:]),
+			offset: binary.LittleEndian.Uint32(entry[12:]),
+		}
+	}
+
+	f := &bdf.Font{
+		CharMap: map[rune]*bdf.Character{},
+		BPP:     1,
+	}
+
+	if err := parsePCFAccelerators(data, tables, f); err != nil {
+		return nil, fmt.Errorf("reading accelerators: %w", err)
+	}
+
+	metrics, err := parsePCFMetrics(data, tables)
+	if err != nil {
+		return nil, fmt.Errorf("reading metrics: %w", err)
+	}
+
+	glyphs, err := parsePCFBitmaps(data, tables, metrics)
+	if err != nil {
+		return nil, fmt.Errorf("reading bitmaps: %w", err)
+	}
+
+	f.Characters = make([]bdf.Character, len(metrics))
+	for i, m := range metrics {
+		f.Characters[i] = bdf.Character{
+			Advance:    [2]int{m.width, 0},
+			Alpha:      glyphs[i],
+			LowerPoint: [2]int{m.left, -m.descent},
+		}
+	}
+
+	if err := parsePCFEncodings(data, tables, f); err != nil {
+		return nil, fmt.Errorf("reading encodings: %w", err)
+	}
+
+	return f, nil
+}
+
+func parsePCFAccelerators(data []byte, tables map[uint32]pcfTable, f *bdf.Font) error {
+	table, ok := tables[pcfBDFAccelerators]
+	if !ok {
+		table, ok = tables[pcfAccelerators]
+	}
+	if !ok {
+		return fmt.Errorf("missing accelerator table")
+	}
+
+	r, err := newPCFReader(data, table)
+	if err != nil {
+		return err
+	}
+
+	// skip the boolean flags and padding
+	r.bytes(8)
+
+	f.Ascent = r.int32()
+	f.Descent = r.int32()
+	return r.err
+}
+
+func parsePCFMetrics(data []byte, tables map[uint32]pcfTable) ([]pcfMetric, error) {
+	table, ok := tables[pcfMetrics]
+	if !ok {
+		return nil, fmt.Errorf("missing metrics table")
+	}
+
+	r, err := newPCFReader(data, table)
+	if err != nil {
+		return nil, err
+	}
+
+	var count int
+	if r.format&pcfCompressedMetrics != 0 {
+		count = r.int16()
+	} else {
+		count = r.int32()
+	}
+	if count < 0 || count > len(r.data) {
+		return nil, fmt.Errorf("invalid glyph count %d", count)
+	}
+
+	metrics := make([]pcfMetric, count)
+	if r.format&pcfCompressedMetrics != 0 {
+		for i := range metrics {
+			metrics[i] = pcfMetric{
+				left:    r.uint8() - 0x80,
+				right:   r.uint8() - 0x80,
+				width:   r.uint8() - 0x80,
+				ascent:  r.uint8() - 0x80,
+				descent: r.uint8() - 0x80,
+			}
+		}
+	} else {
+		for i := range metrics {
+			metrics[i] = pcfMetric{
+				left:    r.int16(),
+				right:   r.int16(),
+				width:   r.int16(),
+				ascent:  r.int16(),
+				descent: r.int16(),
+			}
+			// attributes
+			r.uint16()
+		}
+	}
+
+	return metrics, r.err
+}
+
+func parsePCFBitmaps(data []byte, tables map[uint32]pcfTable, metrics []pcfMetric) ([]*image.Alpha, error) {
+	table, ok := tables[pcfBitmaps]
+	if !ok {
+		return nil, fmt.Errorf("missing bitmap table")
+	}
+
+	r, err := newPCFReader(data, table)
+	if err != nil {
+		return nil, err
+	}
+
+	count := r.int32()
+	if r.err != nil {
+		return nil, r.err
+	}
+	if count != len(metrics) {
+		return nil, fmt.Errorf("have %d bitmaps for %d glyphs", count, len(metrics))
+	}
+
+	offsets := make([]int, count)
+	for i := range offsets {
+		offsets[i] = r.int32()
+	}
+
+	var sizes [4]int
+	for i := range sizes {
+		sizes[i] = r.int32()
+	}
+
+	pad := 1 << (r.format & pcfGlyphPadMask)
+	unit := 1 << ((r.format & pcfScanUnitMask) >> 4)
+	bitmaps := append([]byte{}, r.bytes(sizes[r.format&pcfGlyphPadMask])...)
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	// normalize to most significant bit and byte first
+	if r.format&pcfBitMask == 0 {
+		for i, b := range bitmaps {
+			bitmaps[i] = bits.Reverse8(b)
+		}
+	}
+	if (r.format&pcfByteMask == 0) != (r.format&pcfBitMask == 0) && unit > 1 {
+		for i := 0; i+unit <= len(bitmaps); i += unit {
+			for j := 0; j < unit/2; j++ {
+				bitmaps[i+j], bitmaps[i+unit-1-j] = bitmaps[i+unit-1-j], bitmaps[i+j]
+			}
+		}
+	}
+
+	glyphs := make([]*image.Alpha, count)
+	for i, m := range metrics {
+		w := m.right - m.left
+		h := m.ascent + m.descent
+		if w < 0 || h < 0 {
+			return nil, fmt.Errorf("invalid size for glyph %d", i)
+		}
+
+		stride := (w + 7) / 8
+		stride = (stride + pad - 1) / pad * pad
+		if offsets[i] < 0 || offsets[i]+stride*h > len(bitmaps) {
+			return nil, fmt.Errorf("bitmap for glyph %d out of bounds", i)
+		}
+
+		alpha := &image.Alpha{
+			Stride: w,
+			Rect:   image.Rect(0, 0, w, h),
+			Pix:    make([]byte, w*h),
+		}
+		for y := 0; y < h; y++ {
+			row := bitmaps[offsets[i]+y*stride:]
+			for x := 0; x < w; x++ {
+				if row[x/8]&(0x80>>(x%8)) != 0 {
+					alpha.Pix[y*w+x] = 0xff
+				}
+			}
+		}
+		glyphs[i] = alpha
+	}
+
+	return glyphs, nil
+}
+
+func parsePCFEncodings(data []byte, tables map[uint32]pcfTable, f *bdf.Font) error {
+	table, ok := tables[pcfBDFEncodings]
+	if !ok {
+		return fmt.Errorf("missing encoding table")
+	}
+
+	r, err := newPCFReader(data, table)
+	if err != nil {
+		return err
+	}
+
+	minByte2 := r.int16()
+	maxByte2 := r.int16()
+	minByte1 := r.int16()
+	maxByte1 := r.int16()
+	f.DefaultChar = rune(r.int16())
+
+	for b1 := minByte1; b1 <= maxByte1; b1++ {
+		for b2 := minByte2; b2 <= maxByte2; b2++ {
+			idx := r.uint16()
+			if r.err != nil {
+				return r.err
+			}
+			if idx == pcfNoGlyph {
+				continue
+			}
+			if idx >= len(f.Characters) {
+				return fmt.Errorf("glyph index %d out of range", idx)
+			}
+
+			c := &f.Characters[idx]
+			c.Encoding = rune(b1<<8 | b2)
+			f.CharMap[c.Encoding] = c
+		}
+	}
+
+	return nil
+}
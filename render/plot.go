@@ -13,7 +13,18 @@ var DefaultPlotColor = color.RGBA{0xff, 0xff, 0xff, 0xff}
 // surface fill gets line color dampened by this factor
 var FillDampFactor uint8 = 0x55
 
-// Plot is a widget that draws a data series.
+// Plot is a widget that draws one or more data series.
+//
+// The points in `data` are drawn as the first series. To draw more,
+// pass a list of `render.Series` as `series`, each with its own data,
+// colors and chart type. All series share the same limits, which
+// fit all of them unless set with `x_lim` and `y_lim`.
+//
+// With `axes`, the X and Y axes are drawn where they cross 0, or
+// along the edge of the plot if 0 is out of view. `x_ticks` and
+// `y_ticks` mark every multiple of a value along the axes, and with
+// `grid`, lines are drawn across the plot at each of them. Ticks
+// closer together than a pixel are left out.
 //
 // DOC(Data): A list of 2-tuples of numbers
 // DOC(Width): Limits Plot width
@@ -26,6 +37,13 @@ var FillDampFactor uint8 = 0x55
 // DOC(FillColor): Fill color for Y-values above 0
 // DOC(FillColorInverted): Fill color for Y-values below 0
 // DOC(ChartType): Specifies the type of chart to render, "scatter" or "line", default is "line"
+// DOC(Series): Additional series to draw
+// DOC(Axes): Draw the X and Y axes
+// DOC(AxisColor): Color of axes and ticks
+// DOC(XTicks): Distance between ticks along the X axis
+// DOC(YTicks): Distance between ticks along the Y axis
+// DOC(Grid): Draw gridlines at every tick
+// DOC(GridColor): Color of gridlines
 //
 // EXAMPLE BEGIN
 // render.Plot(
@@ -50,6 +68,25 @@ var FillDampFactor uint8 = 0x55
 //   fill = True,
 // ),
 // EXAMPLE END
+//
+// EXAMPLE BEGIN
+// render.Plot(
+//   data = [(0, 1), (1, 3), (2, 2), (3, 5), (4, 4), (5, 6)],
+//   series = [
+//     render.Series(
+//       data = [(0, 4), (1, 2), (2, 3), (3, 1), (4, 2), (5, 0)],
+//       color = "#f80",
+//     ),
+//   ],
+//   width = 64,
+//   height = 32,
+//   color = "#0af",
+//   axes = True,
+//   x_ticks = 1,
+//   y_ticks = 2,
+//   grid = True,
+// )
+// EXAMPLE END
 type Plot struct {
 	Widget
 
@@ -82,9 +119,71 @@ type Plot struct {
 	// Optional fill color for Y-values below 0
 	FillColorInverted color.Color `starlark:"fill_color_inverted"`
 
+	// Optional series to draw in addition to Data
+	Series []Series `starlark:"series"`
+
+	// If true, draw X and Y axes
+	Axes      bool        `starlark:"axes"`
+	AxisColor color.Color `starlark:"axis_color"`
+
+	// Optional distance between ticks on the axes
+	XTicks float64 `starlark:"x_ticks"`
+	YTicks float64 `starlark:"y_ticks"`
+
+	// If true, draw gridlines at every tick
+	Grid      bool        `starlark:"grid"`
+	GridColor color.Color `starlark:"grid_color"`
+
 	invThreshold int
 }
 
+// Series is a data series drawn by Plot, in addition to the one in
+// its `data`.
+//
+// DOC(Data): A list of 2-tuples of numbers
+// DOC(Name): Name of the series
+// DOC(Color): Line color, default is '#fff'
+// DOC(ColorInverted): Line color for Y-values below 0
+// DOC(Fill): Paint surface between line and X-axis
+// DOC(FillColor): Fill color for Y-values above 0
+// DOC(FillColorInverted): Fill color for Y-values below 0
+// DOC(ChartType): Specifies the type of chart to render, "scatter" or "line", default is "line"
+type Series struct {
+	Data              [][2]float64 `starlark:"data,required"`
+	Name              string       `starlark:"name"`
+	Color             color.Color  `starlark:"color"`
+	ColorInverted     color.Color  `starlark:"color_inverted"`
+	Fill              bool         `starlark:"fill"`
+	FillColor         color.Color  `starlark:"fill_color"`
+	FillColorInverted color.Color  `starlark:"fill_color_inverted"`
+	ChartType         string       `starlark:"chart_type"`
+}
+
+var DefaultAxisColor = color.RGBA{0x66, 0x66, 0x66, 0xff}
+var DefaultGridColor = color.RGBA{0x22, 0x22, 0x22, 0xff}
+
+// Returns all series to draw, starting with the one in Data
+func (p *Plot) allSeries() []Series {
+	series := make([]Series, 0, len(p.Series)+1)
+	if len(p.Data) > 0 {
+		series = append(series, Series{
+			Data:              p.Data,
+			Color:             p.Color,
+			ColorInverted:     p.ColorInverted,
+			Fill:              p.Fill,
+			FillColor:         p.FillColor,
+			FillColorInverted: p.FillColorInverted,
+			ChartType:         p.ChartType,
+		})
+	}
+	for _, s := range p.Series {
+		if len(s.Data) > 0 {
+			series = append(series, s)
+		}
+	}
+	return series
+}
+
 // Computes X and Y limits
 func (p *Plot) computeLimits() (float64, float64, float64, float64) {
 
@@ -94,22 +193,28 @@ func (p *Plot) computeLimits() (float64, float64, float64, float64) {
 		return p.XLim[0], p.XLim[1], p.YLim[0], p.YLim[1]
 	}
 
-	// Otherwise we'll need min/max of X and Y
-	pt := p.Data[0]
-	minX, maxX, minY, maxY := pt[0], pt[0], pt[1], pt[1]
-	for i := 1; i < len(p.Data); i++ {
-		pt = p.Data[i]
-		if pt[0] < minX {
-			minX = pt[0]
-		}
-		if pt[0] > maxX {
-			maxX = pt[0]
-		}
-		if pt[1] < minY {
-			minY = pt[1]
-		}
-		if pt[1] > maxY {
-			maxY = pt[1]
+	// Otherwise we'll need min/max of X and Y, across all series
+	var minX, maxX, minY, maxY float64
+	first := true
+	for _, series := range p.allSeries() {
+		for _, pt := range series.Data {
+			if first {
+				minX, maxX, minY, maxY = pt[0], pt[0], pt[1], pt[1]
+				first = false
+				continue
+			}
+			if pt[0] < minX {
+				minX = pt[0]
+			}
+			if pt[0] > maxX {
+				maxX = pt[0]
+			}
+			if pt[1] < minY {
+				minY = pt[1]
+			}
+			if pt[1] > maxY {
+				maxY = pt[1]
+			}
 		}
 	}
 
@@ -167,29 +272,62 @@ func (p *Plot) computeLimits() (float64, float64, float64, float64) {
 	return xLimMin, xLimMax, yLimMin, yLimMax
 }
 
+// Maps an X value to a column on the canvas
+func (p *Plot) translateX(x, xLimMin, xLimMax float64) int {
+	nX := (x - xLimMin) / (xLimMax - xLimMin)
+	return int(math.Round(nX * float64(p.Width-1)))
+}
+
+// Maps a Y value to a row on the canvas
+func (p *Plot) translateY(y, yLimMin, yLimMax float64) int {
+	nY := (y - yLimMin) / (yLimMax - yLimMin)
+	return p.Height - 1 - int(math.Round(nY*float64(p.Height-1)))
+}
+
 // Maps the points in X and Y to positions on the canvas
-func (p *Plot) translatePoints() []PathPoint {
+func (p *Plot) translate(data [][2]float64) []PathPoint {
 	xLimMin, xLimMax, yLimMin, yLimMax := p.computeLimits()
 
 	// Translate
-	points := make([]PathPoint, len(p.Data))
-	for i := 0; i < len(p.Data); i++ {
-		pt := p.Data[i]
-		nX := (pt[0] - xLimMin) / (xLimMax - xLimMin)
-		nY := (pt[1] - yLimMin) / (yLimMax - yLimMin)
+	points := make([]PathPoint, len(data))
+	for i := 0; i < len(data); i++ {
+		pt := data[i]
 		points[i] = PathPoint{
-			X: int(math.Round(nX * float64(p.Width-1))),
-			Y: p.Height - 1 - int(math.Round(nY*float64(p.Height-1))),
+			X: p.translateX(pt[0], xLimMin, xLimMax),
+			Y: p.translateY(pt[1], yLimMin, yLimMax),
 		}
 	}
-	p.invThreshold = p.Height - 1 - int(math.Round(((0-yLimMin)/(yLimMax-yLimMin))*float64(p.Height-1)))
+	p.invThreshold = p.translateY(0, yLimMin, yLimMax)
 
 	return points
 }
 
+// Maps the points in Data to positions on the canvas
+func (p *Plot) translatePoints() []PathPoint {
+	return p.translate(p.Data)
+}
+
+// Returns the values of every multiple of step between min and max.
+// If there'd be more of them than the pixels they're spread over,
+// there are none, since they couldn't be told apart anyway.
+func ticks(step, min, max float64, pixels int) []float64 {
+	if step <= 0 || math.IsNaN(step) || math.IsInf(step, 0) {
+		return nil
+	}
+	if (max-min)/step > float64(pixels) {
+		return nil
+	}
+
+	values := []float64{}
+	for i := math.Ceil(min / step); i*step <= max; i++ {
+		values = append(values, i*step)
+	}
+	return values
+}
+
 func dampenColor(c color.Color, a uint8) color.Color {
 	r, g, b, _ := c.RGBA()
-	return color.RGBA{uint8(r * uint32(a) / 255), uint8(g * uint32(a) / 255), uint8(b * uint32(a) / 255), 0xFF}
+	return color.RGBA{uint8((r >> 8) * uint32(a) / 255), uint8((g >> 8) * uint32(a) / 255), uint8((b >> 8) * uint32(a) / 255), 0xFF}
 }
 
 func (p Plot) PaintBounds(bounds image.Rectangle, frameIdx int) image.Rectangle {
@@ -197,31 +335,62 @@ func (p Plot) PaintBounds(bounds image.Rectangle, frameIdx int) image.Rectangle
 }
 
 func (p Plot) Paint(dc *gg.Context, bounds image.Rectangle, frameIdx int) {
-	// Set line and fill colors
+	series := p.allSeries()
+
+	points := make([]*PolyLine, len(series))
+	for i, s := range series {
+		points[i] = &PolyLine{Vertices: p.translate(s.Data)}
+	}
+
+	if p.Grid {
+		p.paintGrid(dc)
+	}
+
+	for i, s := range series {
+		if s.Fill {
+			p.paintFill(dc, s, points[i])
+		}
+	}
+
+	if p.Axes {
+		p.paintAxes(dc)
+	}
+
+	for i, s := range series {
+		p.paintLine(dc, s, points[i])
+	}
+}
+
+// Returns line and fill colors of a series
+func (s Series) colors() (color.Color, color.Color, color.Color, color.Color) {
 	var col color.Color
 	col = color.RGBA{0xff, 0xff, 0xff, 0xff}
-	if p.Color != nil {
-		col = p.Color
+	if s.Color != nil {
+		col = s.Color
 	}
 	colInv := col
-	if p.ColorInverted != nil {
-		colInv = p.ColorInverted
+	if s.ColorInverted != nil {
+		colInv = s.ColorInverted
 	}
 
 	fillCol := dampenColor(col, FillDampFactor)
-	if p.FillColor != nil {
-		fillCol = p.FillColor
+	if s.FillColor != nil {
+		fillCol = s.FillColor
 	}
 
 	fillColInv := dampenColor(colInv, FillDampFactor)
-	if p.FillColorInverted != nil {
-		fillColInv = p.FillColorInverted
+	if s.FillColorInverted != nil {
+		fillColInv = s.FillColorInverted
 	}
 
-	pl := &PolyLine{Vertices: p.translatePoints()}
+	return col, colInv, fillCol, fillColInv
+}
 
-	// the optional surface fill
-	for i := 0; p.Fill && i < pl.Length(); i++ {
+// Paints the surface between a series' line and the X-axis
+func (p Plot) paintFill(dc *gg.Context, s Series, pl *PolyLine) {
+	_, _, fillCol, fillColInv := s.colors()
+
+	for i := 0; i < pl.Length(); i++ {
 		x, y := pl.Point(i)
 		if x < 0 || x >= p.Width || y < 0 || y >= p.Height {
 			continue
@@ -240,11 +409,14 @@ func (p Plot) Paint(dc *gg.Context, bounds image.Rectangle, frameIdx int) {
 			}
 		}
 	}
+}
+
+// Paints a series' line, or its points for scatter charts
+func (p Plot) paintLine(dc *gg.Context, s Series, pl *PolyLine) {
+	col, colInv, _, _ := s.colors()
 
-	if p.ChartType == "scatter" {
-		points := p.translatePoints()
-		for i := 0; i < len(points); i++ {
-			point := points[i]
+	if s.ChartType == "scatter" {
+		for _, point := range pl.Vertices {
 			if point.Y > p.invThreshold {
 				dc.SetColor(colInv)
 			} else {
@@ -267,6 +439,94 @@ func (p Plot) Paint(dc *gg.Context, bounds image.Rectangle, frameIdx int) {
 	}
 }
 
+// Returns the column of the Y axis and the row of the X axis. They're
+// drawn at 0, unless it's out of view.
+func (p Plot) axesPosition() (int, int) {
+	xLimMin, xLimMax, yLimMin, yLimMax := p.computeLimits()
+
+	x := p.translateX(0, xLimMin, xLimMax)
+	if x < 0 {
+		x = 0
+	} else if x > p.Width-1 {
+		x = p.Width - 1
+	}
+
+	y := p.translateY(0, yLimMin, yLimMax)
+	if y < 0 {
+		y = 0
+	} else if y > p.Height-1 {
+		y = p.Height - 1
+	}
+
+	return x, y
+}
+
+func (p Plot) paintGrid(dc *gg.Context) {
+	xLimMin, xLimMax, yLimMin, yLimMax := p.computeLimits()
+
+	var col color.Color = DefaultGridColor
+	if p.GridColor != nil {
+		col = p.GridColor
+	}
+	dc.SetColor(col)
+
+	for _, v := range ticks(p.XTicks, xLimMin, xLimMax, p.Width) {
+		x := p.translateX(v, xLimMin, xLimMax)
+		for y := 0; y < p.Height; y++ {
+			tx, ty := dc.TransformPoint(float64(x), float64(y))
+			dc.SetPixel(int(tx), int(ty))
+		}
+	}
+
+	for _, v := range ticks(p.YTicks, yLimMin, yLimMax, p.Height) {
+		y := p.translateY(v, yLimMin, yLimMax)
+		for x := 0; x < p.Width; x++ {
+			tx, ty := dc.TransformPoint(float64(x), float64(y))
+			dc.SetPixel(int(tx), int(ty))
+		}
+	}
+}
+
+// Paints the axes, with ticks on the side facing into the plot
+func (p Plot) paintAxes(dc *gg.Context) {
+	xLimMin, xLimMax, yLimMin, yLimMax := p.computeLimits()
+	axisX, axisY := p.axesPosition()
+
+	var col color.Color = DefaultAxisColor
+	if p.AxisColor != nil {
+		col = p.AxisColor
+	}
+	dc.SetColor(col)
+
+	setPixel := func(x, y int) {
+		tx, ty := dc.TransformPoint(float64(x), float64(y))
+		dc.SetPixel(int(tx), int(ty))
+	}
+
+	for x := 0; x < p.Width; x++ {
+		setPixel(x, axisY)
+	}
+	for y := 0; y < p.Height; y++ {
+		setPixel(axisX, y)
+	}
+
+	tickY := axisY - 1
+	if axisY == 0 {
+		tickY = 1
+	}
+	for _, v := range ticks(p.XTicks, xLimMin, xLimMax, p.Width) {
+		setPixel(p.translateX(v, xLimMin, xLimMax), tickY)
+	}
+
+	tickX := axisX + 1
+	if axisX == p.Width-1 {
+		tickX = axisX - 1
+	}
+	for _, v := range ticks(p.YTicks, yLimMin, yLimMax, p.Height) {
+		setPixel(tickX, p.translateY(v, yLimMin, yLimMax))
+	}
+}
+
 func (p Plot) FrameCount() int {
 	return 1
 }
@@ -567,3 +567,130 @@ func TestPlotScatter(t *testing.T) {
 	}, PaintWidget(p, image.Rect(0, 0, 100, 100), 0)))
 
 }
+
+func TestDampenColor(t *testing.T) {
+	assert.Equal(t, color.RGBA{0x55, 0x38, 0, 0xff}, dampenColor(color.RGBA{0xff, 0xaa, 0, 0xff}, 0x55))
+	assert.Equal(t, color.RGBA{0x55, 0x55, 0x55, 0xff}, dampenColor(color.White, 0x55))
+}
+
+func TestPlotSeriesLimits(t *testing.T) {
+	p := Plot{
+		Data: [][2]float64{{1, 1}, {2, 2}},
+		Series: []Series{
+			{Data: [][2]float64{{0, 3}, {4, -1}}},
+			{Data: [][2]float64{}},
+		},
+		XLim: Empty,
+		YLim: Empty,
+	}
+
+	// Limits fit all series
+	xA, xB, yA, yB := p.computeLimits()
+	assert.Equal(t, []float64{0, 4, -1, 3}, []float64{xA, xB, yA, yB})
+
+	// Even when Data is empty
+	p.Data = nil
+	xA, xB, yA, yB = p.computeLimits()
+	assert.Equal(t, []float64{0, 4, -1, 3}, []float64{xA, xB, yA, yB})
+}
+
+func TestPlotSeries(t *testing.T) {
+	ic := ImageChecker{
+		Palette: map[string]color.RGBA{
+			"1": {0xff, 0xff, 0xff, 0xff},
+			"2": {0xff, 0, 0, 0xff},
+			"3": {0, 0xff, 0, 0xff},
+			".": {0, 0, 0, 0},
+		},
+	}
+
+	p := Plot{
+		Width:  5,
+		Height: 5,
+		Data:   [][2]float64{{0, 0}, {4, 0}},
+		Series: []Series{
+			{
+				Data:  [][2]float64{{0, 4}, {4, 2}},
+				Color: color.RGBA{0xff, 0, 0, 0xff},
+			},
+			{
+				Data:      [][2]float64{{1, 1}, {3, 3}},
+				Color:     color.RGBA{0, 0xff, 0, 0xff},
+				ChartType: "scatter",
+			},
+		},
+		XLim: Empty,
+		YLim: Empty,
+	}
+
+	assert.Equal(t, nil, ic.Check([]string{
+		"2....",
+		".223.",
+		"...22",
+		".3...",
+		"11111",
+	}, PaintWidget(p, image.Rect(0, 0, 100, 100), 0)))
+}
+
+func TestPlotAxes(t *testing.T) {
+	ic := ImageChecker{
+		Palette: map[string]color.RGBA{
+			"1": {0xff, 0xff, 0xff, 0xff},
+			"a": DefaultAxisColor,
+			"g": DefaultGridColor,
+			".": {0, 0, 0, 0},
+		},
+	}
+
+	// Axes cross at 0, with ticks on the inside
+	p := Plot{
+		Width:  7,
+		Height: 5,
+		Data:   [][2]float64{{-2, 2}, {4, -2}},
+		XLim:   Empty,
+		YLim:   Empty,
+		Axes:   true,
+		XTicks: 2,
+		YTicks: 1,
+	}
+	assert.Equal(t, nil, ic.Check([]string{
+		"1.aa...",
+		"a11aa.a",
+		"aaa1aaa",
+		"..aa11.",
+		"..aa..1",
+	}, PaintWidget(p, image.Rect(0, 0, 100, 100), 0)))
+
+	// Out of view, they're drawn along the edges
+	p.Data = [][2]float64{{1, 1}, {5, 3}}
+	p.XTicks, p.YTicks = 0, 0
+	assert.Equal(t, nil, ic.Check([]string{
+		"a.....1",
+		"a...11.",
+		"a..1...",
+		"a11....",
+		"1aaaaaa",
+	}, PaintWidget(p, image.Rect(0, 0, 100, 100), 0)))
+
+	// Gridlines at every tick, below axes and data
+	p.Axes = false
+	p.Grid = true
+	p.XTicks = 2
+	p.YTicks = 1
+	assert.Equal(t, nil, ic.Check([]string{
+		"gggggg1",
+		"..g.11.",
+		"ggg1ggg",
+		".11..g.",
+		"1gggggg",
+	}, PaintWidget(p, image.Rect(0, 0, 100, 100), 0)))
+}
+
+func TestPlotTicks(t *testing.T) {
+	assert.Equal(t, []float64{-2, 0, 2, 4}, ticks(2, -3, 5, 10))
+	assert.Nil(t, ticks(0, -3, 5, 10))
+
+	// too many to tell apart
+	assert.Nil(t, ticks(0.5, -3, 5, 10))
+	assert.Nil(t, ticks(0.000001, 0, 1e6, 64))
+}
@@ -0,0 +1,384 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"unicode"
+
+	"github.com/tidbyt/gg"
+	"golang.org/x/image/font"
+)
+
+// RichText draws text made up of spans, each with its own font and
+// color, and optionally an inline image. All spans on a line share
+// the same baseline.
+//
+// Spans can be given as `render.Span` or as plain strings, which are
+// drawn in the font and color of the RichText.
+//
+// Like WrappedText, RichText wraps its content to fit the available
+// width, or the `width` parameter if set. Lines are only broken at
+// whitespace, or where a span contains a line break.
+//
+// Alignment of the lines is controlled by passing one of the
+// following `align` values:
+// - `"left"`: align text to the left
+// - `"center"`: align text in the center
+// - `"right"`: align text to the right
+//
+// DOC(Spans): Spans of text to draw
+// DOC(Font): Font face for spans that don't set one
+// DOC(Color): Font color for spans that don't set one
+// DOC(Width): Limits width of the area on which text may be drawn
+// DOC(Height): Limits height of the area on which text may be drawn
+// DOC(LineSpacing): Controls spacing between lines
+// DOC(Align): Text Alignment
+// DOC(Fallback): Fonts to draw characters missing from a span's font with, in order of preference
+//
+// EXAMPLE BEGIN
+// render.RichText(
+//   spans = [
+//     render.Span("BOS ", color = "#fa0"),
+//     render.Span("3", font = "6x13"),
+//     " - ",
+//     render.Span("2", font = "6x13"),
+//     render.Span(" NYY", color = "#099"),
+//   ],
+// )
+// EXAMPLE END
+type RichText struct {
+	Widget
+
+	Spans       []Span `starlark:"spans,required"`
+	Font        string
+	Color       color.Color
+	Width       int
+	Height      int
+	LineSpacing int
+	Align       string
+	Fallback    []string
+
+	face    font.Face
+	faces   []font.Face
+	fonts   FontSource
+	missing []rune
+}
+
+// Span is a run of text within RichText.
+//
+// DOC(Content): The text string to draw
+// DOC(Font): Desired font face, instead of that of the RichText
+// DOC(Color): Desired font color, instead of that of the RichText
+// DOC(Image): Widget to draw inline before the text, such as an icon
+type Span struct {
+	Content string
+	Font    string
+	Color   color.Color
+	Image   Widget
+}
+
+// SetFontSource makes the fonts in src available to the widget, in addition
+// to the built-in ones.
+func (rt *RichText) SetFontSource(src FontSource) {
+	rt.fonts = src
+}
+
+func (rt *RichText) Init() error {
+	if rt.Font == "" {
+		rt.Font = DefaultFontFace
+	}
+
+	face, err := LookupFont(rt.fonts, rt.Font)
+	if err != nil {
+		return err
+	}
+	rt.face = face
+
+	rt.faces = make([]font.Face, len(rt.Spans))
+	rt.missing = nil
+	seen := map[rune]bool{}
+
+	for i, span := range rt.Spans {
+		name := span.Font
+		if name == "" {
+			name = rt.Font
+		}
+
+		face, missing, err := LoadFontChain(rt.fonts, name, rt.Fallback, span.Content)
+		if err != nil {
+			return err
+		}
+		rt.faces[i] = face
+
+		for _, r := range missing {
+			if !seen[r] {
+				seen[r] = true
+				rt.missing = append(rt.missing, r)
+			}
+		}
+	}
+
+	return nil
+}
+
+// MissingGlyphs returns the characters in the spans that neither their
+// fonts nor the fallbacks can draw.
+func (rt *RichText) MissingGlyphs() []rune {
+	return rt.missing
+}
+
+// richTextPiece is a word, an image or a run of whitespace, placed on a
+// line of RichText.
+type richTextPiece struct {
+	span  int
+	text  string
+	image Widget
+
+	x, width        int
+	ascent, descent int
+}
+
+type richTextLine struct {
+	pieces          []richTextPiece
+	width           int
+	ascent, descent int
+}
+
+func (l *richTextLine) add(p richTextPiece) {
+	if len(l.pieces) == 0 {
+		// empty lines are as tall as the default font, but others
+		// only as tall as what's on them
+		l.ascent, l.descent = p.ascent, p.descent
+	}
+
+	p.x = l.width
+	l.pieces = append(l.pieces, p)
+	l.width += p.width
+	if p.ascent > l.ascent {
+		l.ascent = p.ascent
+	}
+	if p.descent > l.descent {
+		l.descent = p.descent
+	}
+}
+
+// layout breaks the spans into lines that fit the width of the widget,
+// unless a single word is wider than that.
+func (rt *RichText) layout(bounds image.Rectangle, frameIdx int) []*richTextLine {
+	maxWidth := rt.Width
+	if maxWidth == 0 {
+		maxWidth = bounds.Dx()
+	}
+
+	defaultMetrics := rt.face.Metrics()
+
+	newLine := func() *richTextLine {
+		return &richTextLine{
+			ascent:  defaultMetrics.Ascent.Floor(),
+			descent: defaultMetrics.Descent.Floor(),
+		}
+	}
+
+	// split the spans into words, whitespace and line breaks. a word
+	// can run across spans, as long as there's no whitespace between
+	// them.
+	type chunk struct {
+		pieces    []richTextPiece
+		width     int
+		space     bool
+		lineBreak bool
+	}
+	chunks := []*chunk{}
+	current := func(space bool) *chunk {
+		if len(chunks) > 0 {
+			last := chunks[len(chunks)-1]
+			if !last.lineBreak && last.space == space {
+				return last
+			}
+		}
+		c := &chunk{space: space}
+		chunks = append(chunks, c)
+		return c
+	}
+
+	for i, span := range rt.Spans {
+		face := rt.faces[i]
+		metrics := face.Metrics()
+
+		if span.Image != nil {
+			b := span.Image.PaintBounds(image.Rect(0, 0, maxWidth, bounds.Dy()), frameIdx)
+			c := current(false)
+			c.pieces = append(c.pieces, richTextPiece{
+				span:   i,
+				image:  span.Image,
+				width:  b.Dx(),
+				ascent: b.Dy(),
+			})
+			c.width += b.Dx()
+		}
+
+		runes := []rune(span.Content)
+		for start := 0; start < len(runes); {
+			if runes[start] == '\n' {
+				chunks = append(chunks, &chunk{lineBreak: true})
+				start++
+				continue
+			}
+
+			space := unicode.IsSpace(runes[start])
+			end := start + 1
+			for end < len(runes) && runes[end] != '\n' && unicode.IsSpace(runes[end]) == space {
+				end++
+			}
+
+			text := string(runes[start:end])
+			width := font.MeasureString(face, text).Ceil()
+			c := current(space)
+			c.pieces = append(c.pieces, richTextPiece{
+				span:    i,
+				text:    text,
+				width:   width,
+				ascent:  metrics.Ascent.Floor(),
+				descent: metrics.Descent.Floor(),
+			})
+			c.width += width
+
+			start = end
+		}
+	}
+
+	// greedily fill lines with as many words as fit. whitespace at the
+	// end of a line is dropped.
+	lines := []*richTextLine{newLine()}
+	var pending *chunk
+	for _, c := range chunks {
+		line := lines[len(lines)-1]
+
+		switch {
+		case c.lineBreak:
+			lines = append(lines, newLine())
+			pending = nil
+
+		case c.space:
+			pending = c
+
+		default:
+			width := c.width
+			if pending != nil {
+				width += pending.width
+			}
+
+			if len(line.pieces) > 0 && line.width+width > maxWidth {
+				line = newLine()
+				lines = append(lines, line)
+				pending = nil
+			}
+
+			if pending != nil {
+				for _, p := range pending.pieces {
+					line.add(p)
+				}
+				pending = nil
+			}
+			for _, p := range c.pieces {
+				line.add(p)
+			}
+		}
+	}
+
+	return lines
+}
+
+// size returns the size of the widget, given its lines.
+func (rt *RichText) size(bounds image.Rectangle, lines []*richTextLine) (int, int) {
+	w, h := 0, 0
+	for i, line := range lines {
+		if line.width > w {
+			w = line.width
+		}
+		h += line.ascent + line.descent
+		if i > 0 {
+			h += rt.LineSpacing
+		}
+	}
+
+	width := rt.Width
+	if width == 0 {
+		width = w
+		if width > bounds.Dx() {
+			width = bounds.Dx()
+		}
+	}
+
+	height := rt.Height
+	if height == 0 {
+		height = h
+		if height > bounds.Dy() {
+			height = bounds.Dy()
+		}
+	}
+
+	return width, height
+}
+
+func (rt *RichText) PaintBounds(bounds image.Rectangle, frameIdx int) image.Rectangle {
+	lines := rt.layout(bounds, frameIdx)
+	width, height := rt.size(bounds, lines)
+	return image.Rect(0, 0, width, height)
+}
+
+func (rt *RichText) Paint(dc *gg.Context, bounds image.Rectangle, frameIdx int) {
+	lines := rt.layout(bounds, frameIdx)
+	width, _ := rt.size(bounds, lines)
+
+	y := 0
+	for _, line := range lines {
+		x := 0
+		if rt.Align == "center" {
+			x = (width - line.width) / 2
+		} else if rt.Align == "right" {
+			x = width - line.width
+		}
+
+		baseline := y + line.ascent
+		for _, p := range line.pieces {
+			span := rt.Spans[p.span]
+
+			if p.image != nil {
+				dc.Push()
+				dc.Translate(float64(x+p.x), float64(baseline-p.ascent))
+				p.image.Paint(dc, image.Rect(0, 0, p.width, p.ascent), frameIdx)
+				dc.Pop()
+				continue
+			}
+
+			if unicode.IsSpace([]rune(p.text)[0]) {
+				continue
+			}
+
+			c := span.Color
+			if c == nil {
+				c = rt.Color
+			}
+			if c == nil {
+				c = DefaultFontColor
+			}
+
+			dc.SetFontFace(rt.faces[p.span])
+			dc.SetColor(c)
+			dc.DrawString(p.text, float64(x+p.x), float64(baseline))
+		}
+
+		y += line.ascent + line.descent + rt.LineSpacing
+	}
+}
+
+func (rt *RichText) FrameCount() int {
+	widgets := []Widget{}
+	for _, span := range rt.Spans {
+		if span.Image != nil {
+			widgets = append(widgets, span.Image)
+		}
+	}
+	return MaxFrameCount(widgets)
+}
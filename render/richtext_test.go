@@ -0,0 +1,161 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRichTextSpans(t *testing.T) {
+	text := &RichText{Spans: []Span{
+		{Content: "AB", Color: color.RGBA{0xff, 0, 0, 0xff}},
+		{Content: " CD."},
+	}}
+	require.NoError(t, text.Init())
+
+	im := PaintWidget(text, image.Rect(0, 0, 40, 8), 0)
+	assert.Equal(t, nil, checkImage([]string{
+		"....." + "........" + "....." + ".......",
+		".rr.." + "rrr....." + ".ww.." + "www....",
+		"r..r." + "r..r...." + "w..w." + "w..w...",
+		"r..r." + "rrr....." + "w...." + "w..w...",
+		"rrrr." + "r..r...." + "w...." + "w..w...",
+		"r..r." + "r..r...." + "w..w." + "w..w...",
+		"r..r." + "rrr....." + ".ww.." + "www..w.",
+		"....." + "........" + "....." + ".......",
+	}, im))
+}
+
+func TestRichTextBaseline(t *testing.T) {
+	small, err := GetFont("tom-thumb")
+	require.NoError(t, err)
+	large, err := GetFont("6x13")
+	require.NoError(t, err)
+
+	text := &RichText{Spans: []Span{
+		{Content: "a", Font: "tom-thumb"},
+		{Content: "A", Font: "6x13"},
+	}}
+	require.NoError(t, text.Init())
+
+	// the line fits the tallest ascent and the deepest descent
+	ascent := large.Metrics().Ascent.Floor()
+	descent := large.Metrics().Descent.Floor()
+	assert.Equal(t, image.Rect(0, 0, 4+6, ascent+descent), text.PaintBounds(image.Rect(0, 0, 64, 32), 0))
+
+	// and both glyphs sit on the same baseline, so the bottom rows of
+	// the small one line up with those of the large one
+	im := PaintWidget(text, image.Rect(0, 0, 64, 32), 0)
+	smallDescent := small.Metrics().Descent.Floor()
+	for y := ascent + smallDescent; y < ascent+descent; y++ {
+		for x := 0; x < 4; x++ {
+			_, _, _, a := im.At(x, y).RGBA()
+			assert.Zero(t, a, "pixel below small baseline at %d,%d", x, y)
+		}
+	}
+	lit := false
+	for x := 0; x < 4; x++ {
+		_, _, _, a := im.At(x, ascent-1).RGBA()
+		lit = lit || a > 0
+	}
+	assert.True(t, lit, "small glyph should touch the shared baseline")
+}
+
+func TestRichTextWrapping(t *testing.T) {
+	text := &RichText{Spans: []Span{
+		{Content: "AB CD", Color: color.RGBA{0xff, 0, 0, 0xff}},
+		{Content: " EF"},
+	}}
+	require.NoError(t, text.Init())
+
+	lines := text.layout(image.Rect(0, 0, 64, 32), 0)
+	require.Equal(t, 1, len(lines))
+
+	// words are only broken at whitespace, which is dropped at the
+	// end of the line
+	lines = text.layout(image.Rect(0, 0, 12, 32), 0)
+	require.Equal(t, 3, len(lines))
+	for i, line := range lines {
+		assert.LessOrEqual(t, line.width, 12, "line %d", i)
+		assert.Equal(t, 1, len(line.pieces), "line %d", i)
+	}
+	assert.Equal(t, "AB", lines[0].pieces[0].text)
+	assert.Equal(t, "CD", lines[1].pieces[0].text)
+	assert.Equal(t, "EF", lines[2].pieces[0].text)
+
+	// a word that spans several spans is kept together
+	text = &RichText{Spans: []Span{
+		{Content: "AB C"},
+		{Content: "D", Color: color.RGBA{0xff, 0, 0, 0xff}},
+	}}
+	require.NoError(t, text.Init())
+	lines = text.layout(image.Rect(0, 0, 12, 32), 0)
+	require.Equal(t, 2, len(lines))
+	assert.Equal(t, 2, len(lines[1].pieces))
+
+	// line breaks always start a new line
+	text = &RichText{Spans: []Span{{Content: "A\nB"}}, LineSpacing: 2}
+	require.NoError(t, text.Init())
+	lines = text.layout(image.Rect(0, 0, 64, 32), 0)
+	require.Equal(t, 2, len(lines))
+	h := lines[0].ascent + lines[0].descent
+	assert.Equal(t, image.Rect(0, 0, lines[0].width, 2*h+2), text.PaintBounds(image.Rect(0, 0, 64, 32), 0))
+}
+
+func TestRichTextAlign(t *testing.T) {
+	text := &RichText{
+		Spans: []Span{{Content: "AB"}},
+		Width: 15,
+		Align: "right",
+	}
+	require.NoError(t, text.Init())
+
+	im := PaintWidget(text, image.Rect(0, 0, 64, 32), 0)
+	assert.Equal(t, nil, checkImage([]string{
+		"....." + "....." + ".....",
+		"....." + ".ww.." + "www..",
+		"....." + "w..w." + "w..w.",
+		"....." + "w..w." + "www..",
+		"....." + "wwww." + "w..w.",
+		"....." + "w..w." + "w..w.",
+		"....." + "w..w." + "www..",
+		"....." + "....." + ".....",
+	}, im))
+}
+
+func TestRichTextImage(t *testing.T) {
+	icon := &Box{Width: 3, Height: 3, Color: color.RGBA{0, 0, 0xff, 0xff}}
+	text := &RichText{Spans: []Span{
+		{Content: "A", Image: icon},
+	}}
+	require.NoError(t, text.Init())
+
+	// the image sits on the baseline, right before the text
+	im := PaintWidget(text, image.Rect(0, 0, 64, 32), 0)
+	assert.Equal(t, nil, checkImage([]string{
+		"..." + ".....",
+		"..." + ".ww..",
+		"..." + "w..w.",
+		"..." + "w..w.",
+		"bbb" + "wwww.",
+		"bbb" + "w..w.",
+		"bbb" + "w..w.",
+		"..." + ".....",
+	}, im))
+}
+
+func TestRichTextFallback(t *testing.T) {
+	text := &RichText{Spans: []Span{
+		{Content: "aΩ", Font: "tom-thumb"},
+		{Content: "Ω"},
+	}}
+	require.NoError(t, text.Init())
+	assert.Equal(t, []rune{'Ω'}, text.MissingGlyphs())
+
+	text.Fallback = []string{"6x13"}
+	require.NoError(t, text.Init())
+	assert.Empty(t, text.MissingGlyphs())
+}
@@ -1,8 +1,10 @@
 package render
 
 import (
+	"fmt"
 	"image"
 	"image/color"
+	"reflect"
 	"runtime"
 	"sync"
 
@@ -19,6 +21,10 @@ const (
 
 	// DefaultMaxFrameCount is the default maximum number of frames to render.
 	DefaultMaxFrameCount = 2000
+
+	// DefaultFrameDelay is the frame delay in milliseconds used when a
+	// root doesn't specify one.
+	DefaultFrameDelay = 50
 )
 
 var FrameWidth = DefaultFrameWidth
@@ -31,7 +37,15 @@ var FrameHeight = DefaultFrameHeight
 // canvas.
 //
 // If the tree contains animated widgets, the resulting animation will
-// run with _delay_ milliseconds per frame.
+// run with _delay_ milliseconds per frame. When an app returns several
+// roots, each of them runs with its own _delay_, or that of the first
+// root if it doesn't have one. Identical consecutive frames are shown
+// as one longer frame, so holding still costs nothing.
+//
+// Durations and delays of animations are counted in frames, so they
+// play faster or slower when _delay_ changes. Set _TimeBased_ to give
+// them in milliseconds instead, and have them converted to frames of
+// whatever _delay_ the root uses.
 //
 // If the tree holds time sensitive information which must never be
 // displayed past a certain point in time, pass _MaxAge_ to specify
@@ -42,16 +56,100 @@ var FrameHeight = DefaultFrameHeight
 // DOC(Delay): Frame delay in milliseconds
 // DOC(MaxAge): Expiration time in seconds
 // DOC(ShowFullAnimation): Request animation is shown in full, regardless of app cycle speed
+// DOC(TimeBased): Durations and delays of animations are in milliseconds instead of frames
 type Root struct {
 	Child             Widget `starlark:"child,required"`
 	Delay             int32  `starlark:"delay"`
 	MaxAge            int32  `starlark:"max_age"`
 	ShowFullAnimation bool   `starlark:"show_full_animation"`
+	TimeBased         bool   `starlark:"time_based"`
 
 	maxParallelFrames int
 	maxFrameCount     int
 }
 
+// FrameDelay returns how long each frame of r is shown, in
+// milliseconds. A root without a delay of its own uses inherited, the
+// delay of the first root returned along with it, and failing that
+// DefaultFrameDelay.
+func (r *Root) FrameDelay(inherited int32) int32 {
+	if r.Delay > 0 {
+		return r.Delay
+	}
+	if inherited > 0 {
+		return inherited
+	}
+	return DefaultFrameDelay
+}
+
+// Init tells the widgets of a time based root how long each frame
+// is, so that they can convert their durations to frames. Roots
+// returned together should be initialized with InitRoots instead.
+//
+// The frame delay is stored in the widgets, so in a time based root,
+// widgets that implement WidgetWithFrameDelay must be passed by
+// pointer. Init fails if they aren't.
+func (r *Root) Init() error {
+	return r.initFrameDelay(0, nil)
+}
+
+// InitRoots initializes roots returned together, so that time based
+// roots without a delay convert their durations with the delay of the
+// first root, which is what they're shown with. It fails if a widget
+// is shared by roots that need different delays.
+func InitRoots(roots []Root) error {
+	delays := map[WidgetWithFrameDelay]int{}
+	for i := range roots {
+		if err := roots[i].initFrameDelay(roots[0].Delay, delays); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var frameDelayType = reflect.TypeOf((*WidgetWithFrameDelay)(nil)).Elem()
+
+// initFrameDelay sets the frame delay of the widgets of r, or 0 if r
+// isn't time based. If delays isn't nil, it holds the delay given to
+// each widget so far, and r may not give any of them another one.
+func (r *Root) initFrameDelay(inherited int32, delays map[WidgetWithFrameDelay]int) error {
+	if !r.TimeBased && delays == nil {
+		return nil
+	}
+
+	delay := 0
+	if r.TimeBased {
+		delay = int(r.FrameDelay(inherited))
+	}
+
+	var err error
+	Walk(r.Child, func(w Widget) {
+		if err != nil {
+			return
+		}
+
+		t, ok := w.(WidgetWithFrameDelay)
+		if !ok {
+			if r.TimeBased && reflect.PointerTo(reflect.TypeOf(w)).Implements(frameDelayType) {
+				err = fmt.Errorf("%T in a time based root must be passed by pointer", w)
+			}
+			return
+		}
+
+		if delays != nil {
+			if d, seen := delays[t]; seen && d != delay {
+				err = fmt.Errorf("%T is shared by roots with frame delays %d and %d", w, d, delay)
+				return
+			}
+			delays[t] = delay
+		}
+
+		t.SetFrameDelay(delay)
+	})
+
+	return err
+}
+
 type RootPaintOption func(*Root)
 
 // WithMaxParallelFrames sets the maximum number of frames that will
@@ -97,12 +195,7 @@ func (r Root) Paint(solidBackground bool, opts ...RootPaintOption) []image.Image
 		parallelism = runtime.NumCPU()
 	}
 
-	if globals.Width != DefaultFrameWidth {
-		FrameWidth = globals.Width
-	}
-	if globals.Height != DefaultFrameHeight {
-		FrameHeight = globals.Height
-	}
+	updateFrameSize()
 
 	var wg sync.WaitGroup
 	sem := make(chan bool, parallelism)
@@ -116,16 +209,7 @@ func (r Root) Paint(solidBackground bool, opts ...RootPaintOption) []image.Image
 				wg.Done()
 			}()
 
-			dc := gg.NewContext(FrameWidth, FrameHeight)
-			if solidBackground {
-				dc.SetColor(color.Black)
-				dc.Clear()
-			}
-
-			dc.Push()
-			r.Child.Paint(dc, image.Rect(0, 0, FrameWidth, FrameHeight), i)
-			dc.Pop()
-			frames[i] = dc.Image()
+			frames[i] = r.paintFrame(solidBackground, i)
 		}(i)
 	}
 
@@ -133,12 +217,47 @@ func (r Root) Paint(solidBackground bool, opts ...RootPaintOption) []image.Image
 	return frames
 }
 
+func (r Root) paintFrame(solidBackground bool, frameIdx int) image.Image {
+	dc := gg.NewContext(FrameWidth, FrameHeight)
+	if solidBackground {
+		dc.SetColor(color.Black)
+		dc.Clear()
+	}
+
+	dc.Push()
+	r.Child.Paint(dc, image.Rect(0, 0, FrameWidth, FrameHeight), frameIdx)
+	dc.Pop()
+	return dc.Image()
+}
+
+func updateFrameSize() {
+	if globals.Width != DefaultFrameWidth {
+		FrameWidth = globals.Width
+	}
+	if globals.Height != DefaultFrameHeight {
+		FrameHeight = globals.Height
+	}
+}
+
 // PaintRoots draws >=1 Roots which must all have the same dimensions.
 func PaintRoots(solidBackground bool, roots ...Root) []image.Image {
+	images, _ := PaintRootsWithDelays(solidBackground, roots...)
+	return images
+}
+
+// PaintRootsWithDelays draws >=1 Roots like PaintRoots, and also
+// returns the delay of each frame, which is the FrameDelay of the root
+// it came from.
+func PaintRootsWithDelays(solidBackground bool, roots ...Root) ([]image.Image, []int32) {
 	var images []image.Image
+	var delays []int32
 	for _, r := range roots {
-		images = append(images, r.Paint(solidBackground)...)
+		frames := r.Paint(solidBackground)
+		images = append(images, frames...)
+		for range frames {
+			delays = append(delays, r.FrameDelay(roots[0].Delay))
+		}
 	}
 
-	return images
+	return images, delays
 }
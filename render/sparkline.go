@@ -0,0 +1,127 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/tidbyt/gg"
+)
+
+// Sparkline draws a small line chart of a list of values, spread
+// evenly across its width. The lowest and highest values can be
+// marked with a pixel of their own color.
+//
+// DOC(Data): A list of numbers
+// DOC(Width): Limits Sparkline width
+// DOC(Height): Limits Sparkline height
+// DOC(Color): Line color, default is '#fff'
+// DOC(Fill): Paint surface between line and X-axis
+// DOC(FillColor): Fill color, default is a dimmed line color
+// DOC(MinColor): Color of the marker on the lowest value, not drawn unless set
+// DOC(MaxColor): Color of the marker on the highest value, not drawn unless set
+// DOC(Lim): Limit values to a range
+//
+// EXAMPLE BEGIN
+// render.Sparkline(
+//   data = [3, 5, 4, 8, 6, 7, 2, 4, 6, 9, 7, 8],
+//   width = 32,
+//   height = 12,
+//   color = "#0af",
+//   fill = True,
+//   min_color = "#f00",
+//   max_color = "#0f0",
+// )
+// EXAMPLE END
+type Sparkline struct {
+	Widget
+
+	Data      []float64   `starlark:"data,required"`
+	Width     int         `starlark:"width,required"`
+	Height    int         `starlark:"height,required"`
+	Color     color.Color `starlark:"color"`
+	Fill      bool        `starlark:"fill"`
+	FillColor color.Color `starlark:"fill_color"`
+	MinColor  color.Color `starlark:"min_color"`
+	MaxColor  color.Color `starlark:"max_color"`
+	Lim       [2]float64  `starlark:"lim"`
+}
+
+// Returns a Plot that draws the line
+func (s Sparkline) plot() *Plot {
+	data := make([][2]float64, len(s.Data))
+	for i, v := range s.Data {
+		data[i] = [2]float64{float64(i), v}
+	}
+
+	xMax := float64(len(s.Data) - 1)
+	if xMax < 1 {
+		xMax = 1
+	}
+
+	lim := s.Lim
+	if lim[0] == lim[1] {
+		// not set at all
+		lim = [2]float64{math.NaN(), math.NaN()}
+	}
+
+	return &Plot{
+		Data:      data,
+		Width:     s.Width,
+		Height:    s.Height,
+		Color:     s.Color,
+		XLim:      [2]float64{0, xMax},
+		YLim:      lim,
+		Fill:      s.Fill,
+		FillColor: s.FillColor,
+	}
+}
+
+func (s Sparkline) PaintBounds(bounds image.Rectangle, frameIdx int) image.Rectangle {
+	return image.Rect(0, 0, s.Width, s.Height)
+}
+
+func (s Sparkline) Paint(dc *gg.Context, bounds image.Rectangle, frameIdx int) {
+	if len(s.Data) == 0 {
+		return
+	}
+
+	p := s.plot()
+	p.Paint(dc, bounds, frameIdx)
+
+	minIdx, maxIdx := 0, 0
+	for i, v := range s.Data {
+		if v < s.Data[minIdx] {
+			minIdx = i
+		}
+		if v > s.Data[maxIdx] {
+			maxIdx = i
+		}
+	}
+
+	points := p.translatePoints()
+	for _, marker := range []struct {
+		idx   int
+		color color.Color
+	}{
+		{minIdx, s.MinColor},
+		{maxIdx, s.MaxColor},
+	} {
+		if marker.color == nil {
+			continue
+		}
+
+		pt := points[marker.idx]
+		if pt.X < 0 || pt.X >= s.Width || pt.Y < 0 || pt.Y >= s.Height {
+			continue
+		}
+
+		dc.SetColor(marker.color)
+		tx, ty := dc.TransformPoint(float64(pt.X), float64(pt.Y))
+		dc.SetPixel(int(tx), int(ty))
+	}
+}
+
+func (s Sparkline) FrameCount() int {
+	return 1
+}
@@ -0,0 +1,66 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSparkline(t *testing.T) {
+	s := Sparkline{
+		Data:   []float64{1, 3, 2, 1, 2},
+		Width:  5,
+		Height: 3,
+	}
+	im := PaintWidget(s, image.Rect(0, 0, 100, 100), 0)
+	assert.Equal(t, nil, checkImage([]string{
+		".w...",
+		".ww.w",
+		"w..w.",
+	}, im))
+
+	// Markers on the first lowest and highest values
+	s.MinColor = color.RGBA{0xff, 0, 0, 0xff}
+	s.MaxColor = color.RGBA{0, 0xff, 0, 0xff}
+	im = PaintWidget(s, image.Rect(0, 0, 100, 100), 0)
+	assert.Equal(t, nil, checkImage([]string{
+		".g...",
+		".ww.w",
+		"r..w.",
+	}, im))
+}
+
+func TestSparklineLimits(t *testing.T) {
+	s := Sparkline{
+		Data:     []float64{1, 2, 3},
+		Width:    3,
+		Height:   3,
+		Lim:      [2]float64{0, 4},
+		MaxColor: color.RGBA{0, 0xff, 0, 0xff},
+	}
+	im := PaintWidget(s, image.Rect(0, 0, 100, 100), 0)
+	assert.Equal(t, nil, checkImage([]string{
+		"..g",
+		"ww.",
+		"...",
+	}, im))
+
+	// A single value, or none, is fine too
+	s.Data = []float64{2}
+	im = PaintWidget(s, image.Rect(0, 0, 100, 100), 0)
+	assert.Equal(t, nil, checkImage([]string{
+		"...",
+		"g..",
+		"...",
+	}, im))
+
+	s.Data = []float64{}
+	im = PaintWidget(s, image.Rect(0, 0, 100, 100), 0)
+	assert.Equal(t, nil, checkImage([]string{
+		"...",
+		"...",
+		"...",
+	}, im))
+}
@@ -16,29 +16,45 @@ var (
 // Text draws a string of text on a single line.
 //
 // By default, the text will use the "tb-8" font, but other fonts can
-// be chosen via the `font` attribute. The `height` and `offset`
-// parameters allow fine tuning of the vertical layout of the
-// string. Take a look at the [font documentation](fonts.md) for more
-// information.
+// be chosen via the `font` attribute, including font files that ship
+// with the app. The `height` and `offset` parameters allow fine tuning
+// of the vertical layout of the string. Take a look at the [font
+// documentation](fonts.md) for more information.
 //
 // DOC(Content): The text string to draw
 // DOC(Font): Desired font face
 // DOC(Height): Limits height of the area on which text is drawn
 // DOC(Offset): Shifts position of text vertically.
 // DOC(Color): Desired font color
+// DOC(Fallback): Fonts to draw characters missing from `font` with, in order of preference
 //
 // EXAMPLE BEGIN
 // render.Text(content="Tidbyt!", color="#099")
 // EXAMPLE END
 type Text struct {
 	Widget
-	Content string `starlark:"content,required"`
-	Font    string
-	Height  int
-	Offset  int
-	Color   color.Color
+	Content  string `starlark:"content,required"`
+	Font     string
+	Height   int
+	Offset   int
+	Color    color.Color
+	Fallback []string
+
+	img     image.Image
+	fonts   FontSource
+	missing []rune
+}
+
+// SetFontSource makes the fonts in src available to the widget, in addition
+// to the built-in ones.
+func (t *Text) SetFontSource(src FontSource) {
+	t.fonts = src
+}
 
-	img image.Image
+// MissingGlyphs returns the characters in the text that neither the font
+// nor its fallbacks can draw.
+func (t *Text) MissingGlyphs() []rune {
+	return t.missing
 }
 
 func (t *Text) Size() (int, int) {
@@ -57,10 +73,11 @@ func (t *Text) Init() error {
 	if t.Font == "" {
 		t.Font = DefaultFontFace
 	}
-	face, err := GetFont(t.Font)
+	face, missing, err := LoadFontChain(t.fonts, t.Font, t.Fallback, t.Content)
 	if err != nil {
 		return err
 	}
+	t.missing = missing
 
 	dc := gg.NewContext(0, 0)
 	dc.SetFontFace(face)
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestTextDefault(t *testing.T) {
@@ -200,3 +201,19 @@ func TestTextMissingFont(t *testing.T) {
 	}
 	assert.Error(t, text.Init())
 }
+
+func TestTextFallback(t *testing.T) {
+	text := &Text{Content: "aΩ", Font: "tom-thumb"}
+	require.NoError(t, text.Init())
+	assert.Equal(t, []rune{'Ω'}, text.MissingGlyphs())
+	w, h := text.Size()
+	assert.Equal(t, 4, w)
+	assert.Equal(t, 6, h)
+
+	text = &Text{Content: "aΩ", Font: "tom-thumb", Fallback: []string{"6x13"}}
+	require.NoError(t, text.Init())
+	assert.Empty(t, text.MissingGlyphs())
+	w, h = text.Size()
+	assert.Equal(t, 4+6, w)
+	assert.Equal(t, 13, h)
+}
@@ -0,0 +1,552 @@
+// Package tree converts widget trees to and from JSON.
+//
+// This makes it possible to run an app in one place and paint its
+// output in another, or to compare the trees apps produce in tests.
+// The format is described in docs/tree.md. Widgets and their
+// attributes have the same names as in Starlark.
+package tree
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+
+	"tidbyt.dev/pixlet/render"
+	"tidbyt.dev/pixlet/render/animation"
+)
+
+// Version is the version of the format written by Marshal. Unmarshal
+// reads this and any earlier version.
+const Version = 1
+
+var (
+	widgetType     = reflect.TypeOf((*render.Widget)(nil)).Elem()
+	colorType      = reflect.TypeOf((*color.Color)(nil)).Elem()
+	curveType      = reflect.TypeOf((*animation.Curve)(nil)).Elem()
+	percentageType = reflect.TypeOf(animation.Percentage{})
+	rectangleType  = reflect.TypeOf(image.Rectangle{})
+)
+
+// The types that can appear where a Widget, CanvasOp or Transform is
+// expected, by their name in the tree
+var nodeTypes = map[string]reflect.Type{
+	"render.Animation":   reflect.TypeOf(&render.Animation{}),
+	"render.Arc":         reflect.TypeOf(&render.Arc{}),
+	"render.BarChart":    reflect.TypeOf(&render.BarChart{}),
+	"render.Bezier":      reflect.TypeOf(&render.Bezier{}),
+	"render.Box":         reflect.TypeOf(&render.Box{}),
+	"render.Canvas":      reflect.TypeOf(&render.Canvas{}),
+	"render.Circle":      reflect.TypeOf(&render.Circle{}),
+	"render.Column":      reflect.TypeOf(&render.Column{}),
+	"render.Gauge":       reflect.TypeOf(&render.Gauge{}),
+	"render.Image":       reflect.TypeOf(&render.Image{}),
+	"render.Label":       reflect.TypeOf(&render.Label{}),
+	"render.Line":        reflect.TypeOf(&render.Line{}),
+	"render.Marquee":     reflect.TypeOf(&render.Marquee{}),
+	"render.Padding":     reflect.TypeOf(&render.Padding{}),
+	"render.PieChart":    reflect.TypeOf(&render.PieChart{}),
+	"render.Pixel":       reflect.TypeOf(&render.Pixel{}),
+	"render.Plot":        reflect.TypeOf(&render.Plot{}),
+	"render.Polygon":     reflect.TypeOf(&render.Polygon{}),
+	"render.Rect":        reflect.TypeOf(&render.Rect{}),
+	"render.RichText":    reflect.TypeOf(&render.RichText{}),
+	"render.Row":         reflect.TypeOf(&render.Row{}),
+	"render.Sequence":    reflect.TypeOf(&render.Sequence{}),
+	"render.Sparkline":   reflect.TypeOf(&render.Sparkline{}),
+	"render.Stack":       reflect.TypeOf(&render.Stack{}),
+	"render.Starfield":   reflect.TypeOf(&render.Starfield{}),
+	"render.Text":        reflect.TypeOf(&render.Text{}),
+	"render.WrappedText": reflect.TypeOf(&render.WrappedText{}),
+
+	"animation.AnimatedPositioned": reflect.TypeOf(&animation.AnimatedPositioned{}),
+	"animation.Transformation":     reflect.TypeOf(&animation.Transformation{}),
+
+	// transforms are values, not pointers
+	"animation.Rotate":    reflect.TypeOf(animation.Rotate{}),
+	"animation.Scale":     reflect.TypeOf(animation.Scale{}),
+	"animation.Translate": reflect.TypeOf(animation.Translate{}),
+}
+
+var nodeNames = map[reflect.Type]string{}
+
+// Interfaces with a fixed set of values, written as strings
+var enums = map[reflect.Type]map[string]interface{}{
+	reflect.TypeOf((*animation.Direction)(nil)).Elem(): {
+		"normal":            animation.DirectionNormal,
+		"reverse":           animation.DirectionReverse,
+		"alternate":         animation.DirectionAlternate,
+		"alternate-reverse": animation.DirectionAlternateReverse,
+	},
+	reflect.TypeOf((*animation.FillMode)(nil)).Elem(): {
+		"forwards":  animation.FillModeForwards{},
+		"backwards": animation.FillModeBackwards{},
+	},
+	reflect.TypeOf((*animation.Rounding)(nil)).Elem(): {
+		"round": animation.Round{},
+		"floor": animation.RoundFloor{},
+		"ceil":  animation.RoundCeil{},
+		"none":  animation.RoundNone{},
+	},
+}
+
+// Fields holding binary data, which is base64 encoded
+var binaryFields = map[reflect.Type]string{
+	reflect.TypeOf(render.Image{}): "Src",
+}
+
+func init() {
+	for name, t := range nodeTypes {
+		nodeNames[t] = name
+	}
+}
+
+type document struct {
+	Version int               `json:"version"`
+	Roots   []json.RawMessage `json:"roots"`
+}
+
+// An attribute of a widget or other value in the tree
+type attr struct {
+	name   string
+	index  []int
+	binary bool
+}
+
+// Returns the attributes of a struct type, named as in Starlark.
+// Fields of embedded structs are included as if they were the struct's
+// own.
+func attrs(t reflect.Type) []attr {
+	result := []attr{}
+
+	for _, field := range reflect.VisibleFields(t) {
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+
+		name := strings.ToLower(field.Name)
+		readonly := false
+		if tag, ok := field.Tag.Lookup("starlark"); ok {
+			parts := strings.Split(tag, ",")
+			if n := strings.TrimSpace(parts[0]); n != "" {
+				name = n
+			}
+			for _, p := range parts[1:] {
+				if strings.TrimSpace(p) == "readonly" {
+					readonly = true
+				}
+			}
+		}
+
+		// read-only attributes are computed when the widget is
+		// initialized
+		if readonly {
+			continue
+		}
+
+		result = append(result, attr{
+			name:   name,
+			index:  field.Index,
+			binary: binaryFields[t] == field.Name,
+		})
+	}
+
+	return result
+}
+
+// Marshal returns the JSON representation of roots.
+func Marshal(roots []render.Root) ([]byte, error) {
+	doc := document{Version: Version}
+
+	for i, root := range roots {
+		val, err := encodeStruct(reflect.ValueOf(root))
+		if err != nil {
+			return nil, fmt.Errorf("root %d: %w", i, err)
+		}
+
+		raw, err := json.Marshal(val)
+		if err != nil {
+			return nil, fmt.Errorf("root %d: %w", i, err)
+		}
+		doc.Roots = append(doc.Roots, raw)
+	}
+
+	return json.Marshal(doc)
+}
+
+func encodeStruct(v reflect.Value) (map[string]interface{}, error) {
+	result := map[string]interface{}{}
+
+	for _, a := range attrs(v.Type()) {
+		fv := v.FieldByIndex(a.index)
+		if fv.IsZero() {
+			continue
+		}
+
+		if a.binary {
+			result[a.name] = base64.StdEncoding.EncodeToString([]byte(fv.String()))
+			continue
+		}
+
+		val, err := encodeValue(fv)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", a.name, err)
+		}
+		result[a.name] = val
+	}
+
+	return result, nil
+}
+
+func encodeNode(v reflect.Value) (interface{}, error) {
+	name, ok := nodeNames[v.Type()]
+	if !ok {
+		return nil, fmt.Errorf("unsupported type %s", v.Type())
+	}
+
+	result, err := encodeStruct(reflect.Indirect(v))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	result["type"] = name
+	return result, nil
+}
+
+func encodeValue(v reflect.Value) (interface{}, error) {
+	t := v.Type()
+
+	if values, ok := enums[t]; ok {
+		if v.IsNil() {
+			return nil, nil
+		}
+		for name, val := range values {
+			if val == v.Interface() {
+				return name, nil
+			}
+		}
+		return nil, fmt.Errorf("unsupported value %v", v.Interface())
+	}
+
+	switch t {
+	case colorType:
+		if v.IsNil() {
+			return nil, nil
+		}
+		c := color.NRGBAModel.Convert(v.Interface().(color.Color)).(color.NRGBA)
+		if c.A == 0xff {
+			return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B), nil
+		}
+		return fmt.Sprintf("#%02x%02x%02x%02x", c.R, c.G, c.B, c.A), nil
+
+	case curveType:
+		if v.IsNil() {
+			return nil, nil
+		}
+		if s, ok := v.Interface().(fmt.Stringer); ok {
+			return s.String(), nil
+		}
+		return nil, fmt.Errorf("curve %T can't be serialized", v.Interface())
+
+	case percentageType:
+		return v.Interface().(animation.Percentage).Value, nil
+
+	case rectangleType:
+		r := v.Interface().(image.Rectangle)
+		return []int{r.Min.X, r.Min.Y, r.Dx(), r.Dy()}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		if v.IsNil() {
+			return nil, nil
+		}
+		if t.Kind() == reflect.Interface {
+			v = v.Elem()
+		}
+		return encodeNode(v)
+
+	case reflect.Bool, reflect.String, reflect.Int, reflect.Int32, reflect.Int64:
+		return v.Interface(), nil
+
+	case reflect.Float64:
+		f := v.Float()
+		if math.IsNaN(f) {
+			return nil, nil
+		}
+		if math.IsInf(f, 0) {
+			return nil, fmt.Errorf("infinite value")
+		}
+		return f, nil
+
+	case reflect.Slice, reflect.Array:
+		result := make([]interface{}, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			val, err := encodeValue(v.Index(i))
+			if err != nil {
+				return nil, fmt.Errorf("%d: %w", i, err)
+			}
+			result[i] = val
+		}
+		return result, nil
+
+	case reflect.Struct:
+		return encodeStruct(v)
+	}
+
+	return nil, fmt.Errorf("unsupported type %s", t)
+}
+
+// UnmarshalOption configures how a tree is unmarshaled.
+type UnmarshalOption func(*decoder)
+
+// WithFontSource makes the fonts in src available to widgets that
+// draw text, in addition to the built-in ones.
+func WithFontSource(src render.FontSource) UnmarshalOption {
+	return func(d *decoder) {
+		d.fonts = src
+	}
+}
+
+type decoder struct {
+	fonts render.FontSource
+}
+
+// Unmarshal returns the roots represented by data. Widgets are
+// initialized as if they had been created by an app, so the roots are
+// ready to paint.
+func Unmarshal(data []byte, opts ...UnmarshalOption) ([]render.Root, error) {
+	d := &decoder{}
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	var doc document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing tree: %w", err)
+	}
+
+	if doc.Version < 1 {
+		return nil, fmt.Errorf("tree has no version")
+	}
+	if doc.Version > Version {
+		return nil, fmt.Errorf("unsupported tree version %d (expected at most %d)", doc.Version, Version)
+	}
+
+	roots := make([]render.Root, len(doc.Roots))
+	for i, raw := range doc.Roots {
+		if err := d.decodeStruct(raw, reflect.ValueOf(&roots[i]).Elem(), false); err != nil {
+			return nil, fmt.Errorf("root %d: %w", i, err)
+		}
+	}
+
+	if err := render.InitRoots(roots); err != nil {
+		return nil, err
+	}
+
+	return roots, nil
+}
+
+func (d *decoder) decodeStruct(data json.RawMessage, v reflect.Value, isNode bool) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	if isNode {
+		delete(fields, "type")
+	}
+
+	for _, a := range attrs(v.Type()) {
+		raw, ok := fields[a.name]
+		if !ok {
+			continue
+		}
+		delete(fields, a.name)
+
+		fv := v.FieldByIndex(a.index)
+		if a.binary {
+			var s string
+			if err := json.Unmarshal(raw, &s); err != nil {
+				return fmt.Errorf("%s: %w", a.name, err)
+			}
+			b, err := base64.StdEncoding.DecodeString(s)
+			if err != nil {
+				return fmt.Errorf("%s: %w", a.name, err)
+			}
+			fv.SetString(string(b))
+			continue
+		}
+
+		if err := d.decodeValue(raw, fv); err != nil {
+			return fmt.Errorf("%s: %w", a.name, err)
+		}
+	}
+
+	if len(fields) > 0 {
+		unknown := []string{}
+		for name := range fields {
+			unknown = append(unknown, name)
+		}
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown attributes: %s", strings.Join(unknown, ", "))
+	}
+
+	return nil
+}
+
+func (d *decoder) decodeNode(data json.RawMessage) (reflect.Value, error) {
+	var header struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return reflect.Value{}, err
+	}
+
+	t, ok := nodeTypes[header.Type]
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("unknown type '%s'", header.Type)
+	}
+
+	var v reflect.Value
+	if t.Kind() == reflect.Ptr {
+		v = reflect.New(t.Elem())
+		if err := d.decodeStruct(data, v.Elem(), true); err != nil {
+			return reflect.Value{}, fmt.Errorf("%s: %w", header.Type, err)
+		}
+	} else {
+		v = reflect.New(t).Elem()
+		if err := d.decodeStruct(data, v, true); err != nil {
+			return reflect.Value{}, fmt.Errorf("%s: %w", header.Type, err)
+		}
+	}
+
+	// children have already been initialized, just like when an app
+	// creates the tree
+	if w, ok := v.Interface().(render.WidgetWithFonts); ok && d.fonts != nil {
+		w.SetFontSource(d.fonts)
+	}
+	if w, ok := v.Interface().(render.WidgetWithInit); ok {
+		if err := w.Init(); err != nil {
+			return reflect.Value{}, fmt.Errorf("%s: %w", header.Type, err)
+		}
+	}
+
+	return v, nil
+}
+
+func (d *decoder) decodeValue(data json.RawMessage, v reflect.Value) error {
+	t := v.Type()
+
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		if t.Kind() == reflect.Float64 {
+			v.SetFloat(math.NaN())
+		} else {
+			v.Set(reflect.Zero(t))
+		}
+		return nil
+	}
+
+	if values, ok := enums[t]; ok {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		val, ok := values[s]
+		if !ok {
+			return fmt.Errorf("unknown value '%s'", s)
+		}
+		v.Set(reflect.ValueOf(val))
+		return nil
+	}
+
+	switch t {
+	case colorType:
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		c, err := render.ParseColor(s)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(c))
+		return nil
+
+	case curveType:
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		c, err := animation.ParseCurve(s)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(c))
+		return nil
+
+	case percentageType:
+		var f float64
+		if err := json.Unmarshal(data, &f); err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(animation.Percentage{Value: f}))
+		return nil
+
+	case rectangleType:
+		var r [4]int
+		if err := json.Unmarshal(data, &r); err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(image.Rect(r[0], r[1], r[0]+r[2], r[1]+r[3])))
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+		node, err := d.decodeNode(data)
+		if err != nil {
+			return err
+		}
+		if !node.Type().AssignableTo(t) {
+			return fmt.Errorf("%s is not a %s", nodeNames[node.Type()], t.Name())
+		}
+		v.Set(node)
+		return nil
+
+	case reflect.Bool, reflect.String, reflect.Int, reflect.Int32, reflect.Int64, reflect.Float64:
+		return json.Unmarshal(data, v.Addr().Interface())
+
+	case reflect.Slice, reflect.Array:
+		var elems []json.RawMessage
+		if err := json.Unmarshal(data, &elems); err != nil {
+			return err
+		}
+
+		if t.Kind() == reflect.Array {
+			if len(elems) != t.Len() {
+				return fmt.Errorf("expected %d elements, found %d", t.Len(), len(elems))
+			}
+		} else {
+			v.Set(reflect.MakeSlice(t, len(elems), len(elems)))
+		}
+
+		for i, elem := range elems {
+			if err := d.decodeValue(elem, v.Index(i)); err != nil {
+				return fmt.Errorf("%d: %w", i, err)
+			}
+		}
+		return nil
+
+	case reflect.Struct:
+		return d.decodeStruct(data, v, false)
+	}
+
+	return fmt.Errorf("unsupported type %s", t)
+}
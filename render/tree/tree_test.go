@@ -0,0 +1,223 @@
+package tree
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"tidbyt.dev/pixlet/render"
+	"tidbyt.dev/pixlet/render/animation"
+	"tidbyt.dev/pixlet/runtime"
+)
+
+// A 2x2 PNG, red on the diagonal
+const testPNG = "iVBORw0KGgoAAAANSUhEUgAAAAIAAAACCAYAAABytg0kAAAAH0lEQVR4nAASAO3/Av8AAP8AAAAAAAAAAAD/AAD/AwAkEgP/N/JgfwAAAABJRU5ErkJggg=="
+
+const testApp = `
+load("render.star", "render")
+load("animation.star", "animation")
+load("encoding/base64.star", "base64")
+
+def main():
+    return [
+        render.Root(
+            delay = 100,
+            max_age = 60,
+            child = render.Column(
+                main_align = "space_between",
+                children = [
+                    render.Row(children = [
+                        render.Box(width = 4, height = 4, color = "#f008"),
+                        render.Padding(pad = (1, 0, 1, 0), child = render.Circle(diameter = 4, color = "#0f0")),
+                        render.Image(src = base64.decode("%s"), width = 4, resample = "bilinear"),
+                        render.Text("hey", font = "tom-thumb", color = "#00f"),
+                    ]),
+                    render.Stack(children = [
+                        render.Plot(data = [(0, 1), (1, 3), (2, 2)], width = 16, height = 6, y_lim = (0, None)),
+                        render.BarChart(data = [1, (2, 3), [4, None]], width = 16, height = 6),
+                    ]),
+                    render.Marquee(width = 20, child = render.WrappedText("a long line of text", width = 40)),
+                    render.RichText(spans = ["a", render.Span("b", color = "#f00")]),
+                    render.Canvas(width = 8, height = 8, ops = [
+                        render.Pixel(x = 1, y = 1),
+                        render.Line(x0 = 0, y0 = 0, x1 = 7, y1 = 7, color = "#ff0", end_frame = 3),
+                        render.Arc(x = 4, y = 4, radius = 3, start = 0, end = 90),
+                        render.Label(x = 0, y = 1, content = "x"),
+                    ]),
+                ],
+            ),
+        ),
+        render.Root(
+            child = animation.Transformation(
+                child = render.Box(width = 8, height = 8, color = "#fff"),
+                duration = 10,
+                delay = 2,
+                direction = "alternate",
+                rounding = "floor",
+                origin = animation.Origin(0.25, 0.75),
+                keyframes = [
+                    animation.Keyframe(
+                        percentage = 0.5,
+                        transforms = [animation.Translate(4, 2), animation.Rotate(45), animation.Scale(2, 2)],
+                        curve = "ease_in_out",
+                    ),
+                ],
+            ),
+        ),
+        render.Root(
+            delay = 25,
+            time_based = True,
+            child = render.Marquee(
+                width = 8,
+                delay = 100,
+                child = animation.AnimatedPositioned(
+                    child = render.Text("time"),
+                    x_start = 0,
+                    x_end = 8,
+                    duration = 250,
+                    hold = 50,
+                    curve = "linear",
+                ),
+            ),
+        ),
+    ]
+`
+
+func runTestApp(t *testing.T) []render.Root {
+	app, err := runtime.NewApplet("tree.star", []byte(fmt.Sprintf(testApp, testPNG)))
+	require.NoError(t, err)
+
+	roots, err := app.Run(context.Background())
+	require.NoError(t, err)
+	return roots
+}
+
+func TestRoundTrip(t *testing.T) {
+	roots := runTestApp(t)
+
+	data, err := Marshal(roots)
+	require.NoError(t, err)
+
+	decoded, err := Unmarshal(data)
+	require.NoError(t, err)
+	require.Equal(t, 3, len(decoded))
+
+	// marshaling again gives the same tree
+	again, err := Marshal(decoded)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(data), string(again))
+
+	// and the same frames
+	expected := render.PaintRoots(true, roots...)
+	actual := render.PaintRoots(true, decoded...)
+	require.Equal(t, len(expected), len(actual))
+	for i := range expected {
+		assert.Equal(t, expected[i], actual[i], "frame %d", i)
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	data, err := Marshal([]render.Root{{
+		Delay: 50,
+		Child: &render.Padding{
+			Pad: render.Insets{Left: 1, Top: 2},
+			Child: &render.Plot{
+				Data:   [][2]float64{{0, 1}, {1, 2}},
+				Width:  4,
+				Height: 3,
+				Color:  color.NRGBA{0xff, 0, 0, 0x80},
+				YLim:   [2]float64{0, math.NaN()},
+			},
+		},
+	}})
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"version": 1,
+		"roots": [{
+			"delay": 50,
+			"child": {
+				"type": "render.Padding",
+				"pad": {"left": 1, "top": 2},
+				"child": {
+					"type": "render.Plot",
+					"data": [[0, 1], [1, 2]],
+					"width": 4,
+					"height": 3,
+					"color": "#ff000080",
+					"y_lim": [0, null]
+				}
+			}
+		}]
+	}`, string(data))
+}
+
+func TestImageSrc(t *testing.T) {
+	raw, _ := base64.StdEncoding.DecodeString(testPNG)
+
+	data, err := Marshal([]render.Root{{Child: &render.Image{Src: string(raw), Crop: image.Rect(1, 0, 2, 2)}}})
+	require.NoError(t, err)
+
+	var doc struct {
+		Roots []struct {
+			Child map[string]interface{}
+		}
+	}
+	require.NoError(t, json.Unmarshal(data, &doc))
+	assert.Equal(t, testPNG, doc.Roots[0].Child["src"])
+	assert.Equal(t, []interface{}{1.0, 0.0, 1.0, 2.0}, doc.Roots[0].Child["crop"])
+
+	roots, err := Unmarshal(data)
+	require.NoError(t, err)
+	img := roots[0].Child.(*render.Image)
+	assert.Equal(t, string(raw), img.Src)
+
+	// the image is ready to paint
+	w, h := img.Size()
+	assert.Equal(t, 1, w)
+	assert.Equal(t, 2, h)
+}
+
+func TestCustomCurve(t *testing.T) {
+	_, err := Marshal([]render.Root{{
+		Child: &animation.AnimatedPositioned{
+			Child:    &render.Box{},
+			Duration: 10,
+			Curve:    animation.CustomCurve{},
+		},
+	}})
+	assert.Error(t, err)
+}
+
+func TestUnsupportedWidget(t *testing.T) {
+	type custom struct{ render.Box }
+
+	_, err := Marshal([]render.Root{{Child: &custom{}}})
+	assert.Error(t, err)
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	for name, data := range map[string]string{
+		"not json":          `{`,
+		"no version":        `{"roots": []}`,
+		"future version":    `{"version": 2, "roots": []}`,
+		"unknown type":      `{"version": 1, "roots": [{"child": {"type": "render.Blob"}}]}`,
+		"unknown attribute": `{"version": 1, "roots": [{"child": {"type": "render.Box", "size": 3}}]}`,
+		"wrong kind":        `{"version": 1, "roots": [{"child": {"type": "animation.Rotate", "angle": 3}}]}`,
+		"bad color":         `{"version": 1, "roots": [{"child": {"type": "render.Box", "color": "red"}}]}`,
+		"bad enum":          `{"version": 1, "roots": [{"child": {"type": "animation.Transformation", "child": {"type": "render.Box"}, "direction": "sideways"}}]}`,
+		"bad array":         `{"version": 1, "roots": [{"child": {"type": "render.Plot", "data": [[1, 2, 3]]}}]}`,
+		"init fails":        `{"version": 1, "roots": [{"child": {"type": "render.Text", "content": "a", "font": "nope"}}]}`,
+	} {
+		_, err := Unmarshal([]byte(data))
+		assert.Error(t, err, name)
+	}
+}
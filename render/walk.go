@@ -0,0 +1,53 @@
+package render
+
+import (
+	"reflect"
+)
+
+var (
+	widgetType      = reflect.TypeOf((*Widget)(nil)).Elem()
+	widgetSliceType = reflect.TypeOf([]Widget(nil))
+)
+
+// Walk calls fn for w and every widget below it, parents before their
+// children. Children are found by looking for fields of type Widget or
+// []Widget, including those of structs in slices, like the images in
+// the spans of a RichText.
+func Walk(w Widget, fn func(Widget)) {
+	if w == nil {
+		return
+	}
+
+	fn(w)
+	walkFields(reflect.Indirect(reflect.ValueOf(w)), fn)
+}
+
+func walkFields(v reflect.Value, fn func(Widget)) {
+	if v.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+
+		switch {
+		case field.Type == widgetType:
+			if child, ok := v.Field(i).Interface().(Widget); ok {
+				Walk(child, fn)
+			}
+
+		case field.Type == widgetSliceType:
+			for _, child := range v.Field(i).Interface().([]Widget) {
+				Walk(child, fn)
+			}
+
+		case field.Type.Kind() == reflect.Slice && field.Type.Elem().Kind() == reflect.Struct:
+			for j := 0; j < v.Field(i).Len(); j++ {
+				walkFields(v.Field(i).Index(j), fn)
+			}
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalk(t *testing.T) {
+	a := &Text{Content: "a"}
+	b := &Box{Width: 1}
+	c := &Text{Content: "c"}
+
+	tree := &Row{
+		Children: []Widget{
+			&Padding{Child: a},
+			&Column{Children: []Widget{b, c}},
+		},
+	}
+
+	var visited []Widget
+	Walk(tree, func(w Widget) {
+		visited = append(visited, w)
+	})
+
+	assert.Equal(t, []Widget{
+		tree,
+		tree.Children[0],
+		a,
+		tree.Children[1],
+		b,
+		c,
+	}, visited)
+
+	// empty children are skipped
+	visited = nil
+	Walk(&Box{}, func(w Widget) {
+		visited = append(visited, w)
+	})
+	assert.Len(t, visited, 1)
+}
+
+func TestWalkSpanImages(t *testing.T) {
+	m := &Marquee{Width: 2, Child: &Text{Content: "image"}}
+	rt := &RichText{Spans: []Span{
+		{Content: "a"},
+		{Image: m},
+	}}
+
+	var visited []Widget
+	Walk(rt, func(w Widget) {
+		visited = append(visited, w)
+	})
+
+	assert.Equal(t, []Widget{rt, m, m.Child}, visited)
+
+	// so time based roots reach them too
+	root := Root{Child: rt, Delay: 100, TimeBased: true}
+	assert.NoError(t, root.Init())
+	assert.Equal(t, 100, m.frameDelay)
+}
@@ -2,6 +2,7 @@ package render
 
 import (
 	"image"
+	"math"
 
 	"github.com/tidbyt/gg"
 )
@@ -19,6 +20,17 @@ type WidgetWithInit interface {
 	Init() error
 }
 
+// Widgets can draw text in fonts beyond the built-in ones
+type WidgetWithFonts interface {
+	SetFontSource(src FontSource)
+}
+
+// Widgets can time their animations in milliseconds rather than frames.
+// A delay of 0 means their durations are counted in frames.
+type WidgetWithFrameDelay interface {
+	SetFrameDelay(delay int)
+}
+
 // WidgetStaticSize has inherent size and width known before painting.
 type WidgetStaticSize interface {
 	Size() (int, int)
@@ -34,6 +46,25 @@ func ModInt(a, m int) int {
 	return a
 }
 
+// Converts a duration of d milliseconds to the closest number of
+// frames that are frameDelay milliseconds each. A frameDelay of 0
+// means d is already a number of frames. Durations never round down
+// to 0 frames.
+func DurationFrames(d, frameDelay int) int {
+	if frameDelay <= 0 || d == 0 {
+		return d
+	}
+
+	frames := int(math.Round(float64(d) / float64(frameDelay)))
+	if frames == 0 {
+		if d > 0 {
+			return 1
+		}
+		return -1
+	}
+	return frames
+}
+
 // Computes the maximum frame count of a slice of widgets.
 func MaxFrameCount(widgets []Widget) int {
 	m := 1
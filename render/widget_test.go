@@ -0,0 +1,81 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDurationFrames(t *testing.T) {
+	// without a frame delay, durations are already in frames
+	assert.Equal(t, 7, DurationFrames(7, 0))
+
+	assert.Equal(t, 20, DurationFrames(1000, 50))
+	assert.Equal(t, 33, DurationFrames(1000, 30))
+	assert.Equal(t, 0, DurationFrames(0, 50))
+	assert.Equal(t, -2, DurationFrames(-100, 50))
+
+	// short durations still take a frame
+	assert.Equal(t, 1, DurationFrames(10, 50))
+	assert.Equal(t, -1, DurationFrames(-10, 50))
+}
+
+func TestRootTimeBased(t *testing.T) {
+	m := &Marquee{Width: 2, Child: Box{Width: 4, Height: 1}, Delay: 200}
+	tree := &Column{Children: []Widget{&Padding{Child: m}}}
+
+	// durations are left alone unless the root is time based
+	root := Root{Child: tree, Delay: 100}
+	assert.NoError(t, root.Init())
+	assert.Equal(t, 0, m.frameDelay)
+
+	root.TimeBased = true
+	assert.NoError(t, root.Init())
+	assert.Equal(t, 100, m.frameDelay)
+
+	// the default delay is used if the root doesn't have one
+	root.Delay = 0
+	assert.NoError(t, root.Init())
+	assert.Equal(t, DefaultFrameDelay, m.frameDelay)
+}
+
+func TestInitRoots(t *testing.T) {
+	m := &Marquee{Width: 2, Child: Box{Width: 4, Height: 1}, Delay: 200}
+	roots := []Root{
+		{Child: Box{}, Delay: 100},
+		{Child: m, TimeBased: true},
+	}
+
+	// a root without a delay is shown with that of the first root, so
+	// its durations are converted with it too
+	assert.NoError(t, InitRoots(roots))
+	assert.Equal(t, 100, m.frameDelay)
+	assert.Equal(t, int32(100), roots[1].FrameDelay(roots[0].Delay))
+
+	_, delays := PaintRootsWithDelays(true, roots...)
+	for _, d := range delays {
+		assert.Equal(t, int32(100), d)
+	}
+
+	roots[0].Delay = 0
+	assert.NoError(t, InitRoots(roots))
+	assert.Equal(t, DefaultFrameDelay, m.frameDelay)
+
+	// a widget can only be converted with one delay
+	roots = append(roots, Root{Child: m, Delay: 200, TimeBased: true})
+	assert.ErrorContains(t, InitRoots(roots), "shared by roots")
+
+	roots[2] = Root{Child: m}
+	assert.ErrorContains(t, InitRoots(roots), "shared by roots")
+
+	roots[2] = Root{Child: m, TimeBased: true}
+	assert.NoError(t, InitRoots(roots))
+
+	// and it must be a pointer to hold on to the delay
+	root := Root{Child: *m, TimeBased: true}
+	assert.ErrorContains(t, root.Init(), "must be passed by pointer")
+	assert.ErrorContains(t, InitRoots([]Root{root}), "must be passed by pointer")
+
+	root.TimeBased = false
+	assert.NoError(t, root.Init())
+}
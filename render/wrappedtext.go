@@ -27,6 +27,7 @@ import (
 // DOC(LineSpacing): Controls spacing between lines
 // DOC(Color): Desired font color
 // DOC(Align): Text Alignment
+// DOC(Fallback): Fonts to draw characters missing from `font` with, in order of preference
 // EXAMPLE BEGIN
 // render.WrappedText(
 //
@@ -46,8 +47,17 @@ type WrappedText struct {
 	LineSpacing int
 	Color       color.Color
 	Align       string
+	Fallback    []string
 
-	face font.Face
+	face    font.Face
+	fonts   FontSource
+	missing []rune
+}
+
+// SetFontSource makes the fonts in src available to the widget, in addition
+// to the built-in ones.
+func (tw *WrappedText) SetFontSource(src FontSource) {
+	tw.fonts = src
 }
 
 func (tw *WrappedText) Init() error {
@@ -55,16 +65,23 @@ func (tw *WrappedText) Init() error {
 		tw.Font = DefaultFontFace
 	}
 
-	face, err := GetFont(tw.Font)
+	face, missing, err := LoadFontChain(tw.fonts, tw.Font, tw.Fallback, tw.Content)
 	if err != nil {
 		return err
 	}
 
 	tw.face = face
+	tw.missing = missing
 
 	return nil
 }
 
+// MissingGlyphs returns the characters in the text that neither the font
+// nor its fallbacks can draw.
+func (tw *WrappedText) MissingGlyphs() []rune {
+	return tw.missing
+}
+
 func (tw *WrappedText) PaintBounds(bounds image.Rectangle, frameIdx int) image.Rectangle {
 	// The bounds provided by user or parent widget
 	width := tw.Width
@@ -26,7 +26,6 @@ import (
 	"go.starlark.net/starlark"
 	"go.starlark.net/starlarkstruct"
 	"go.starlark.net/starlarktest"
-	"go.starlark.net/syntax"
 
 	"tidbyt.dev/pixlet/render"
 	"tidbyt.dev/pixlet/runtime/modules/animation_runtime"
@@ -62,6 +61,9 @@ type Applet struct {
 	loader       ModuleLoader
 	initializers []ThreadInitializer
 	loadedPaths  map[string]bool
+	fonts        *appletFonts
+	programs     ProgramCache
+	limits       limits
 
 	mainFun    *starlark.Function
 	schemaFile string
@@ -186,19 +188,29 @@ func ExtractRoots(val starlark.Value) ([]render.Root, error) {
 		return nil, fmt.Errorf("expected app implementation to return Root(s) but found: %s", val.Type())
 	}
 
+	if err := render.InitRoots(roots); err != nil {
+		return nil, err
+	}
+
 	return roots, nil
 }
 
 // RunWithConfig exceutes the applet's main function, passing it configuration as a
 // starlark dict. It returns the render roots that are returned by the applet.
 func (a *Applet) RunWithConfig(ctx context.Context, config map[string]string) (roots []render.Root, err error) {
+	return a.run(ctx, config)
+}
+
+// run is like RunWithConfig, but additionally runs the given initializers on
+// the thread after the applet's own.
+func (a *Applet) run(ctx context.Context, config map[string]string, inits ...ThreadInitializer) (roots []render.Root, err error) {
 	var args starlark.Tuple
 	if a.mainFun.NumParams() > 0 {
 		starlarkConfig := AppletConfig(config)
 		args = starlark.Tuple{starlarkConfig}
 	}
 
-	returnValue, err := a.Call(ctx, a.mainFun, args...)
+	returnValue, err := a.call(ctx, a.mainFun, args, inits...)
 	if err != nil {
 		return nil, err
 	}
@@ -208,6 +220,10 @@ func (a *Applet) RunWithConfig(ctx context.Context, config map[string]string) (r
 		return nil, err
 	}
 
+	if err := a.checkRoots(roots); err != nil {
+		return nil, err
+	}
+
 	return roots, nil
 }
 
@@ -263,12 +279,18 @@ func (app *Applet) CallSchemaHandler(ctx context.Context, handlerName, parameter
 	return "", fmt.Errorf("a very unexpected error happened for handler \"%s\"", handlerName)
 }
 
-// RunTests runs all test functions that are defined in the applet source.
-func (app *Applet) RunTests(t *testing.T) {
-	app.initializers = append(app.initializers, func(thread *starlark.Thread) *starlark.Thread {
-		starlarktest.SetReporter(thread, t)
-		return thread
-	})
+// TestFunction is a function defined in the applet source whose name starts
+// with "test_".
+type TestFunction struct {
+	File     string
+	Name     string
+	Function *starlark.Function
+}
+
+// TestFunctions returns all test functions that are defined in the applet
+// source, sorted by file and name.
+func (app *Applet) TestFunctions() []TestFunction {
+	var tests []TestFunction
 
 	for file, globals := range app.Globals {
 		for name, global := range globals {
@@ -277,19 +299,53 @@ func (app *Applet) RunTests(t *testing.T) {
 			}
 
 			if fun, ok := global.(*starlark.Function); ok {
-				t.Run(fmt.Sprintf("%s/%s", file, name), func(t *testing.T) {
-					if _, err := app.Call(context.Background(), fun); err != nil {
-						t.Error(err)
-					}
+				tests = append(tests, TestFunction{
+					File:     file,
+					Name:     name,
+					Function: fun,
 				})
 			}
 		}
 	}
+
+	slices.SortFunc(tests, func(a, b TestFunction) int {
+		return strings.Compare(a.File+"/"+a.Name, b.File+"/"+b.Name)
+	})
+
+	return tests
+}
+
+// RunTest calls a single test function. Failures reported through the
+// assert module go to the reporter, while errors that stop the test from
+// completing are returned.
+func (app *Applet) RunTest(ctx context.Context, test TestFunction, reporter starlarktest.Reporter) error {
+	_, err := app.call(ctx, test.Function, nil, func(thread *starlark.Thread) *starlark.Thread {
+		starlarktest.SetReporter(thread, reporter)
+		return thread
+	})
+	return err
+}
+
+// RunTests runs all test functions that are defined in the applet source.
+func (app *Applet) RunTests(t *testing.T) {
+	for _, test := range app.TestFunctions() {
+		t.Run(fmt.Sprintf("%s/%s", test.File, test.Name), func(t *testing.T) {
+			if err := app.RunTest(context.Background(), test, t); err != nil {
+				t.Error(err)
+			}
+		})
+	}
 }
 
 // Calls any callable from Applet.Globals. Pass args and receive a
 // starlark Value, or an error if you're unlucky.
 func (a *Applet) Call(ctx context.Context, callable *starlark.Function, args ...starlark.Value) (val starlark.Value, err error) {
+	return a.call(ctx, callable, args)
+}
+
+// call is like Call, but additionally runs the given initializers on the
+// thread after the applet's own.
+func (a *Applet) call(ctx context.Context, callable *starlark.Function, args starlark.Tuple, inits ...ThreadInitializer) (val starlark.Value, err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			err = fmt.Errorf("panic while running %s: %v\n%s", a.ID, r, debug.Stack())
@@ -297,6 +353,9 @@ func (a *Applet) Call(ctx context.Context, callable *starlark.Function, args ...
 	}()
 
 	t := a.newThread(ctx)
+	for _, init := range inits {
+		t = init(t)
+	}
 	defer starlarkutil.RunOnExitFuncs(t)
 
 	context.AfterFunc(ctx, func() {
@@ -305,12 +364,16 @@ func (a *Applet) Call(ctx context.Context, callable *starlark.Function, args ...
 
 	resultVal, err := starlark.Call(t, callable, args, nil)
 	if err != nil {
+		if limitErr := a.stepsExceeded(t); limitErr != nil {
+			return nil, fmt.Errorf("in %s: %w", callable.Name(), limitErr)
+		}
+
 		evalErr, ok := err.(*starlark.EvalError)
 		if ok {
-			return nil, fmt.Errorf(evalErr.Backtrace())
+			return nil, &backtraceError{evalErr}
 		}
 		return nil, fmt.Errorf(
-			"in %s at %s: %s",
+			"in %s at %s: %w",
 			callable.Name(),
 			callable.Position().String(),
 			err,
@@ -320,6 +383,16 @@ func (a *Applet) Call(ctx context.Context, callable *starlark.Function, args ...
 	return resultVal, nil
 }
 
+// backtraceError reports a Starlark error with its backtrace, while still
+// letting callers inspect the error that caused it.
+type backtraceError struct {
+	*starlark.EvalError
+}
+
+func (e *backtraceError) Error() string {
+	return e.Backtrace()
+}
+
 // PathsForBundle returns a list of all the paths that have been loaded by the
 // applet. This is useful for creating a bundle of the applet.
 func (a *Applet) PathsForBundle() []string {
@@ -337,6 +410,16 @@ func (a *Applet) load(fsys fs.FS) (err error) {
 		return fmt.Errorf("reading root directory: %v", err)
 	}
 
+	// find fonts before running any code, so that they can be used at
+	// load time
+	a.fonts, err = findFonts(fsys)
+	if err != nil {
+		return err
+	}
+	for _, p := range a.fonts.Paths() {
+		a.loadedPaths[p] = true
+	}
+
 	for _, d := range rootDir {
 		if d.IsDir() || !strings.HasSuffix(d.Name(), ".star") {
 			// only process Starlark files
@@ -420,19 +503,19 @@ func (a *Applet) ensureLoaded(fsys fs.FS, pathToLoad string, currentlyLoading ..
 
 	switch path.Ext(pathToLoad) {
 	case ".star":
-		globals, err := starlark.ExecFileOptions(
-			&syntax.FileOptions{
-				Set:       true,
-				Recursion: true,
-			},
-			thread,
-			path.Join(a.ID, pathToLoad),
-			src,
-			predeclared,
-		)
+		prog, err := a.compile(path.Join(a.ID, pathToLoad), src, predeclared)
 		if err != nil {
 			return fmt.Errorf("starlark.ExecFile: %v", err)
 		}
+
+		globals, err := prog.Init(thread, predeclared)
+		globals.Freeze()
+		if limitErr := a.stepsExceeded(thread); limitErr != nil {
+			return fmt.Errorf("starlark.ExecFile: %w", limitErr)
+		}
+		if err != nil {
+			return fmt.Errorf("starlark.ExecFile: %w", err)
+		}
 		a.Globals[pathToLoad] = globals
 
 		// if the file is in the root directory, check for the main function
@@ -493,6 +576,10 @@ func (a *Applet) newThread(ctx context.Context) *starlark.Thread {
 
 	starlarkutil.AttachThreadContext(ctx, t)
 	random.AttachToThread(t)
+	a.attachLimits(t)
+	if a.fonts != nil {
+		render_runtime.AttachFontsToThread(t, a.fonts)
+	}
 
 	for _, init := range a.initializers {
 		t = init(t)
@@ -453,4 +453,60 @@ def main():
 	app.RunTests(t)
 }
 
+type testReporter struct {
+	errors []string
+}
+
+func (r *testReporter) Error(args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprint(args...))
+}
+
+func TestRunTest(t *testing.T) {
+	src := `
+load("assert.star", "assert")
+load("render.star", "render")
+
+def test_passes():
+    assert.eq(1 + 1, 2)
+
+def test_assertion_fails():
+    assert.eq(1 + 1, 3)
+    assert.true(False)
+
+def test_fails():
+    fail("oh no")
+
+def helper_test_not_a_test():
+    fail("should not run")
+
+def main():
+    return render.Root(child = render.Box())
+`
+
+	app, err := NewApplet("test.star", []byte(src))
+	require.NoError(t, err)
+
+	tests := app.TestFunctions()
+	require.Len(t, tests, 3)
+	assert.Equal(t, "test_assertion_fails", tests[0].Name)
+	assert.Equal(t, "test_fails", tests[1].Name)
+	assert.Equal(t, "test_passes", tests[2].Name)
+	assert.Equal(t, "test.star", tests[0].File)
+
+	r := &testReporter{}
+	assert.NoError(t, app.RunTest(context.Background(), tests[0], r))
+	require.Len(t, r.errors, 2)
+	assert.Contains(t, r.errors[0], "test.star:9:14: in test_assertion_fails")
+	assert.Contains(t, r.errors[0], "Error: 2 != 3")
+
+	r = &testReporter{}
+	err = app.RunTest(context.Background(), tests[1], r)
+	assert.ErrorContains(t, err, "oh no")
+	assert.Empty(t, r.errors)
+
+	r = &testReporter{}
+	assert.NoError(t, app.RunTest(context.Background(), tests[2], r))
+	assert.Empty(t, r.errors)
+}
+
 // TODO: test Screens, especially Screens.Render()
@@ -110,6 +110,10 @@ func cacheGet(thread *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple,
 
 	val, found, err := cache.Get(thread, cacheKey)
 
+	if report := threadReport(thread); report != nil {
+		report.addCacheCall(CacheCall{Op: "get", Key: key.GoString(), Hit: found && err == nil})
+	}
+
 	if err != nil {
 		// don't fail just because cache is misbehaving
 		log.Printf("getting %s from cache: %v", cacheKey, err)
@@ -155,6 +159,10 @@ func cacheSet(thread *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple,
 		ttl64 = DefaultExpirationSeconds
 	}
 
+	if report := threadReport(thread); report != nil {
+		report.addCacheCall(CacheCall{Op: "set", Key: key.GoString(), TTL: ttl64})
+	}
+
 	if cache == nil {
 		// no cache configured
 		return starlark.None, nil
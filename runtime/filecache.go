@@ -0,0 +1,285 @@
+package runtime
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"go.starlark.net/starlark"
+)
+
+const (
+	// fileCacheSuffix is appended to the name of every record file, so that
+	// stray files in the cache directory are never read or evicted.
+	fileCacheSuffix = ".cache"
+
+	// fileCacheHeaderSize is the size of the expiration timestamp that
+	// prefixes every record on disk.
+	fileCacheHeaderSize = 8
+
+	// fileCacheEvictionRatio is the fraction of the maximum size that the
+	// cache is trimmed down to when it grows too large. Evicting a little
+	// more than strictly needed avoids a directory scan on every write.
+	fileCacheEvictionRatio = 0.9
+)
+
+// FileCache is a Cache that persists records on disk, so that they survive
+// across runs of pixlet.
+//
+// Every record is stored in its own file, named after a hash of its key, and
+// written atomically by renaming a temporary file into place. This makes it
+// safe for several processes to share the same cache directory without
+// locking. Records are evicted in least recently used order once the total
+// size of the cache exceeds its maximum size.
+type FileCache struct {
+	dir     string
+	maxSize int64
+
+	// size is an estimate of the number of bytes on disk. It's only
+	// updated by this process, so it's reconciled against the actual
+	// directory contents whenever eviction runs.
+	size  int64
+	mutex sync.Mutex
+}
+
+// NewFileCache creates a FileCache that stores records in dir, creating the
+// directory if needed. If maxSize is positive, the cache will evict records
+// to keep its total size below maxSize bytes.
+func NewFileCache(dir string, maxSize int64) (*FileCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("creating cache directory %s: %w", dir, err)
+	}
+
+	c := &FileCache{
+		dir:     dir,
+		maxSize: maxSize,
+	}
+
+	records, err := c.records()
+	if err != nil {
+		return nil, fmt.Errorf("reading cache directory %s: %w", dir, err)
+	}
+
+	for _, r := range records {
+		c.size += r.size
+	}
+
+	return c, nil
+}
+
+func (c *FileCache) Get(_ *starlark.Thread, key string) (value []byte, found bool, err error) {
+	path := c.pathForKey(key)
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("reading cache record: %w", err)
+	}
+
+	if len(b) < fileCacheHeaderSize {
+		// truncated or foreign file, treat it as a miss
+		os.Remove(path)
+		return nil, false, nil
+	}
+
+	expiration := decodeExpiration(b)
+	now := time.Now()
+	if now.After(expiration) {
+		// another process may have renamed a fresh record into place
+		// since it was read, so only remove the one that expired
+		if exp, err := readExpiration(path); err == nil && exp.Equal(expiration) && os.Remove(path) == nil {
+			c.mutex.Lock()
+			c.size -= int64(len(b))
+			c.mutex.Unlock()
+		}
+		return nil, false, nil
+	}
+
+	// the modification time doubles as the last access time, which is
+	// what eviction uses to find the least recently used records
+	os.Chtimes(path, now, now)
+
+	return b[fileCacheHeaderSize:], true, nil
+}
+
+func (c *FileCache) Set(_ *starlark.Thread, key string, value []byte, ttl int64) error {
+	path := c.pathForKey(key)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating cache directory: %w", err)
+	}
+
+	expiration := time.Now().Add(time.Duration(ttl) * time.Second)
+	b := make([]byte, fileCacheHeaderSize+len(value))
+	binary.BigEndian.PutUint64(b, uint64(expiration.UnixNano()))
+	copy(b[fileCacheHeaderSize:], value)
+
+	// write to a temporary file first and rename it into place, so that
+	// concurrent readers never observe a partially written record
+	f, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("creating cache record: %w", err)
+	}
+
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return fmt.Errorf("writing cache record: %w", err)
+	}
+
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return fmt.Errorf("writing cache record: %w", err)
+	}
+
+	// a record that's replaced no longer counts towards the size
+	var replaced int64
+	if info, err := os.Stat(path); err == nil {
+		replaced = info.Size()
+	}
+
+	if err := os.Rename(f.Name(), path); err != nil {
+		os.Remove(f.Name())
+		return fmt.Errorf("storing cache record: %w", err)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.size += int64(len(b)) - replaced
+	if c.maxSize > 0 && c.size > c.maxSize {
+		if err := c.evict(); err != nil {
+			return fmt.Errorf("evicting cache records: %w", err)
+		}
+	}
+
+	return nil
+}
+
+type fileCacheRecord struct {
+	path       string
+	size       int64
+	lastAccess time.Time
+}
+
+// evict removes expired records, and then the least recently used records
+// until the cache is comfortably below its maximum size. The caller must
+// hold c.mutex.
+func (c *FileCache) evict() error {
+	records, err := c.records()
+	if err != nil {
+		return err
+	}
+
+	// expired records go first, however recently they were used. As
+	// below, another process may have evicted or replaced the record in
+	// the meantime, which is fine.
+	now := time.Now()
+	live := records[:0]
+	var total int64
+	for _, r := range records {
+		if exp, err := readExpiration(r.path); err == nil && now.After(exp) {
+			if err := os.Remove(r.path); err == nil || errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+		}
+		live = append(live, r)
+		total += r.size
+	}
+	records = live
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].lastAccess.Before(records[j].lastAccess)
+	})
+
+	target := int64(float64(c.maxSize) * fileCacheEvictionRatio)
+	for _, r := range records {
+		if total <= target {
+			break
+		}
+
+		// another process may have evicted or replaced the record in
+		// the meantime, which is fine
+		if err := os.Remove(r.path); err == nil || errors.Is(err, fs.ErrNotExist) {
+			total -= r.size
+		}
+	}
+
+	c.size = total
+	return nil
+}
+
+func (c *FileCache) records() ([]fileCacheRecord, error) {
+	var records []fileCacheRecord
+
+	err := filepath.WalkDir(c.dir, func(path string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() || filepath.Ext(path) != fileCacheSuffix {
+			return nil
+		}
+
+		info, err := d.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		records = append(records, fileCacheRecord{
+			path:       path,
+			size:       info.Size(),
+			lastAccess: info.ModTime(),
+		})
+
+		return nil
+	})
+
+	return records, err
+}
+
+// readExpiration reads the expiration timestamp of the record at path.
+func readExpiration(path string) (time.Time, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer f.Close()
+
+	b := make([]byte, fileCacheHeaderSize)
+	if _, err := io.ReadFull(f, b); err != nil {
+		return time.Time{}, err
+	}
+
+	return decodeExpiration(b), nil
+}
+
+func decodeExpiration(b []byte) time.Time {
+	return time.Unix(0, int64(binary.BigEndian.Uint64(b[:fileCacheHeaderSize])))
+}
+
+// pathForKey hashes the key to get a file name that's safe on every
+// platform, and shards records into subdirectories to keep directories
+// small.
+func (c *FileCache) pathForKey(key string) string {
+	h := sha256.Sum256([]byte(key))
+	name := hex.EncodeToString(h[:])
+	return filepath.Join(c.dir, name[:2], name+fileCacheSuffix)
+}
@@ -0,0 +1,175 @@
+package runtime
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileCacheGetAndSet(t *testing.T) {
+	c, err := NewFileCache(t.TempDir(), 0)
+	require.NoError(t, err)
+
+	_, found, err := c.Get(nil, "foo")
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	assert.NoError(t, c.Set(nil, "foo", []byte("bar"), 60))
+	assert.NoError(t, c.Set(nil, "pixlet:app/with:odd/chars", []byte("baz"), 60))
+
+	val, found, err := c.Get(nil, "foo")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, []byte("bar"), val)
+
+	val, found, err = c.Get(nil, "pixlet:app/with:odd/chars")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, []byte("baz"), val)
+
+	// overwrite
+	size := c.size
+	assert.NoError(t, c.Set(nil, "foo", []byte("qux"), 60))
+	val, found, err = c.Get(nil, "foo")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, []byte("qux"), val)
+	assert.Equal(t, size, c.size)
+}
+
+func TestFileCacheExpiration(t *testing.T) {
+	c, err := NewFileCache(t.TempDir(), 0)
+	require.NoError(t, err)
+
+	assert.NoError(t, c.Set(nil, "foo", []byte("bar"), -1))
+
+	_, found, err := c.Get(nil, "foo")
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	// expired records are removed from disk
+	_, err = os.Stat(c.pathForKey("foo"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestFileCachePersists(t *testing.T) {
+	dir := t.TempDir()
+
+	c, err := NewFileCache(dir, 0)
+	require.NoError(t, err)
+	assert.NoError(t, c.Set(nil, "foo", []byte("bar"), 60))
+
+	// a second cache in the same directory, e.g. from another pixlet
+	// process, sees the same records
+	c2, err := NewFileCache(dir, 0)
+	require.NoError(t, err)
+	assert.Equal(t, c.size, c2.size)
+
+	val, found, err := c2.Get(nil, "foo")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, []byte("bar"), val)
+}
+
+func TestFileCacheEviction(t *testing.T) {
+	value := make([]byte, 100-fileCacheHeaderSize)
+
+	// room for five records
+	c, err := NewFileCache(t.TempDir(), 500)
+	require.NoError(t, err)
+
+	for _, key := range []string{"a", "b", "c", "d", "e"} {
+		assert.NoError(t, c.Set(nil, key, value, 60))
+	}
+
+	// make access times deterministic, with "a" being the least
+	// recently used record and "b" the most recently used one
+	start := time.Now().Add(-time.Hour)
+	for i, key := range []string{"a", "c", "d", "e", "b"} {
+		ts := start.Add(time.Duration(i) * time.Minute)
+		assert.NoError(t, os.Chtimes(c.pathForKey(key), ts, ts))
+	}
+
+	// exceeds the maximum size, and trims the cache to 90% of it
+	assert.NoError(t, c.Set(nil, "f", value, 60))
+	assert.Equal(t, int64(400), c.size)
+
+	for key, expected := range map[string]bool{
+		"a": false,
+		"b": true,
+		"c": false,
+		"d": true,
+		"e": true,
+		"f": true,
+	} {
+		_, err := os.Stat(c.pathForKey(key))
+		assert.Equal(t, expected, err == nil, key)
+	}
+}
+
+func TestFileCacheEvictsExpiredFirst(t *testing.T) {
+	value := make([]byte, 100-fileCacheHeaderSize)
+
+	c, err := NewFileCache(t.TempDir(), 500)
+	require.NoError(t, err)
+
+	for _, key := range []string{"a", "b", "c", "d"} {
+		assert.NoError(t, c.Set(nil, key, value, 60))
+	}
+	assert.NoError(t, c.Set(nil, "e", value, -1))
+
+	// "e" has expired, but is the most recently used record
+	start := time.Now().Add(-time.Hour)
+	for i, key := range []string{"a", "b", "c", "d", "e"} {
+		ts := start.Add(time.Duration(i) * time.Minute)
+		assert.NoError(t, os.Chtimes(c.pathForKey(key), ts, ts))
+	}
+
+	assert.NoError(t, c.Set(nil, "f", value, 60))
+	assert.Equal(t, int64(400), c.size)
+
+	for key, expected := range map[string]bool{
+		"a": false,
+		"b": true,
+		"c": true,
+		"d": true,
+		"e": false,
+		"f": true,
+	} {
+		_, err := os.Stat(c.pathForKey(key))
+		assert.Equal(t, expected, err == nil, key)
+	}
+}
+
+func TestFileCacheWithApplet(t *testing.T) {
+	src := `
+load("render.star", "render")
+load("cache.star", "cache")
+
+def main():
+    i = int(cache.get("counter") or '1')
+    cache.set("counter", str(i + 1))
+    return [render.Root(child=render.Box()) for _ in range(i)]
+`
+	dir := t.TempDir()
+
+	for i := 1; i <= 3; i++ {
+		// a new cache for every run, similar to restarting pixlet
+		c, err := NewFileCache(dir, 0)
+		require.NoError(t, err)
+		InitCache(c)
+
+		app, err := NewApplet("test.star", []byte(src))
+		assert.NoError(t, err)
+
+		roots, err := app.Run(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, i, len(roots))
+	}
+
+	InitCache(nil)
+}
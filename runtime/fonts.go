@@ -0,0 +1,156 @@
+package runtime
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/image/font"
+
+	"tidbyt.dev/pixlet/manifest"
+	"tidbyt.dev/pixlet/render"
+)
+
+// appletFonts are the font files that ship with an applet. Text widgets can
+// refer to them by their path, or by a name registered in the manifest.
+// Files are found when the applet is loaded, but only parsed once they're
+// used.
+type appletFonts struct {
+	fsys  fs.FS
+	files map[string]fontFile
+	faces map[string]font.Face
+	mutex sync.Mutex
+}
+
+type fontFile struct {
+	path string
+	size float64
+}
+
+func findFonts(fsys fs.FS) (*appletFonts, error) {
+	fonts := &appletFonts{
+		fsys:  fsys,
+		files: make(map[string]fontFile),
+		faces: make(map[string]font.Face),
+	}
+
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// not every file system lets us list everything, and
+			// files we can't get to are of no use anyway
+			return nil
+		}
+
+		if d.IsDir() {
+			if p != "." && strings.HasPrefix(d.Name(), ".") {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		if !render.IsFontFile(p) {
+			return nil
+		}
+		if _, err := fs.Stat(fsys, p); err != nil {
+			return nil
+		}
+
+		fonts.files[p] = fontFile{path: p}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("finding fonts: %w", err)
+	}
+
+	data, err := fs.ReadFile(fsys, manifest.ManifestFileName)
+	if errors.Is(err, fs.ErrNotExist) {
+		return fonts, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("reading manifest: %w", err)
+	}
+
+	m, err := manifest.LoadManifest(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range m.Fonts {
+		p := path.Clean(f.Path)
+		if _, ok := fonts.files[p]; !ok {
+			return nil, fmt.Errorf("font '%s' in manifest: no font file at %s", f.Name, f.Path)
+		}
+
+		fonts.files[f.Name] = fontFile{path: p, size: f.Size}
+	}
+
+	return fonts, nil
+}
+
+// Paths returns the paths of all font files.
+func (f *appletFonts) Paths() []string {
+	paths := make([]string, 0, len(f.files))
+	for name, file := range f.files {
+		if name == file.path {
+			paths = append(paths, name)
+		}
+	}
+	return paths
+}
+
+// Names returns every name that fonts can be looked up by, which includes
+// their paths.
+func (f *appletFonts) Names() []string {
+	names := make([]string, 0, len(f.files))
+	for name := range f.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Font implements render.FontSource.
+func (f *appletFonts) Font(name string) (font.Face, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if face, ok := f.faces[name]; ok {
+		return face, nil
+	}
+
+	file, ok := f.files[name]
+	if !ok {
+		file, ok = f.files[path.Clean(name)]
+	}
+	if !ok {
+		return nil, nil
+	}
+
+	data, err := fs.ReadFile(f.fsys, file.path)
+	if err != nil {
+		return nil, fmt.Errorf("reading font '%s': %w", name, err)
+	}
+
+	face, err := render.ParseFont(file.path, data, file.size)
+	if err != nil {
+		return nil, fmt.Errorf("loading font '%s': %w", name, err)
+	}
+
+	f.faces[name] = face
+	return face, nil
+}
+
+// Fonts returns the fonts that ship with the applet.
+func (a *Applet) Fonts() render.FontSource {
+	return a.fonts
+}
+
+// FontNames returns the names of all fonts that ship with the applet: the
+// paths of font files, and the names registered for them in the manifest.
+func (a *Applet) FontNames() []string {
+	return a.fonts.Names()
+}
@@ -0,0 +1,112 @@
+package runtime
+
+import (
+	"context"
+	"os"
+	"sort"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+func TestAppletFonts(t *testing.T) {
+	tomThumb, err := os.ReadFile("../fonts/tom-thumb.bdf")
+	require.NoError(t, err)
+
+	src := `
+load("render.star", "render")
+
+def assert(success, message=None):
+    if not success:
+        fail(message or "assertion failed")
+
+TINY = render.Text("Hi", font = "tom-thumb").size()
+
+# fonts are available at load time
+assert(render.Text("Hi", font = "fonts/tiny.bdf").size() == TINY)
+
+def main(config):
+    assert(render.Text("Hi", font = "tiny").size() == TINY)
+    assert(render.Text("Hi", font = "./fonts/tiny.bdf").size() == TINY)
+    render.WrappedText("Hi", font = "tiny")
+
+    t = render.Text("Hi Ω", font = "tiny", fallback = ["tb-8", "fonts/go.ttf"])
+    assert(t.fallback == ["tb-8", "fonts/go.ttf"])
+    assert(t.size()[1] > TINY[1])
+
+    small = render.Text("M", font = "fonts/go.ttf").size()
+    large = render.Text("M", font = "go-large").size()
+    assert(large[0] > small[0])
+
+    return render.Root(child = render.Text("Hi", font = "tiny"))
+`
+
+	vfs := fstest.MapFS{
+		"main.star":      {Data: []byte(src)},
+		"fonts/tiny.bdf": {Data: tomThumb},
+		"fonts/go.ttf":   {Data: goregular.TTF},
+		"manifest.yaml": {Data: []byte(`---
+id: fonts
+name: Fonts
+fonts:
+  - name: tiny
+    path: fonts/tiny.bdf
+  - name: go-large
+    path: fonts/go.ttf
+    size: 16
+`)},
+	}
+
+	app, err := NewAppletFromFS("fonts", vfs)
+	require.NoError(t, err)
+
+	roots, err := app.Run(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, roots, 1)
+
+	paths := app.PathsForBundle()
+	sort.Strings(paths)
+	assert.Equal(t, []string{"fonts/go.ttf", "fonts/tiny.bdf", "main.star"}, paths)
+}
+
+func TestAppletFontsErrors(t *testing.T) {
+	src := `
+load("render.star", "render")
+
+def main(config):
+    return render.Root(child = render.Text("Hi", font = "fonts/broken.bdf"))
+`
+
+	// manifest fonts must exist
+	_, err := NewAppletFromFS("fonts", fstest.MapFS{
+		"main.star": {Data: []byte(src)},
+		"manifest.yaml": {Data: []byte(`---
+fonts:
+  - name: missing
+    path: fonts/missing.bdf
+`)},
+	})
+	assert.ErrorContains(t, err, "missing")
+
+	// broken fonts fail when they're used
+	app, err := NewAppletFromFS("fonts", fstest.MapFS{
+		"main.star":        {Data: []byte(src)},
+		"fonts/broken.bdf": {Data: []byte("STARTFONT 2.1\nCHARS x\n")},
+	})
+	require.NoError(t, err)
+
+	_, err = app.Run(context.Background())
+	assert.ErrorContains(t, err, "fonts/broken.bdf")
+
+	// other apps' fonts aren't available
+	app, err = NewAppletFromFS("fonts", fstest.MapFS{
+		"main.star": {Data: []byte(src)},
+	})
+	require.NoError(t, err)
+
+	_, err = app.Run(context.Background())
+	assert.ErrorContains(t, err, "unknown font")
+}
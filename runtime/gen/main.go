@@ -13,6 +13,7 @@ import (
 	"go/format"
 	"go/parser"
 	"go/token"
+	"image"
 	"image/color"
 	"os"
 	"reflect"
@@ -68,17 +69,31 @@ var Packages = []Package{
 		GoWidgetName:   "Widget",
 		Types: []reflect.Value{
 			reflect.ValueOf(new(render.Animation)),
+			reflect.ValueOf(new(render.Arc)),
+			reflect.ValueOf(new(render.BarChart)),
+			reflect.ValueOf(new(render.Bezier)),
 			reflect.ValueOf(new(render.Box)),
+			reflect.ValueOf(new(render.Canvas)),
 			reflect.ValueOf(new(render.Circle)),
 			reflect.ValueOf(new(render.Column)),
+			reflect.ValueOf(new(render.Gauge)),
 			reflect.ValueOf(new(render.Image)),
+			reflect.ValueOf(new(render.Label)),
+			reflect.ValueOf(new(render.Line)),
 			reflect.ValueOf(new(render.Marquee)),
 			reflect.ValueOf(new(render.Padding)),
 			reflect.ValueOf(new(render.PieChart)),
+			reflect.ValueOf(new(render.Pixel)),
 			reflect.ValueOf(new(render.Plot)),
+			reflect.ValueOf(new(render.Polygon)),
+			reflect.ValueOf(new(render.Rect)),
+			reflect.ValueOf(new(render.RichText)),
 			reflect.ValueOf(new(render.Root)),
 			reflect.ValueOf(new(render.Row)),
 			reflect.ValueOf(new(render.Sequence)),
+			reflect.ValueOf(new(render.Series)),
+			reflect.ValueOf(new(render.Span)),
+			reflect.ValueOf(new(render.Sparkline)),
 			reflect.ValueOf(new(render.Stack)),
 			reflect.ValueOf(new(render.Text)),
 			reflect.ValueOf(new(render.WrappedText)),
@@ -145,6 +160,12 @@ var TypeMap = map[reflect.Type]Type{
 		DocType:      "bool",
 		TemplatePath: "./runtime/gen/attr/bool.tmpl",
 	},
+	toDecayedType(new([]string)): {
+		GoType:        "*starlark.List",
+		DocType:       "[str]",
+		TemplatePath:  "./runtime/gen/attr/strings.tmpl",
+		GenerateField: true,
+	},
 
 	// Render types
 	toDecayedType(new(render.Insets)): {
@@ -169,6 +190,20 @@ var TypeMap = map[reflect.Type]Type{
 		GenerateField: true,
 	},
 
+	toDecayedType(new([]render.Span)): {
+		GoType:        "*starlark.List",
+		DocType:       "[Span / str]",
+		TemplatePath:  "./runtime/gen/attr/spans.tmpl",
+		GenerateField: true,
+	},
+
+	// Render `Image` types
+	toDecayedType(new(image.Rectangle)): {
+		GoType:       "starlark.Tuple",
+		DocType:      "(int, int, int, int)",
+		TemplatePath: "./runtime/gen/attr/rect.tmpl",
+	},
+
 	// Render `PieChart types`
 	toDecayedType(new([]color.Color)): {
 		GoType:        "*starlark.List",
@@ -194,6 +229,28 @@ var TypeMap = map[reflect.Type]Type{
 		DocType:      "[(float, float)]",
 		TemplatePath: "./runtime/gen/attr/dataseries.tmpl",
 	},
+	toDecayedType(new([]render.Series)): {
+		GoType:        "*starlark.List",
+		DocType:       "[Series]",
+		TemplatePath:  "./runtime/gen/attr/series.tmpl",
+		GenerateField: true,
+	},
+
+	// Render `Canvas` types
+	toDecayedType(new([]render.CanvasOp)): {
+		GoType:        "*starlark.List",
+		DocType:       "[Pixel / Line / Rect / Polygon / Arc / Bezier / Label]",
+		TemplatePath:  "./runtime/gen/attr/ops.tmpl",
+		GenerateField: true,
+	},
+
+	// Render `BarChart` types
+	toDecayedType(new([][]float64)): {
+		GoType:        "*starlark.List",
+		DocType:       "[float / [float]]",
+		TemplatePath:  "./runtime/gen/attr/bars.tmpl",
+		GenerateField: true,
+	},
 
 	// Animation types
 	toDecayedType(new(animation.Origin)): {
@@ -251,6 +308,7 @@ type GeneratedAttr struct {
 	GenerateField bool
 	IsRequired    bool
 	IsReadOnly    bool
+	IsFloat       bool
 
 	// Template and generated code for handling this attribute.
 	Template *template.Template
@@ -271,6 +329,7 @@ type GeneratedType struct {
 	Attributes        []*GeneratedAttr
 	HasSize           bool
 	HasInit           bool
+	HasFonts          bool
 	Documentation     string
 	Examples          []string
 }
@@ -361,6 +420,10 @@ func toGeneratedType(pkg Package, val reflect.Value) (*GeneratedType, error) {
 		result.HasInit = true
 	}
 
+	if typ.ConvertibleTo(toDecayedType(new(render.WidgetWithFonts))) {
+		result.HasFonts = true
+	}
+
 	// Unwrap any pointer types.
 	val = reflect.Indirect(val)
 	typ = val.Type()
@@ -387,6 +450,7 @@ func toGeneratedType(pkg Package, val reflect.Value) (*GeneratedType, error) {
 				attr.DocType = t.DocType
 				attr.Template = loadTemplate("attr", t.TemplatePath)
 				attr.GenerateField = t.GenerateField
+				attr.IsFloat = field.Type.Kind() == reflect.Float64
 			} else {
 				return nil, fmt.Errorf("%s.%s has unsupported type", typ.Name(), field.Name)
 			}
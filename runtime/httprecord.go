@@ -0,0 +1,266 @@
+package runtime
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"tidbyt.dev/pixlet/runtime/modules/starlarkhttp"
+)
+
+const (
+	fixtureRequestSuffix  = ".request"
+	fixtureResponseSuffix = ".response"
+)
+
+// ErrNoFixture is returned by a replaying transport when a request has no
+// recorded response.
+var ErrNoFixture = errors.New("no recorded response")
+
+// RecordingTransport passes requests through to another transport, and
+// writes every request and response pair to a fixture directory. The
+// fixtures can later be served by a ReplayingTransport.
+type RecordingTransport struct {
+	dir       string
+	transport http.RoundTripper
+
+	// recorded keeps track of fixtures written by this transport, so that
+	// only the first response to identical requests is kept.
+	recorded map[string]bool
+	mutex    sync.Mutex
+}
+
+// NewRecordingTransport creates a RecordingTransport that writes fixtures to
+// dir, creating the directory if needed.
+func NewRecordingTransport(dir string, transport http.RoundTripper) (*RecordingTransport, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("creating fixture directory %s: %w", dir, err)
+	}
+
+	return &RecordingTransport{
+		dir:       dir,
+		transport: transport,
+		recorded:  map[string]bool{},
+	}, nil
+}
+
+func (t *RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	name, err := fixtureName(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	redacted := redactRequest(req, body)
+
+	reqDump, err := httputil.DumpRequestOut(redacted, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize request for fixture: %w", err)
+	}
+
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respDump, err := httputil.DumpResponse(resp, true)
+	if err != nil {
+		// if httputil.DumpResponse fails, it leaves the response body in an
+		// undefined state, so we cannot continue
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to serialize response for fixture: %s", resp.Status)
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.recorded[name] {
+		return resp, nil
+	}
+	t.recorded[name] = true
+
+	base := filepath.Join(t.dir, name)
+	if err := os.WriteFile(base+fixtureRequestSuffix, reqDump, 0644); err != nil {
+		return nil, fmt.Errorf("writing fixture: %w", err)
+	}
+	if err := os.WriteFile(base+fixtureResponseSuffix, respDump, 0644); err != nil {
+		return nil, fmt.Errorf("writing fixture: %w", err)
+	}
+
+	return resp, nil
+}
+
+// ReplayingTransport serves responses from a fixture directory written by a
+// RecordingTransport. It never makes network requests, and fails any
+// request that has no recorded response.
+type ReplayingTransport struct {
+	dir string
+}
+
+// NewReplayingTransport creates a ReplayingTransport that reads fixtures
+// from dir.
+func NewReplayingTransport(dir string) (*ReplayingTransport, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading fixture directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("fixture path %s is not a directory", dir)
+	}
+
+	return &ReplayingTransport{dir: dir}, nil
+}
+
+func (t *ReplayingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	name, err := fixtureName(req)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := os.ReadFile(filepath.Join(t.dir, name+fixtureResponseSuffix))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w for %s %s", ErrNoFixture, req.Method, req.URL)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading fixture: %w", err)
+	}
+
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(b)), req)
+	if err != nil {
+		return nil, fmt.Errorf("parsing fixture %s: %w", name, err)
+	}
+
+	return resp, nil
+}
+
+// InitHTTPRecording is like InitHTTP, but additionally records every request
+// made by applets to fixtures in dir.
+func InitHTTPRecording(cache Cache, dir string) error {
+	InitHTTP(cache)
+
+	client := starlarkhttp.StarlarkHTTPClient
+	recorder, err := NewRecordingTransport(dir, client.Transport)
+	if err != nil {
+		return err
+	}
+
+	starlarkhttp.StarlarkHTTPClient = &http.Client{
+		Transport: recorder,
+		Timeout:   client.Timeout,
+	}
+	return nil
+}
+
+// InitHTTPReplay configures applets to receive HTTP responses only from the
+// fixtures in dir, which were previously written by InitHTTPRecording.
+// Requests that weren't recorded fail with ErrNoFixture.
+func InitHTTPReplay(dir string) error {
+	replayer, err := NewReplayingTransport(dir)
+	if err != nil {
+		return err
+	}
+
+	starlarkhttp.StarlarkHTTPClient = &http.Client{
+		Transport: replayer,
+		Timeout:   HTTPTimeout * 2,
+	}
+	return nil
+}
+
+var unsafeFixtureChars = regexp.MustCompile(`[^a-zA-Z0-9.-]+`)
+
+// fixtureName identifies a request by its method, URL and body. Headers are
+// deliberately left out, since they often carry credentials or other values
+// that change between runs.
+func fixtureName(req *http.Request) (string, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%s\n", req.Method, req.URL.String())
+	h.Write(body)
+
+	host := unsafeFixtureChars.ReplaceAllString(req.URL.Host, "_")
+	return fmt.Sprintf(
+		"%s-%s-%s",
+		strings.ToLower(req.Method),
+		host,
+		hex.EncodeToString(h.Sum(nil))[:16],
+	), nil
+}
+
+// recordedHeaders are the request headers written to fixtures as they are.
+// Any other header might carry credentials, so only its name is kept.
+var recordedHeaders = map[string]bool{
+	"Accept":          true,
+	"Accept-Encoding": true,
+	"Accept-Language": true,
+	"Cache-Control":   true,
+	"Content-Length":  true,
+	"Content-Type":    true,
+	"User-Agent":      true,
+}
+
+const redactedValue = "REDACTED"
+
+// redactRequest returns a copy of req with the given body, and with query
+// parameter values and unknown header values replaced. Credentials don't
+// belong in fixtures, which are likely to be committed alongside the app.
+func redactRequest(req *http.Request, body []byte) *http.Request {
+	redacted := req.Clone(req.Context())
+	redacted.Body = io.NopCloser(bytes.NewReader(body))
+
+	for name, values := range redacted.Header {
+		if recordedHeaders[name] {
+			continue
+		}
+		for i := range values {
+			values[i] = redactedValue
+		}
+	}
+
+	query := redacted.URL.Query()
+	for _, values := range query {
+		for i := range values {
+			values[i] = redactedValue
+		}
+	}
+	redacted.URL.RawQuery = query.Encode()
+	redacted.URL.User = nil
+
+	return redacted
+}
+
+// readBody returns the request body, and replaces it so that the request can
+// still be sent.
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("reading request body: %w", err)
+	}
+
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
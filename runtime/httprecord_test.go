@@ -0,0 +1,100 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.starlark.net/starlark"
+)
+
+func TestHTTPRecordAndReplay(t *testing.T) {
+	hits := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		body, _ := io.ReadAll(r.Body)
+		fmt.Fprintf(w, "%s %s %s %d", r.Method, r.URL.Path, body, hits)
+	}))
+	defer ts.Close()
+
+	src := fmt.Sprintf(`
+load("http.star", "http")
+load("render.star", "render")
+
+def main():
+    a = http.get(
+        "%[1]s/a",
+        params = {"api_key": "s3cr3t"},
+        headers = {"Authorization": "Bearer s3cr3t", "X-Api-Key": "s3cr3t"},
+    ).body()
+    b = http.post("%[1]s/b", body = "hello").body()
+    print(a + ", " + b)
+    return render.Root(child = render.Text(a + ", " + b))
+`, ts.URL)
+
+	var output []string
+	printFunc := WithPrintFunc(func(thread *starlark.Thread, msg string) {
+		output = append(output, msg)
+	})
+
+	dir := filepath.Join(t.TempDir(), "fixtures")
+
+	// record against the live server
+	require.NoError(t, InitHTTPRecording(NewInMemoryCache(), dir))
+	app, err := NewApplet("record.star", []byte(src), printFunc)
+	require.NoError(t, err)
+	_, err = app.Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, hits)
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Equal(t, 4, len(files))
+
+	// credentials are not written to fixtures
+	for _, f := range files {
+		b, err := os.ReadFile(filepath.Join(dir, f.Name()))
+		require.NoError(t, err)
+		assert.False(t, strings.Contains(string(b), "s3cr3t"), f.Name())
+		if strings.HasPrefix(f.Name(), "get-") && strings.HasSuffix(f.Name(), fixtureRequestSuffix) {
+			assert.Contains(t, string(b), "/a?api_key=REDACTED")
+			assert.Contains(t, string(b), "X-Api-Key: REDACTED")
+		}
+	}
+
+	// replay never reaches the server
+	require.NoError(t, InitHTTPReplay(dir))
+	app, err = NewApplet("replay.star", []byte(src), printFunc)
+	require.NoError(t, err)
+	_, err = app.Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, hits)
+
+	assert.Equal(t, []string{
+		"GET /a  1, POST /b hello 2",
+		"GET /a  1, POST /b hello 2",
+	}, output)
+
+	// a request body that wasn't recorded
+	src = strings.Replace(src, `"hello"`, `"goodbye"`, 1)
+	app, err = NewApplet("replay.star", []byte(src), printFunc)
+	require.NoError(t, err)
+	_, err = app.Run(context.Background())
+	assert.ErrorContains(t, err, "no recorded response for POST")
+	assert.Equal(t, 2, hits)
+
+	InitHTTP(NewInMemoryCache())
+}
+
+func TestHTTPReplayMissingDirectory(t *testing.T) {
+	err := InitHTTPReplay(filepath.Join(t.TempDir(), "nope"))
+	assert.Error(t, err)
+}
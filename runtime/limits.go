@@ -0,0 +1,177 @@
+package runtime
+
+import (
+	"fmt"
+	"image"
+	"sync"
+
+	"go.starlark.net/starlark"
+
+	"tidbyt.dev/pixlet/globals"
+	"tidbyt.dev/pixlet/render"
+	"tidbyt.dev/pixlet/runtime/modules/starlarkhttp"
+)
+
+// Limit identifies a resource whose use by an applet can be capped.
+type Limit string
+
+const (
+	LimitExecutionSteps Limit = "execution steps"
+	LimitHTTPRequests   Limit = "HTTP requests"
+	LimitHTTPBytes      Limit = "HTTP response bytes"
+	LimitFrames         Limit = "frames"
+	LimitPixels         Limit = "pixels"
+)
+
+// LimitError is returned when an applet uses more of a resource than it's
+// allowed to. Use errors.As to tell it apart from other errors.
+type LimitError struct {
+	Limit Limit
+	Max   int64
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("exceeded limit of %d %s", e.Max, e.Limit)
+}
+
+// limits holds the resource limits of an applet. Zero means unlimited.
+type limits struct {
+	maxSteps        uint64
+	maxHTTPRequests int
+	maxHTTPBytes    int64
+	maxFrames       int
+	maxPixels       int64
+}
+
+// WithMaxExecutionSteps limits the number of Starlark computation steps a
+// single call into the applet may take, which catches runaway loops sooner
+// and more predictably than a timeout.
+func WithMaxExecutionSteps(max uint64) AppletOption {
+	return func(a *Applet) error {
+		a.limits.maxSteps = max
+		return nil
+	}
+}
+
+// WithMaxHTTPRequests limits the number of HTTP requests a single call into
+// the applet may make.
+func WithMaxHTTPRequests(max int) AppletOption {
+	return func(a *Applet) error {
+		a.limits.maxHTTPRequests = max
+		return nil
+	}
+}
+
+// WithMaxHTTPBytes limits the total size of the HTTP response bodies a
+// single call into the applet may read.
+func WithMaxHTTPBytes(max int64) AppletOption {
+	return func(a *Applet) error {
+		a.limits.maxHTTPBytes = max
+		return nil
+	}
+}
+
+// WithMaxFrames limits the total number of frames in the roots returned by
+// the applet's main function.
+func WithMaxFrames(max int) AppletOption {
+	return func(a *Applet) error {
+		a.limits.maxFrames = max
+		return nil
+	}
+}
+
+// WithMaxPixels limits the total pixel area of the roots returned by the
+// applet's main function, which is the area of each root's widget tree
+// multiplied by its number of frames. Widget trees that are larger than the
+// display count with their full size, even though they're cropped when
+// painted.
+func WithMaxPixels(max int64) AppletOption {
+	return func(a *Applet) error {
+		a.limits.maxPixels = max
+		return nil
+	}
+}
+
+// attachLimits applies the applet's limits to a new thread.
+func (a *Applet) attachLimits(t *starlark.Thread) {
+	if a.limits.maxSteps > 0 {
+		t.SetMaxExecutionSteps(a.limits.maxSteps)
+	}
+
+	if a.limits.maxHTTPRequests > 0 || a.limits.maxHTTPBytes > 0 {
+		starlarkhttp.AttachBudgetToThread(t, &httpBudget{
+			maxRequests: a.limits.maxHTTPRequests,
+			maxBytes:    a.limits.maxHTTPBytes,
+		})
+	}
+}
+
+// stepsExceeded returns a LimitError if the thread was cancelled for
+// taking too many steps.
+func (a *Applet) stepsExceeded(t *starlark.Thread) *LimitError {
+	if a.limits.maxSteps > 0 && t.ExecutionSteps() >= a.limits.maxSteps {
+		return &LimitError{Limit: LimitExecutionSteps, Max: int64(a.limits.maxSteps)}
+	}
+	return nil
+}
+
+// checkRoots returns a LimitError if the roots have more frames or pixels
+// than allowed.
+func (a *Applet) checkRoots(roots []render.Root) error {
+	if a.limits.maxFrames <= 0 && a.limits.maxPixels <= 0 {
+		return nil
+	}
+
+	display := image.Rect(0, 0, globals.Width, globals.Height)
+
+	frames, pixels := 0, int64(0)
+	for _, r := range roots {
+		n := r.Child.FrameCount()
+		frames += n
+
+		size := r.Child.PaintBounds(display, 0).Union(display)
+		pixels += int64(n) * int64(size.Dx()) * int64(size.Dy())
+	}
+
+	if a.limits.maxFrames > 0 && frames > a.limits.maxFrames {
+		return &LimitError{Limit: LimitFrames, Max: int64(a.limits.maxFrames)}
+	}
+
+	if a.limits.maxPixels > 0 && pixels > a.limits.maxPixels {
+		return &LimitError{Limit: LimitPixels, Max: a.limits.maxPixels}
+	}
+
+	return nil
+}
+
+// httpBudget counts the HTTP requests made on a single thread.
+type httpBudget struct {
+	maxRequests int
+	maxBytes    int64
+
+	requests int
+	bytes    int64
+	mutex    sync.Mutex
+}
+
+func (b *httpBudget) Request() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.requests++
+	if b.maxRequests > 0 && b.requests > b.maxRequests {
+		return &LimitError{Limit: LimitHTTPRequests, Max: int64(b.maxRequests)}
+	}
+	return nil
+}
+
+func (b *httpBudget) Read(n int) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.bytes += int64(n)
+	if b.maxBytes > 0 && b.bytes > b.maxBytes {
+		return &LimitError{Limit: LimitHTTPBytes, Max: b.maxBytes}
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func requireLimitError(t *testing.T, err error, limit Limit) {
+	var limitErr *LimitError
+	require.True(t, errors.As(err, &limitErr), "expected LimitError, got: %v", err)
+	assert.Equal(t, limit, limitErr.Limit)
+}
+
+func TestMaxExecutionSteps(t *testing.T) {
+	src := `
+load("render.star", "render")
+
+def main(config):
+    n = int(config.get("n"))
+    for i in range(n):
+        pass
+    return render.Root(child = render.Box())
+`
+	app, err := NewApplet("steps", []byte(src), WithMaxExecutionSteps(10000))
+	require.NoError(t, err)
+
+	_, err = app.RunWithConfig(context.Background(), map[string]string{"n": "10"})
+	assert.NoError(t, err)
+
+	_, err = app.RunWithConfig(context.Background(), map[string]string{"n": "1000000"})
+	requireLimitError(t, err, LimitExecutionSteps)
+	assert.ErrorContains(t, err, "exceeded limit of 10000 execution steps")
+
+	// steps are counted per call, not per applet
+	_, err = app.RunWithConfig(context.Background(), map[string]string{"n": "10"})
+	assert.NoError(t, err)
+
+	// top level code is limited too
+	_, err = NewApplet("steps", []byte(`
+def main():
+    pass
+
+x = [i for i in range(1000000)]
+`), WithMaxExecutionSteps(10000))
+	requireLimitError(t, err, LimitExecutionSteps)
+}
+
+func TestMaxHTTP(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, strings.Repeat("x", 1000))
+	}))
+	defer ts.Close()
+
+	src := fmt.Sprintf(`
+load("http.star", "http")
+load("render.star", "render")
+
+def main(config):
+    for i in range(int(config.get("n"))):
+        http.get("%s").body()
+    return render.Root(child = render.Box())
+`, ts.URL)
+
+	app, err := NewApplet("fetcher", []byte(src), WithMaxHTTPRequests(3))
+	require.NoError(t, err)
+
+	_, err = app.RunWithConfig(context.Background(), map[string]string{"n": "3"})
+	assert.NoError(t, err)
+
+	_, err = app.RunWithConfig(context.Background(), map[string]string{"n": "4"})
+	requireLimitError(t, err, LimitHTTPRequests)
+
+	app, err = NewApplet("fetcher", []byte(src), WithMaxHTTPBytes(2500))
+	require.NoError(t, err)
+
+	_, err = app.RunWithConfig(context.Background(), map[string]string{"n": "2"})
+	assert.NoError(t, err)
+
+	_, err = app.RunWithConfig(context.Background(), map[string]string{"n": "3"})
+	requireLimitError(t, err, LimitHTTPBytes)
+}
+
+func TestMaxFramesAndPixels(t *testing.T) {
+	src := `
+load("render.star", "render")
+
+def main(config):
+    n = int(config.get("frames"))
+    size = int(config.get("size"))
+    return render.Root(
+        child = render.Animation(
+            children = [render.Box(width = size, height = size) for i in range(n)],
+        ),
+    )
+`
+
+	app, err := NewApplet("frames", []byte(src), WithMaxFrames(10))
+	require.NoError(t, err)
+
+	_, err = app.RunWithConfig(context.Background(), map[string]string{"frames": "10", "size": "1"})
+	assert.NoError(t, err)
+
+	_, err = app.RunWithConfig(context.Background(), map[string]string{"frames": "11", "size": "1"})
+	requireLimitError(t, err, LimitFrames)
+
+	// 10 frames of the 64x32 display
+	app, err = NewApplet("pixels", []byte(src), WithMaxPixels(10*64*32))
+	require.NoError(t, err)
+
+	_, err = app.RunWithConfig(context.Background(), map[string]string{"frames": "10", "size": "1"})
+	assert.NoError(t, err)
+
+	_, err = app.RunWithConfig(context.Background(), map[string]string{"frames": "11", "size": "1"})
+	requireLimitError(t, err, LimitPixels)
+
+	// oversized widgets count with their full size
+	_, err = app.RunWithConfig(context.Background(), map[string]string{"frames": "1", "size": "1000"})
+	requireLimitError(t, err, LimitPixels)
+}
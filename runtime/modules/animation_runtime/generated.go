@@ -301,6 +301,9 @@ func (w *Keyframe) Attr(name string) (starlark.Value, error) {
 
 	case "transforms":
 
+		if w.starlarkTransforms == nil {
+			return starlark.None, nil
+		}
 		return w.starlarkTransforms, nil
 
 	case "curve":
@@ -429,11 +432,13 @@ func newRotate(
 
 	w := &Rotate{}
 
-	w.starlarkAngle = angle
-	if val, ok := starlark.AsFloat(w.starlarkAngle); ok {
-		w.Angle = val
-	} else {
-		return nil, fmt.Errorf("expected number, but got: %s", w.starlarkAngle.String())
+	if angle != nil {
+		w.starlarkAngle = angle
+		if val, ok := starlark.AsFloat(w.starlarkAngle); ok {
+			w.Angle = val
+		} else {
+			return nil, fmt.Errorf("expected number, but got: %s", w.starlarkAngle.String())
+		}
 	}
 
 	return w, nil
@@ -450,6 +455,9 @@ func (w *Rotate) Attr(name string) (starlark.Value, error) {
 
 	case "angle":
 
+		if w.starlarkAngle == nil {
+			return starlark.Float(w.Angle), nil
+		}
 		return w.starlarkAngle, nil
 
 	default:
@@ -498,18 +506,22 @@ func newScale(
 
 	w := &Scale{}
 
-	w.starlarkX = x
-	if val, ok := starlark.AsFloat(w.starlarkX); ok {
-		w.X = val
-	} else {
-		return nil, fmt.Errorf("expected number, but got: %s", w.starlarkX.String())
+	if x != nil {
+		w.starlarkX = x
+		if val, ok := starlark.AsFloat(w.starlarkX); ok {
+			w.X = val
+		} else {
+			return nil, fmt.Errorf("expected number, but got: %s", w.starlarkX.String())
+		}
 	}
 
-	w.starlarkY = y
-	if val, ok := starlark.AsFloat(w.starlarkY); ok {
-		w.Y = val
-	} else {
-		return nil, fmt.Errorf("expected number, but got: %s", w.starlarkY.String())
+	if y != nil {
+		w.starlarkY = y
+		if val, ok := starlark.AsFloat(w.starlarkY); ok {
+			w.Y = val
+		} else {
+			return nil, fmt.Errorf("expected number, but got: %s", w.starlarkY.String())
+		}
 	}
 
 	return w, nil
@@ -526,10 +538,16 @@ func (w *Scale) Attr(name string) (starlark.Value, error) {
 
 	case "x":
 
+		if w.starlarkX == nil {
+			return starlark.Float(w.X), nil
+		}
 		return w.starlarkX, nil
 
 	case "y":
 
+		if w.starlarkY == nil {
+			return starlark.Float(w.Y), nil
+		}
 		return w.starlarkY, nil
 
 	default:
@@ -720,6 +738,9 @@ func (w *Transformation) Attr(name string) (starlark.Value, error) {
 
 	case "keyframes":
 
+		if w.starlarkKeyframes == nil {
+			return starlark.None, nil
+		}
 		return w.starlarkKeyframes, nil
 
 	case "duration":
@@ -819,18 +840,22 @@ func newTranslate(
 
 	w := &Translate{}
 
-	w.starlarkX = x
-	if val, ok := starlark.AsFloat(w.starlarkX); ok {
-		w.X = val
-	} else {
-		return nil, fmt.Errorf("expected number, but got: %s", w.starlarkX.String())
+	if x != nil {
+		w.starlarkX = x
+		if val, ok := starlark.AsFloat(w.starlarkX); ok {
+			w.X = val
+		} else {
+			return nil, fmt.Errorf("expected number, but got: %s", w.starlarkX.String())
+		}
 	}
 
-	w.starlarkY = y
-	if val, ok := starlark.AsFloat(w.starlarkY); ok {
-		w.Y = val
-	} else {
-		return nil, fmt.Errorf("expected number, but got: %s", w.starlarkY.String())
+	if y != nil {
+		w.starlarkY = y
+		if val, ok := starlark.AsFloat(w.starlarkY); ok {
+			w.Y = val
+		} else {
+			return nil, fmt.Errorf("expected number, but got: %s", w.starlarkY.String())
+		}
 	}
 
 	return w, nil
@@ -847,10 +872,16 @@ func (w *Translate) Attr(name string) (starlark.Value, error) {
 
 	case "x":
 
+		if w.starlarkX == nil {
+			return starlark.Float(w.X), nil
+		}
 		return w.starlarkX, nil
 
 	case "y":
 
+		if w.starlarkY == nil {
+			return starlark.Float(w.Y), nil
+		}
 		return w.starlarkY, nil
 
 	default:
@@ -0,0 +1,19 @@
+package render_runtime
+
+import (
+	"tidbyt.dev/pixlet/render"
+)
+
+// canvasOp is implemented by the Starlark types of drawing ops that can
+// be passed to Canvas.
+type canvasOp interface {
+	AsCanvasOp() render.CanvasOp
+}
+
+func (p *Pixel) AsCanvasOp() render.CanvasOp   { return &p.Pixel }
+func (l *Line) AsCanvasOp() render.CanvasOp    { return &l.Line }
+func (r *Rect) AsCanvasOp() render.CanvasOp    { return &r.Rect }
+func (p *Polygon) AsCanvasOp() render.CanvasOp { return &p.Polygon }
+func (a *Arc) AsCanvasOp() render.CanvasOp     { return &a.Arc }
+func (b *Bezier) AsCanvasOp() render.CanvasOp  { return &b.Bezier }
+func (l *Label) AsCanvasOp() render.CanvasOp   { return &l.Label }
@@ -2,6 +2,7 @@ package render_runtime
 
 import (
 	"fmt"
+	"image"
 	"image/color"
 	"math"
 
@@ -43,6 +44,30 @@ func DataPointFromStarlark(value starlark.Value) ([2]float64, error) {
 	return result, nil
 }
 
+func RectFromStarlark(value starlark.Value) (image.Rectangle, error) {
+	tuple, isTuple := value.(starlark.Tuple)
+	if !isTuple || (tuple.Len() != 0 && tuple.Len() != 4) {
+		return image.Rectangle{}, fmt.Errorf("invalid type for rectangle: %s (expected a 4-tuple of x, y, width and height)", value.Type())
+	} else if tuple.Len() == 0 {
+		return image.Rectangle{}, nil
+	}
+
+	var elems [4]int
+	for i := 0; i < len(elems); i++ {
+		val, err := starlark.AsInt32(tuple.Index(i))
+		if err != nil {
+			return image.Rectangle{}, fmt.Errorf("rectangle element %d is not int", i)
+		}
+		elems[i] = val
+	}
+
+	if elems[2] < 0 || elems[3] < 0 {
+		return image.Rectangle{}, fmt.Errorf("rectangle width and height must not be negative")
+	}
+
+	return image.Rect(elems[0], elems[1], elems[0]+elems[2], elems[1]+elems[3]), nil
+}
+
 func DataSeriesFromStarlark(list *starlark.List) ([][2]float64, error) {
 	result := make([][2]float64, 0)
 
@@ -71,6 +96,49 @@ func WeightsFromStarlark(list *starlark.List) ([]float64, error) {
 	return result, nil
 }
 
+func BarSeriesFromStarlark(list *starlark.List) ([][]float64, error) {
+	result := make([][]float64, 0)
+
+	for i := 0; i < list.Len(); i++ {
+		switch v := list.Index(i).(type) {
+		case starlark.Tuple, *starlark.List:
+			values := v.(starlark.Indexable)
+			group := make([]float64, 0, values.Len())
+			for j := 0; j < values.Len(); j++ {
+				if val, err := DataPointElementFromStarlark(values.Index(j)); err == nil {
+					group = append(group, val)
+				} else {
+					return nil, err
+				}
+			}
+			result = append(result, group)
+
+		default:
+			if val, err := DataPointElementFromStarlark(v); err == nil {
+				result = append(result, []float64{val})
+			} else {
+				return nil, err
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func StringsFromStarlark(list *starlark.List) ([]string, error) {
+	result := make([]string, 0)
+
+	for i := 0; i < list.Len(); i++ {
+		if val, ok := starlark.AsString(list.Index(i)); ok {
+			result = append(result, val)
+		} else {
+			return nil, fmt.Errorf("invalid type for element %d: %s (expected string)", i, list.Index(i).Type())
+		}
+	}
+
+	return result, nil
+}
+
 func ColorSeriesFromStarlark(list *starlark.List) ([]color.Color, error) {
 	result := make([]color.Color, 0)
 
@@ -0,0 +1,24 @@
+package render_runtime
+
+import (
+	"go.starlark.net/starlark"
+
+	"tidbyt.dev/pixlet/render"
+)
+
+const (
+	// ThreadFontsKey is the name of the Starlark thread-local that holds
+	// the fonts available to text widgets, on top of the built-in ones.
+	ThreadFontsKey = "tidbyt.dev/pixlet/render/$fonts"
+)
+
+// AttachFontsToThread makes the fonts in src available to text widgets
+// created on a Starlark thread.
+func AttachFontsToThread(thread *starlark.Thread, src render.FontSource) {
+	thread.SetLocal(ThreadFontsKey, src)
+}
+
+func threadFonts(thread *starlark.Thread) render.FontSource {
+	src, _ := thread.Local(ThreadFontsKey).(render.FontSource)
+	return src
+}
@@ -37,28 +37,56 @@ func LoadRenderModule() (starlark.StringDict, error) {
 
 					"Animation": starlark.NewBuiltin("Animation", newAnimation),
 
+					"Arc": starlark.NewBuiltin("Arc", newArc),
+
+					"BarChart": starlark.NewBuiltin("BarChart", newBarChart),
+
+					"Bezier": starlark.NewBuiltin("Bezier", newBezier),
+
 					"Box": starlark.NewBuiltin("Box", newBox),
 
+					"Canvas": starlark.NewBuiltin("Canvas", newCanvas),
+
 					"Circle": starlark.NewBuiltin("Circle", newCircle),
 
 					"Column": starlark.NewBuiltin("Column", newColumn),
 
+					"Gauge": starlark.NewBuiltin("Gauge", newGauge),
+
 					"Image": starlark.NewBuiltin("Image", newImage),
 
+					"Label": starlark.NewBuiltin("Label", newLabel),
+
+					"Line": starlark.NewBuiltin("Line", newLine),
+
 					"Marquee": starlark.NewBuiltin("Marquee", newMarquee),
 
 					"Padding": starlark.NewBuiltin("Padding", newPadding),
 
 					"PieChart": starlark.NewBuiltin("PieChart", newPieChart),
 
+					"Pixel": starlark.NewBuiltin("Pixel", newPixel),
+
 					"Plot": starlark.NewBuiltin("Plot", newPlot),
 
+					"Polygon": starlark.NewBuiltin("Polygon", newPolygon),
+
+					"Rect": starlark.NewBuiltin("Rect", newRect),
+
+					"RichText": starlark.NewBuiltin("RichText", newRichText),
+
 					"Root": starlark.NewBuiltin("Root", newRoot),
 
 					"Row": starlark.NewBuiltin("Row", newRow),
 
 					"Sequence": starlark.NewBuiltin("Sequence", newSequence),
 
+					"Series": starlark.NewBuiltin("Series", newSeries),
+
+					"Span": starlark.NewBuiltin("Span", newSpan),
+
+					"Sparkline": starlark.NewBuiltin("Sparkline", newSparkline),
+
 					"Stack": starlark.NewBuiltin("Stack", newStack),
 
 					"Text": starlark.NewBuiltin("Text", newText),
@@ -151,6 +179,9 @@ func (w *Animation) Attr(name string) (starlark.Value, error) {
 
 	case "children":
 
+		if w.starlarkChildren == nil {
+			return starlark.None, nil
+		}
 		return w.starlarkChildren, nil
 
 	case "frame_count":
@@ -183,19 +214,17 @@ func animationFrameCount(
 	return starlark.MakeInt(count), nil
 }
 
-type Box struct {
-	Widget
+type Arc struct {
+	render.Arc
 
-	render.Box
+	starlarkStart starlark.Value
 
-	starlarkChild starlark.Value
+	starlarkEnd starlark.Value
 
 	starlarkColor starlark.String
-
-	frame_count *starlark.Builtin
 }
 
-func newBox(
+func newArc(
 	thread *starlark.Thread,
 	_ *starlark.Builtin,
 	args starlark.Tuple,
@@ -203,44 +232,56 @@ func newBox(
 ) (starlark.Value, error) {
 
 	var (
-		child   starlark.Value
-		width   starlark.Int
-		height  starlark.Int
-		padding starlark.Int
-		color   starlark.String
+		x           starlark.Int
+		y           starlark.Int
+		radius      starlark.Int
+		start       starlark.Value
+		end         starlark.Value
+		color       starlark.String
+		start_frame starlark.Int
+		end_frame   starlark.Int
 	)
 
 	if err := starlark.UnpackArgs(
-		"Box",
+		"Arc",
 		args, kwargs,
-		"child?", &child,
-		"width?", &width,
-		"height?", &height,
-		"padding?", &padding,
+		"x", &x,
+		"y", &y,
+		"radius", &radius,
+		"start?", &start,
+		"end?", &end,
 		"color?", &color,
+		"start_frame?", &start_frame,
+		"end_frame?", &end_frame,
 	); err != nil {
-		return nil, fmt.Errorf("unpacking arguments for Box: %s", err)
+		return nil, fmt.Errorf("unpacking arguments for Arc: %s", err)
 	}
 
-	w := &Box{}
+	w := &Arc{}
 
-	if child != nil {
-		childWidget, ok := child.(Widget)
-		if !ok {
-			return nil, fmt.Errorf(
-				"invalid type for child: %s (expected Widget)",
-				child.Type(),
-			)
-		}
-		w.Child = childWidget.AsRenderWidget()
-		w.starlarkChild = child
-	}
+	w.X = int(x.BigInt().Int64())
 
-	w.Width = int(width.BigInt().Int64())
+	w.Y = int(y.BigInt().Int64())
 
-	w.Height = int(height.BigInt().Int64())
+	w.Radius = int(radius.BigInt().Int64())
 
-	w.Padding = int(padding.BigInt().Int64())
+	if start != nil {
+		w.starlarkStart = start
+		if val, ok := starlark.AsFloat(w.starlarkStart); ok {
+			w.Start = val
+		} else {
+			return nil, fmt.Errorf("expected number, but got: %s", w.starlarkStart.String())
+		}
+	}
+
+	if end != nil {
+		w.starlarkEnd = end
+		if val, ok := starlark.AsFloat(w.starlarkEnd); ok {
+			w.End = val
+		} else {
+			return nil, fmt.Errorf("expected number, but got: %s", w.starlarkEnd.String())
+		}
+	}
 
 	w.starlarkColor = color
 	if color.Len() > 0 {
@@ -251,87 +292,90 @@ func newBox(
 		w.Color = c
 	}
 
-	w.frame_count = starlark.NewBuiltin("frame_count", boxFrameCount)
+	w.StartFrame = int(start_frame.BigInt().Int64())
 
-	return w, nil
-}
+	w.EndFrame = int(end_frame.BigInt().Int64())
 
-func (w *Box) AsRenderWidget() render.Widget {
-	return &w.Box
+	return w, nil
 }
 
-func (w *Box) AttrNames() []string {
+func (w *Arc) AttrNames() []string {
 	return []string{
-		"child", "width", "height", "padding", "color",
+		"x", "y", "radius", "start", "end", "color", "start_frame", "end_frame",
 	}
 }
 
-func (w *Box) Attr(name string) (starlark.Value, error) {
+func (w *Arc) Attr(name string) (starlark.Value, error) {
 	switch name {
 
-	case "child":
+	case "x":
 
-		return w.starlarkChild, nil
+		return starlark.MakeInt(int(w.X)), nil
 
-	case "width":
+	case "y":
 
-		return starlark.MakeInt(int(w.Width)), nil
+		return starlark.MakeInt(int(w.Y)), nil
 
-	case "height":
+	case "radius":
 
-		return starlark.MakeInt(int(w.Height)), nil
+		return starlark.MakeInt(int(w.Radius)), nil
 
-	case "padding":
+	case "start":
 
-		return starlark.MakeInt(int(w.Padding)), nil
+		if w.starlarkStart == nil {
+			return starlark.Float(w.Start), nil
+		}
+		return w.starlarkStart, nil
+
+	case "end":
+
+		if w.starlarkEnd == nil {
+			return starlark.Float(w.End), nil
+		}
+		return w.starlarkEnd, nil
 
 	case "color":
 
 		return w.starlarkColor, nil
 
-	case "frame_count":
-		return w.frame_count.BindReceiver(w), nil
+	case "start_frame":
+
+		return starlark.MakeInt(int(w.StartFrame)), nil
+
+	case "end_frame":
+
+		return starlark.MakeInt(int(w.EndFrame)), nil
 
 	default:
 		return nil, nil
 	}
 }
 
-func (w *Box) String() string       { return "Box(...)" }
-func (w *Box) Type() string         { return "Box" }
-func (w *Box) Freeze()              {}
-func (w *Box) Truth() starlark.Bool { return true }
+func (w *Arc) String() string       { return "Arc(...)" }
+func (w *Arc) Type() string         { return "Arc" }
+func (w *Arc) Freeze()              {}
+func (w *Arc) Truth() starlark.Bool { return true }
 
-func (w *Box) Hash() (uint32, error) {
+func (w *Arc) Hash() (uint32, error) {
 	sum, err := hashstructure.Hash(w, hashstructure.FormatV2, nil)
 	return uint32(sum), err
 }
 
-func boxFrameCount(
-	thread *starlark.Thread,
-	b *starlark.Builtin,
-	args starlark.Tuple,
-	kwargs []starlark.Tuple) (starlark.Value, error) {
-
-	w := b.Receiver().(*Box)
-	count := w.FrameCount()
-
-	return starlark.MakeInt(count), nil
-}
-
-type Circle struct {
+type BarChart struct {
 	Widget
 
-	render.Circle
+	render.BarChart
 
-	starlarkColor starlark.String
+	starlarkData *starlark.List
 
-	starlarkChild starlark.Value
+	starlarkColors *starlark.List
+
+	starlarkLim starlark.Tuple
 
 	frame_count *starlark.Builtin
 }
 
-func newCircle(
+func newBarChart(
 	thread *starlark.Thread,
 	_ *starlark.Builtin,
 	args starlark.Tuple,
@@ -339,75 +383,121 @@ func newCircle(
 ) (starlark.Value, error) {
 
 	var (
-		color    starlark.String
-		diameter starlark.Int
-		child    starlark.Value
+		data       *starlark.List
+		width      starlark.Int
+		height     starlark.Int
+		colors     *starlark.List
+		horizontal starlark.Bool
+		stacked    starlark.Bool
+		spacing    starlark.Int
+		lim        starlark.Tuple
 	)
 
 	if err := starlark.UnpackArgs(
-		"Circle",
+		"BarChart",
 		args, kwargs,
-		"color", &color,
-		"diameter", &diameter,
-		"child?", &child,
+		"data", &data,
+		"width", &width,
+		"height", &height,
+		"colors?", &colors,
+		"horizontal?", &horizontal,
+		"stacked?", &stacked,
+		"spacing?", &spacing,
+		"lim?", &lim,
 	); err != nil {
-		return nil, fmt.Errorf("unpacking arguments for Circle: %s", err)
+		return nil, fmt.Errorf("unpacking arguments for BarChart: %s", err)
 	}
 
-	w := &Circle{}
+	w := &BarChart{}
 
-	w.starlarkColor = color
-	if color.Len() > 0 {
-		c, err := render.ParseColor(color.GoString())
-		if err != nil {
-			return nil, fmt.Errorf("color is not a valid hex string: %s", color.String())
-		}
-		w.Color = c
+	w.starlarkData = data
+	if val, err := BarSeriesFromStarlark(data); err == nil {
+		w.Data = val
+	} else {
+		return nil, err
 	}
 
-	w.Diameter = int(diameter.BigInt().Int64())
+	w.Width = int(width.BigInt().Int64())
 
-	if child != nil {
-		childWidget, ok := child.(Widget)
-		if !ok {
-			return nil, fmt.Errorf(
-				"invalid type for child: %s (expected Widget)",
-				child.Type(),
-			)
+	w.Height = int(height.BigInt().Int64())
+
+	if colors != nil {
+		w.starlarkColors = colors
+		if val, err := ColorSeriesFromStarlark(colors); err == nil {
+			w.Colors = val
+		} else {
+			return nil, err
 		}
-		w.Child = childWidget.AsRenderWidget()
-		w.starlarkChild = child
 	}
 
-	w.frame_count = starlark.NewBuiltin("frame_count", circleFrameCount)
+	w.Horizontal = bool(horizontal)
+
+	w.Stacked = bool(stacked)
+
+	w.Spacing = int(spacing.BigInt().Int64())
+
+	w.starlarkLim = lim
+	if val, err := DataPointFromStarlark(lim); err == nil {
+		w.Lim = val
+	} else {
+		return nil, err
+	}
+
+	w.frame_count = starlark.NewBuiltin("frame_count", barchartFrameCount)
 
 	return w, nil
 }
 
-func (w *Circle) AsRenderWidget() render.Widget {
-	return &w.Circle
+func (w *BarChart) AsRenderWidget() render.Widget {
+	return &w.BarChart
 }
 
-func (w *Circle) AttrNames() []string {
+func (w *BarChart) AttrNames() []string {
 	return []string{
-		"color", "diameter", "child",
+		"data", "width", "height", "colors", "horizontal", "stacked", "spacing", "lim",
 	}
 }
 
-func (w *Circle) Attr(name string) (starlark.Value, error) {
+func (w *BarChart) Attr(name string) (starlark.Value, error) {
 	switch name {
 
-	case "color":
+	case "data":
 
-		return w.starlarkColor, nil
+		if w.starlarkData == nil {
+			return starlark.None, nil
+		}
+		return w.starlarkData, nil
 
-	case "diameter":
+	case "width":
 
-		return starlark.MakeInt(int(w.Diameter)), nil
+		return starlark.MakeInt(int(w.Width)), nil
 
-	case "child":
+	case "height":
 
-		return w.starlarkChild, nil
+		return starlark.MakeInt(int(w.Height)), nil
+
+	case "colors":
+
+		if w.starlarkColors == nil {
+			return starlark.None, nil
+		}
+		return w.starlarkColors, nil
+
+	case "horizontal":
+
+		return starlark.Bool(w.Horizontal), nil
+
+	case "stacked":
+
+		return starlark.Bool(w.Stacked), nil
+
+	case "spacing":
+
+		return starlark.MakeInt(int(w.Spacing)), nil
+
+	case "lim":
+
+		return w.starlarkLim, nil
 
 	case "frame_count":
 		return w.frame_count.BindReceiver(w), nil
@@ -417,39 +507,37 @@ func (w *Circle) Attr(name string) (starlark.Value, error) {
 	}
 }
 
-func (w *Circle) String() string       { return "Circle(...)" }
-func (w *Circle) Type() string         { return "Circle" }
-func (w *Circle) Freeze()              {}
-func (w *Circle) Truth() starlark.Bool { return true }
+func (w *BarChart) String() string       { return "BarChart(...)" }
+func (w *BarChart) Type() string         { return "BarChart" }
+func (w *BarChart) Freeze()              {}
+func (w *BarChart) Truth() starlark.Bool { return true }
 
-func (w *Circle) Hash() (uint32, error) {
+func (w *BarChart) Hash() (uint32, error) {
 	sum, err := hashstructure.Hash(w, hashstructure.FormatV2, nil)
 	return uint32(sum), err
 }
 
-func circleFrameCount(
+func barchartFrameCount(
 	thread *starlark.Thread,
 	b *starlark.Builtin,
 	args starlark.Tuple,
 	kwargs []starlark.Tuple) (starlark.Value, error) {
 
-	w := b.Receiver().(*Circle)
+	w := b.Receiver().(*BarChart)
 	count := w.FrameCount()
 
 	return starlark.MakeInt(count), nil
 }
 
-type Column struct {
-	Widget
-
-	render.Column
+type Bezier struct {
+	render.Bezier
 
-	starlarkChildren *starlark.List
+	starlarkPoints *starlark.List
 
-	frame_count *starlark.Builtin
+	starlarkColor starlark.String
 }
 
-func newColumn(
+func newBezier(
 	thread *starlark.Thread,
 	_ *starlark.Builtin,
 	args starlark.Tuple,
@@ -457,127 +545,104 @@ func newColumn(
 ) (starlark.Value, error) {
 
 	var (
-		children    *starlark.List
-		main_align  starlark.String
-		cross_align starlark.String
-		expanded    starlark.Bool
+		points      *starlark.List
+		color       starlark.String
+		start_frame starlark.Int
+		end_frame   starlark.Int
 	)
 
 	if err := starlark.UnpackArgs(
-		"Column",
+		"Bezier",
 		args, kwargs,
-		"children", &children,
-		"main_align?", &main_align,
-		"cross_align?", &cross_align,
-		"expanded?", &expanded,
+		"points", &points,
+		"color?", &color,
+		"start_frame?", &start_frame,
+		"end_frame?", &end_frame,
 	); err != nil {
-		return nil, fmt.Errorf("unpacking arguments for Column: %s", err)
+		return nil, fmt.Errorf("unpacking arguments for Bezier: %s", err)
 	}
 
-	w := &Column{}
+	w := &Bezier{}
 
-	var childrenVal starlark.Value
-	childrenIter := children.Iterate()
-	defer childrenIter.Done()
-	for i := 0; childrenIter.Next(&childrenVal); {
-		if _, isNone := childrenVal.(starlark.NoneType); isNone {
-			continue
-		}
+	w.starlarkPoints = points
+	if val, err := DataSeriesFromStarlark(points); err == nil {
+		w.Points = val
+	} else {
+		return nil, err
+	}
 
-		childrenChild, ok := childrenVal.(Widget)
-		if !ok {
-			return nil, fmt.Errorf(
-				"expected children to be a list of Widget but found: %s (at index %d)",
-				childrenVal.Type(),
-				i,
-			)
+	w.starlarkColor = color
+	if color.Len() > 0 {
+		c, err := render.ParseColor(color.GoString())
+		if err != nil {
+			return nil, fmt.Errorf("color is not a valid hex string: %s", color.String())
 		}
-
-		w.Children = append(w.Children, childrenChild.AsRenderWidget())
+		w.Color = c
 	}
-	w.starlarkChildren = children
-
-	w.MainAlign = main_align.GoString()
 
-	w.CrossAlign = cross_align.GoString()
-
-	w.Expanded = bool(expanded)
+	w.StartFrame = int(start_frame.BigInt().Int64())
 
-	w.frame_count = starlark.NewBuiltin("frame_count", columnFrameCount)
+	w.EndFrame = int(end_frame.BigInt().Int64())
 
 	return w, nil
 }
 
-func (w *Column) AsRenderWidget() render.Widget {
-	return &w.Column
-}
-
-func (w *Column) AttrNames() []string {
+func (w *Bezier) AttrNames() []string {
 	return []string{
-		"children", "main_align", "cross_align", "expanded",
+		"points", "color", "start_frame", "end_frame",
 	}
 }
 
-func (w *Column) Attr(name string) (starlark.Value, error) {
+func (w *Bezier) Attr(name string) (starlark.Value, error) {
 	switch name {
 
-	case "children":
+	case "points":
 
-		return w.starlarkChildren, nil
+		if w.starlarkPoints == nil {
+			return starlark.None, nil
+		}
+		return w.starlarkPoints, nil
 
-	case "main_align":
+	case "color":
 
-		return starlark.String(w.MainAlign), nil
+		return w.starlarkColor, nil
 
-	case "cross_align":
+	case "start_frame":
 
-		return starlark.String(w.CrossAlign), nil
+		return starlark.MakeInt(int(w.StartFrame)), nil
 
-	case "expanded":
-
-		return starlark.Bool(w.Expanded), nil
+	case "end_frame":
 
-	case "frame_count":
-		return w.frame_count.BindReceiver(w), nil
+		return starlark.MakeInt(int(w.EndFrame)), nil
 
 	default:
 		return nil, nil
 	}
 }
 
-func (w *Column) String() string       { return "Column(...)" }
-func (w *Column) Type() string         { return "Column" }
-func (w *Column) Freeze()              {}
-func (w *Column) Truth() starlark.Bool { return true }
+func (w *Bezier) String() string       { return "Bezier(...)" }
+func (w *Bezier) Type() string         { return "Bezier" }
+func (w *Bezier) Freeze()              {}
+func (w *Bezier) Truth() starlark.Bool { return true }
 
-func (w *Column) Hash() (uint32, error) {
+func (w *Bezier) Hash() (uint32, error) {
 	sum, err := hashstructure.Hash(w, hashstructure.FormatV2, nil)
 	return uint32(sum), err
 }
 
-func columnFrameCount(
-	thread *starlark.Thread,
-	b *starlark.Builtin,
-	args starlark.Tuple,
-	kwargs []starlark.Tuple) (starlark.Value, error) {
-
-	w := b.Receiver().(*Column)
-	count := w.FrameCount()
-
-	return starlark.MakeInt(count), nil
-}
-
-type Image struct {
+type Box struct {
 	Widget
 
-	render.Image
+	render.Box
 
-	size *starlark.Builtin
+	starlarkChild starlark.Value
+
+	starlarkColor starlark.String
 
 	frame_count *starlark.Builtin
 }
 
-func newImage(
+func newBox(
 	thread *starlark.Thread,
 	_ *starlark.Builtin,
 	args starlark.Tuple,
@@ -585,56 +650,75 @@ func newImage(
 ) (starlark.Value, error) {
 
 	var (
-		src    starlark.String
-		width  starlark.Int
-		height starlark.Int
+		child   starlark.Value
+		width   starlark.Int
+		height  starlark.Int
+		padding starlark.Int
+		color   starlark.String
 	)
 
 	if err := starlark.UnpackArgs(
-		"Image",
+		"Box",
 		args, kwargs,
-		"src", &src,
+		"child?", &child,
 		"width?", &width,
 		"height?", &height,
+		"padding?", &padding,
+		"color?", &color,
 	); err != nil {
-		return nil, fmt.Errorf("unpacking arguments for Image: %s", err)
+		return nil, fmt.Errorf("unpacking arguments for Box: %s", err)
 	}
 
-	w := &Image{}
+	w := &Box{}
 
-	w.Src = src.GoString()
+	if child != nil {
+		childWidget, ok := child.(Widget)
+		if !ok {
+			return nil, fmt.Errorf(
+				"invalid type for child: %s (expected Widget)",
+				child.Type(),
+			)
+		}
+		w.Child = childWidget.AsRenderWidget()
+		w.starlarkChild = child
+	}
 
 	w.Width = int(width.BigInt().Int64())
 
 	w.Height = int(height.BigInt().Int64())
 
-	w.size = starlark.NewBuiltin("size", imageSize)
-
-	w.frame_count = starlark.NewBuiltin("frame_count", imageFrameCount)
+	w.Padding = int(padding.BigInt().Int64())
 
-	if err := w.Init(); err != nil {
-		return nil, err
+	w.starlarkColor = color
+	if color.Len() > 0 {
+		c, err := render.ParseColor(color.GoString())
+		if err != nil {
+			return nil, fmt.Errorf("color is not a valid hex string: %s", color.String())
+		}
+		w.Color = c
 	}
 
+	w.frame_count = starlark.NewBuiltin("frame_count", boxFrameCount)
+
 	return w, nil
 }
 
-func (w *Image) AsRenderWidget() render.Widget {
-	return &w.Image
+func (w *Box) AsRenderWidget() render.Widget {
+	return &w.Box
 }
 
-func (w *Image) AttrNames() []string {
+func (w *Box) AttrNames() []string {
 	return []string{
-		"src", "width", "height", "delay",
+		"child", "width", "height", "padding", "color",
 	}
 }
 
-func (w *Image) Attr(name string) (starlark.Value, error) {
+func (w *Box) Attr(name string) (starlark.Value, error) {
 	switch name {
 
-	case "src":
+	case "child":
 
-		return starlark.String(w.Src), nil
+		return w.starlarkChild, nil
 
 	case "width":
 
@@ -644,12 +728,13 @@ func (w *Image) Attr(name string) (starlark.Value, error) {
 
 		return starlark.MakeInt(int(w.Height)), nil
 
-	case "delay":
+	case "padding":
 
-		return starlark.MakeInt(int(w.Delay)), nil
+		return starlark.MakeInt(int(w.Padding)), nil
 
-	case "size":
-		return w.size.BindReceiver(w), nil
+	case "color":
+
+		return w.starlarkColor, nil
 
 	case "frame_count":
 		return w.frame_count.BindReceiver(w), nil
@@ -659,54 +744,39 @@ func (w *Image) Attr(name string) (starlark.Value, error) {
 	}
 }
 
-func (w *Image) String() string       { return "Image(...)" }
-func (w *Image) Type() string         { return "Image" }
-func (w *Image) Freeze()              {}
-func (w *Image) Truth() starlark.Bool { return true }
+func (w *Box) String() string       { return "Box(...)" }
+func (w *Box) Type() string         { return "Box" }
+func (w *Box) Freeze()              {}
+func (w *Box) Truth() starlark.Bool { return true }
 
-func (w *Image) Hash() (uint32, error) {
+func (w *Box) Hash() (uint32, error) {
 	sum, err := hashstructure.Hash(w, hashstructure.FormatV2, nil)
 	return uint32(sum), err
 }
 
-func imageSize(
-	thread *starlark.Thread,
-	b *starlark.Builtin,
-	args starlark.Tuple,
-	kwargs []starlark.Tuple) (starlark.Value, error) {
-
-	w := b.Receiver().(*Image)
-	width, height := w.Size()
-
-	return starlark.Tuple([]starlark.Value{
-		starlark.MakeInt(width),
-		starlark.MakeInt(height),
-	}), nil
-}
-
-func imageFrameCount(
+func boxFrameCount(
 	thread *starlark.Thread,
 	b *starlark.Builtin,
 	args starlark.Tuple,
 	kwargs []starlark.Tuple) (starlark.Value, error) {
 
-	w := b.Receiver().(*Image)
+	w := b.Receiver().(*Box)
 	count := w.FrameCount()
 
 	return starlark.MakeInt(count), nil
 }
 
-type Marquee struct {
+type Canvas struct {
 	Widget
 
-	render.Marquee
+	render.Canvas
 
-	starlarkChild starlark.Value
+	starlarkOps *starlark.List
 
 	frame_count *starlark.Builtin
 }
 
-func newMarquee(
+func newCanvas(
 	thread *starlark.Thread,
 	_ *starlark.Builtin,
 	args starlark.Tuple,
@@ -714,81 +784,62 @@ func newMarquee(
 ) (starlark.Value, error) {
 
 	var (
-		child            starlark.Value
-		width            starlark.Int
-		height           starlark.Int
-		offset_start     starlark.Int
-		offset_end       starlark.Int
-		scroll_direction starlark.String
-		align            starlark.String
-		delay            starlark.Int
+		width  starlark.Int
+		height starlark.Int
+		ops    *starlark.List
 	)
 
 	if err := starlark.UnpackArgs(
-		"Marquee",
+		"Canvas",
 		args, kwargs,
-		"child", &child,
-		"width?", &width,
-		"height?", &height,
-		"offset_start?", &offset_start,
-		"offset_end?", &offset_end,
-		"scroll_direction?", &scroll_direction,
-		"align?", &align,
-		"delay?", &delay,
+		"width", &width,
+		"height", &height,
+		"ops?", &ops,
 	); err != nil {
-		return nil, fmt.Errorf("unpacking arguments for Marquee: %s", err)
+		return nil, fmt.Errorf("unpacking arguments for Canvas: %s", err)
 	}
 
-	w := &Marquee{}
-
-	if child != nil {
-		childWidget, ok := child.(Widget)
-		if !ok {
-			return nil, fmt.Errorf(
-				"invalid type for child: %s (expected Widget)",
-				child.Type(),
-			)
-		}
-		w.Child = childWidget.AsRenderWidget()
-		w.starlarkChild = child
-	}
+	w := &Canvas{}
 
 	w.Width = int(width.BigInt().Int64())
 
 	w.Height = int(height.BigInt().Int64())
 
-	w.OffsetStart = int(offset_start.BigInt().Int64())
-
-	w.OffsetEnd = int(offset_end.BigInt().Int64())
-
-	w.ScrollDirection = scroll_direction.GoString()
+	if ops != nil {
+		w.starlarkOps = ops
+		for i := 0; i < ops.Len(); i++ {
+			val, ok := ops.Index(i).(canvasOp)
+			if !ok {
+				return nil, fmt.Errorf("invalid type for ops: %s (expected a drawing op)", ops.Index(i).Type())
+			}
+			w.Ops = append(w.Ops, val.AsCanvasOp())
+		}
+	}
 
-	w.Align = align.GoString()
+	w.frame_count = starlark.NewBuiltin("frame_count", canvasFrameCount)
 
-	w.Delay = int(delay.BigInt().Int64())
+	w.SetFontSource(threadFonts(thread))
 
-	w.frame_count = starlark.NewBuiltin("frame_count", marqueeFrameCount)
+	if err := w.Init(); err != nil {
+		return nil, err
+	}
 
 	return w, nil
 }
 
-func (w *Marquee) AsRenderWidget() render.Widget {
-	return &w.Marquee
+func (w *Canvas) AsRenderWidget() render.Widget {
+	return &w.Canvas
 }
 
-func (w *Marquee) AttrNames() []string {
+func (w *Canvas) AttrNames() []string {
 	return []string{
-		"child", "width", "height", "offset_start", "offset_end", "scroll_direction", "align", "delay",
+		"width", "height", "ops",
 	}
 }
 
-func (w *Marquee) Attr(name string) (starlark.Value, error) {
+func (w *Canvas) Attr(name string) (starlark.Value, error) {
 	switch name {
 
-	case "child":
-
-		return w.starlarkChild, nil
-
 	case "width":
 
 		return starlark.MakeInt(int(w.Width)), nil
@@ -797,25 +848,12 @@ func (w *Marquee) Attr(name string) (starlark.Value, error) {
 
 		return starlark.MakeInt(int(w.Height)), nil
 
-	case "offset_start":
-
-		return starlark.MakeInt(int(w.OffsetStart)), nil
-
-	case "offset_end":
-
-		return starlark.MakeInt(int(w.OffsetEnd)), nil
-
-	case "scroll_direction":
-
-		return starlark.String(w.ScrollDirection), nil
-
-	case "align":
-
-		return starlark.String(w.Align), nil
-
-	case "delay":
+	case "ops":
 
-		return starlark.MakeInt(int(w.Delay)), nil
+		if w.starlarkOps == nil {
+			return starlark.None, nil
+		}
+		return w.starlarkOps, nil
 
 	case "frame_count":
 		return w.frame_count.BindReceiver(w), nil
@@ -825,43 +863,41 @@ func (w *Marquee) Attr(name string) (starlark.Value, error) {
 	}
 }
 
-func (w *Marquee) String() string       { return "Marquee(...)" }
-func (w *Marquee) Type() string         { return "Marquee" }
-func (w *Marquee) Freeze()              {}
-func (w *Marquee) Truth() starlark.Bool { return true }
+func (w *Canvas) String() string       { return "Canvas(...)" }
+func (w *Canvas) Type() string         { return "Canvas" }
+func (w *Canvas) Freeze()              {}
+func (w *Canvas) Truth() starlark.Bool { return true }
 
-func (w *Marquee) Hash() (uint32, error) {
+func (w *Canvas) Hash() (uint32, error) {
 	sum, err := hashstructure.Hash(w, hashstructure.FormatV2, nil)
 	return uint32(sum), err
 }
 
-func marqueeFrameCount(
+func canvasFrameCount(
 	thread *starlark.Thread,
 	b *starlark.Builtin,
 	args starlark.Tuple,
 	kwargs []starlark.Tuple) (starlark.Value, error) {
 
-	w := b.Receiver().(*Marquee)
+	w := b.Receiver().(*Canvas)
 	count := w.FrameCount()
 
 	return starlark.MakeInt(count), nil
 }
 
-type Padding struct {
+type Circle struct {
 	Widget
 
-	render.Padding
-
-	starlarkChild starlark.Value
-
-	starlarkPad starlark.Value
+	render.Circle
 
 	starlarkColor starlark.String
 
+	starlarkChild starlark.Value
+
 	frame_count *starlark.Builtin
 }
 
-func newPadding(
+func newCircle(
 	thread *starlark.Thread,
 	_ *starlark.Builtin,
 	args starlark.Tuple,
@@ -869,70 +905,22 @@ func newPadding(
 ) (starlark.Value, error) {
 
 	var (
-		child    starlark.Value
-		pad      starlark.Value
-		expanded starlark.Bool
 		color    starlark.String
+		diameter starlark.Int
+		child    starlark.Value
 	)
 
 	if err := starlark.UnpackArgs(
-		"Padding",
+		"Circle",
 		args, kwargs,
-		"child", &child,
-		"pad?", &pad,
-		"expanded?", &expanded,
-		"color?", &color,
+		"color", &color,
+		"diameter", &diameter,
+		"child?", &child,
 	); err != nil {
-		return nil, fmt.Errorf("unpacking arguments for Padding: %s", err)
+		return nil, fmt.Errorf("unpacking arguments for Circle: %s", err)
 	}
 
-	w := &Padding{}
-
-	if child != nil {
-		childWidget, ok := child.(Widget)
-		if !ok {
-			return nil, fmt.Errorf(
-				"invalid type for child: %s (expected Widget)",
-				child.Type(),
-			)
-		}
-		w.Child = childWidget.AsRenderWidget()
-		w.starlarkChild = child
-	}
-
-	w.starlarkPad = pad
-	switch padVal := pad.(type) {
-	case starlark.Int:
-		padInt := int(padVal.BigInt().Int64())
-		w.Pad.Left = padInt
-		w.Pad.Top = padInt
-		w.Pad.Right = padInt
-		w.Pad.Bottom = padInt
-	case starlark.Tuple:
-		padList := []starlark.Value(padVal)
-		if len(padList) != 4 {
-			return nil, fmt.Errorf(
-				"pad tuple must hold 4 elements (left, top, right, bottom), found %d",
-				len(padList),
-			)
-		}
-		padListInt := make([]starlark.Int, 4)
-		for i := 0; i < 4; i++ {
-			pi, ok := padList[i].(starlark.Int)
-			if !ok {
-				return nil, fmt.Errorf("pad element %d is not int", i)
-			}
-			padListInt[i] = pi
-		}
-		w.Pad.Left = int(padListInt[0].BigInt().Int64())
-		w.Pad.Top = int(padListInt[1].BigInt().Int64())
-		w.Pad.Right = int(padListInt[2].BigInt().Int64())
-		w.Pad.Bottom = int(padListInt[3].BigInt().Int64())
-	default:
-		return nil, fmt.Errorf("pad must be int or 4-tuple of int")
-	}
-
-	w.Expanded = bool(expanded)
+	w := &Circle{}
 
 	w.starlarkColor = color
 	if color.Len() > 0 {
@@ -943,39 +931,49 @@ func newPadding(
 		w.Color = c
 	}
 
-	w.frame_count = starlark.NewBuiltin("frame_count", paddingFrameCount)
+	w.Diameter = int(diameter.BigInt().Int64())
+
+	if child != nil {
+		childWidget, ok := child.(Widget)
+		if !ok {
+			return nil, fmt.Errorf(
+				"invalid type for child: %s (expected Widget)",
+				child.Type(),
+			)
+		}
+		w.Child = childWidget.AsRenderWidget()
+		w.starlarkChild = child
+	}
+
+	w.frame_count = starlark.NewBuiltin("frame_count", circleFrameCount)
 
 	return w, nil
 }
 
-func (w *Padding) AsRenderWidget() render.Widget {
-	return &w.Padding
+func (w *Circle) AsRenderWidget() render.Widget {
+	return &w.Circle
 }
 
-func (w *Padding) AttrNames() []string {
+func (w *Circle) AttrNames() []string {
 	return []string{
-		"child", "pad", "expanded", "color",
+		"color", "diameter", "child",
 	}
 }
 
-func (w *Padding) Attr(name string) (starlark.Value, error) {
+func (w *Circle) Attr(name string) (starlark.Value, error) {
 	switch name {
 
-	case "child":
-
-		return w.starlarkChild, nil
-
-	case "pad":
+	case "color":
 
-		return w.starlarkPad, nil
+		return w.starlarkColor, nil
 
-	case "expanded":
+	case "diameter":
 
-		return starlark.Bool(w.Expanded), nil
+		return starlark.MakeInt(int(w.Diameter)), nil
 
-	case "color":
+	case "child":
 
-		return w.starlarkColor, nil
+		return w.starlarkChild, nil
 
 	case "frame_count":
 		return w.frame_count.BindReceiver(w), nil
@@ -985,41 +983,39 @@ func (w *Padding) Attr(name string) (starlark.Value, error) {
 	}
 }
 
-func (w *Padding) String() string       { return "Padding(...)" }
-func (w *Padding) Type() string         { return "Padding" }
-func (w *Padding) Freeze()              {}
-func (w *Padding) Truth() starlark.Bool { return true }
+func (w *Circle) String() string       { return "Circle(...)" }
+func (w *Circle) Type() string         { return "Circle" }
+func (w *Circle) Freeze()              {}
+func (w *Circle) Truth() starlark.Bool { return true }
 
-func (w *Padding) Hash() (uint32, error) {
+func (w *Circle) Hash() (uint32, error) {
 	sum, err := hashstructure.Hash(w, hashstructure.FormatV2, nil)
 	return uint32(sum), err
 }
 
-func paddingFrameCount(
+func circleFrameCount(
 	thread *starlark.Thread,
 	b *starlark.Builtin,
 	args starlark.Tuple,
 	kwargs []starlark.Tuple) (starlark.Value, error) {
 
-	w := b.Receiver().(*Padding)
+	w := b.Receiver().(*Circle)
 	count := w.FrameCount()
 
 	return starlark.MakeInt(count), nil
 }
 
-type PieChart struct {
+type Column struct {
 	Widget
 
-	render.PieChart
-
-	starlarkColors *starlark.List
+	render.Column
 
-	starlarkWeights *starlark.List
+	starlarkChildren *starlark.List
 
 	frame_count *starlark.Builtin
 }
 
-func newPieChart(
+func newColumn(
 	thread *starlark.Thread,
 	_ *starlark.Builtin,
 	args starlark.Tuple,
@@ -1027,68 +1023,88 @@ func newPieChart(
 ) (starlark.Value, error) {
 
 	var (
-		colors   *starlark.List
-		weights  *starlark.List
-		diameter starlark.Int
+		children    *starlark.List
+		main_align  starlark.String
+		cross_align starlark.String
+		expanded    starlark.Bool
 	)
 
 	if err := starlark.UnpackArgs(
-		"PieChart",
+		"Column",
 		args, kwargs,
-		"colors", &colors,
-		"weights", &weights,
-		"diameter", &diameter,
+		"children", &children,
+		"main_align?", &main_align,
+		"cross_align?", &cross_align,
+		"expanded?", &expanded,
 	); err != nil {
-		return nil, fmt.Errorf("unpacking arguments for PieChart: %s", err)
+		return nil, fmt.Errorf("unpacking arguments for Column: %s", err)
 	}
 
-	w := &PieChart{}
+	w := &Column{}
 
-	w.starlarkColors = colors
-	if val, err := ColorSeriesFromStarlark(colors); err == nil {
-		w.Colors = val
-	} else {
-		return nil, err
-	}
+	var childrenVal starlark.Value
+	childrenIter := children.Iterate()
+	defer childrenIter.Done()
+	for i := 0; childrenIter.Next(&childrenVal); {
+		if _, isNone := childrenVal.(starlark.NoneType); isNone {
+			continue
+		}
 
-	w.starlarkWeights = weights
-	if val, err := WeightsFromStarlark(weights); err == nil {
-		w.Weights = val
-	} else {
-		return nil, err
+		childrenChild, ok := childrenVal.(Widget)
+		if !ok {
+			return nil, fmt.Errorf(
+				"expected children to be a list of Widget but found: %s (at index %d)",
+				childrenVal.Type(),
+				i,
+			)
+		}
+
+		w.Children = append(w.Children, childrenChild.AsRenderWidget())
 	}
+	w.starlarkChildren = children
 
-	w.Diameter = int(diameter.BigInt().Int64())
+	w.MainAlign = main_align.GoString()
 
-	w.frame_count = starlark.NewBuiltin("frame_count", piechartFrameCount)
+	w.CrossAlign = cross_align.GoString()
+
+	w.Expanded = bool(expanded)
+
+	w.frame_count = starlark.NewBuiltin("frame_count", columnFrameCount)
 
 	return w, nil
 }
 
-func (w *PieChart) AsRenderWidget() render.Widget {
-	return &w.PieChart
+func (w *Column) AsRenderWidget() render.Widget {
+	return &w.Column
 }
 
-func (w *PieChart) AttrNames() []string {
+func (w *Column) AttrNames() []string {
 	return []string{
-		"colors", "weights", "diameter",
+		"children", "main_align", "cross_align", "expanded",
 	}
 }
 
-func (w *PieChart) Attr(name string) (starlark.Value, error) {
+func (w *Column) Attr(name string) (starlark.Value, error) {
 	switch name {
 
-	case "colors":
+	case "children":
 
-		return w.starlarkColors, nil
+		if w.starlarkChildren == nil {
+			return starlark.None, nil
+		}
+		return w.starlarkChildren, nil
 
-	case "weights":
+	case "main_align":
 
-		return w.starlarkWeights, nil
+		return starlark.String(w.MainAlign), nil
 
-	case "diameter":
+	case "cross_align":
 
-		return starlark.MakeInt(int(w.Diameter)), nil
+		return starlark.String(w.CrossAlign), nil
+
+	case "expanded":
+
+		return starlark.Bool(w.Expanded), nil
 
 	case "frame_count":
 		return w.frame_count.BindReceiver(w), nil
@@ -1098,51 +1114,49 @@ func (w *PieChart) Attr(name string) (starlark.Value, error) {
 	}
 }
 
-func (w *PieChart) String() string       { return "PieChart(...)" }
-func (w *PieChart) Type() string         { return "PieChart" }
-func (w *PieChart) Freeze()              {}
-func (w *PieChart) Truth() starlark.Bool { return true }
+func (w *Column) String() string       { return "Column(...)" }
+func (w *Column) Type() string         { return "Column" }
+func (w *Column) Freeze()              {}
+func (w *Column) Truth() starlark.Bool { return true }
 
-func (w *PieChart) Hash() (uint32, error) {
+func (w *Column) Hash() (uint32, error) {
 	sum, err := hashstructure.Hash(w, hashstructure.FormatV2, nil)
 	return uint32(sum), err
 }
 
-func piechartFrameCount(
+func columnFrameCount(
 	thread *starlark.Thread,
 	b *starlark.Builtin,
 	args starlark.Tuple,
 	kwargs []starlark.Tuple) (starlark.Value, error) {
 
-	w := b.Receiver().(*PieChart)
+	w := b.Receiver().(*Column)
 	count := w.FrameCount()
 
 	return starlark.MakeInt(count), nil
 }
 
-type Plot struct {
+type Gauge struct {
 	Widget
 
-	render.Plot
+	render.Gauge
 
-	starlarkData *starlark.List
+	starlarkValue starlark.Value
 
 	starlarkColor starlark.String
 
-	starlarkColorInverted starlark.String
-
-	starlarkXLim starlark.Tuple
+	starlarkTrackColor starlark.String
 
-	starlarkYLim starlark.Tuple
+	starlarkThresholds *starlark.List
 
-	starlarkFillColor starlark.String
+	starlarkThresholdColors *starlark.List
 
-	starlarkFillColorInverted starlark.String
+	starlarkLim starlark.Tuple
 
 	frame_count *starlark.Builtin
 }
 
-func newPlot(
+func newGauge(
 	thread *starlark.Thread,
 	_ *starlark.Builtin,
 	args starlark.Tuple,
@@ -1150,44 +1164,44 @@ func newPlot(
 ) (starlark.Value, error) {
 
 	var (
-		data                *starlark.List
-		width               starlark.Int
-		height              starlark.Int
-		color               starlark.String
-		color_inverted      starlark.String
-		x_lim               starlark.Tuple
-		y_lim               starlark.Tuple
-		fill                starlark.Bool
-		chart_type          starlark.String
-		fill_color          starlark.String
-		fill_color_inverted starlark.String
+		value            starlark.Value
+		width            starlark.Int
+		height           starlark.Int
+		color            starlark.String
+		track_color      starlark.String
+		thresholds       *starlark.List
+		threshold_colors *starlark.List
+		lim              starlark.Tuple
+		chart_type       starlark.String
+		thickness        starlark.Int
 	)
 
 	if err := starlark.UnpackArgs(
-		"Plot",
+		"Gauge",
 		args, kwargs,
-		"data", &data,
+		"value", &value,
 		"width", &width,
 		"height", &height,
 		"color?", &color,
-		"color_inverted?", &color_inverted,
-		"x_lim?", &x_lim,
-		"y_lim?", &y_lim,
-		"fill?", &fill,
+		"track_color?", &track_color,
+		"thresholds?", &thresholds,
+		"threshold_colors?", &threshold_colors,
+		"lim?", &lim,
 		"chart_type?", &chart_type,
-		"fill_color?", &fill_color,
-		"fill_color_inverted?", &fill_color_inverted,
+		"thickness?", &thickness,
 	); err != nil {
-		return nil, fmt.Errorf("unpacking arguments for Plot: %s", err)
+		return nil, fmt.Errorf("unpacking arguments for Gauge: %s", err)
 	}
 
-	w := &Plot{}
+	w := &Gauge{}
 
-	w.starlarkData = data
-	if val, err := DataSeriesFromStarlark(data); err == nil {
-		w.Data = val
-	} else {
-		return nil, err
+	if value != nil {
+		w.starlarkValue = value
+		if val, ok := starlark.AsFloat(w.starlarkValue); ok {
+			w.Value = val
+		} else {
+			return nil, fmt.Errorf("expected number, but got: %s", w.starlarkValue.String())
+		}
 	}
 
 	w.Width = int(width.BigInt().Int64())
@@ -1203,72 +1217,68 @@ func newPlot(
 		w.Color = c
 	}
 
-	w.starlarkColorInverted = color_inverted
-	if color_inverted.Len() > 0 {
-		c, err := render.ParseColor(color_inverted.GoString())
+	w.starlarkTrackColor = track_color
+	if track_color.Len() > 0 {
+		c, err := render.ParseColor(track_color.GoString())
 		if err != nil {
-			return nil, fmt.Errorf("color_inverted is not a valid hex string: %s", color_inverted.String())
+			return nil, fmt.Errorf("track_color is not a valid hex string: %s", track_color.String())
 		}
-		w.ColorInverted = c
+		w.TrackColor = c
 	}
 
-	w.starlarkXLim = x_lim
-	if val, err := DataPointFromStarlark(x_lim); err == nil {
-		w.XLim = val
-	} else {
-		return nil, err
+	if thresholds != nil {
+		w.starlarkThresholds = thresholds
+		if val, err := WeightsFromStarlark(thresholds); err == nil {
+			w.Thresholds = val
+		} else {
+			return nil, err
+		}
 	}
 
-	w.starlarkYLim = y_lim
-	if val, err := DataPointFromStarlark(y_lim); err == nil {
-		w.YLim = val
+	if threshold_colors != nil {
+		w.starlarkThresholdColors = threshold_colors
+		if val, err := ColorSeriesFromStarlark(threshold_colors); err == nil {
+			w.ThresholdColors = val
+		} else {
+			return nil, err
+		}
+	}
+
+	w.starlarkLim = lim
+	if val, err := DataPointFromStarlark(lim); err == nil {
+		w.Lim = val
 	} else {
 		return nil, err
 	}
 
-	w.Fill = bool(fill)
-
 	w.ChartType = chart_type.GoString()
 
-	w.starlarkFillColor = fill_color
-	if fill_color.Len() > 0 {
-		c, err := render.ParseColor(fill_color.GoString())
-		if err != nil {
-			return nil, fmt.Errorf("fill_color is not a valid hex string: %s", fill_color.String())
-		}
-		w.FillColor = c
-	}
-
-	w.starlarkFillColorInverted = fill_color_inverted
-	if fill_color_inverted.Len() > 0 {
-		c, err := render.ParseColor(fill_color_inverted.GoString())
-		if err != nil {
-			return nil, fmt.Errorf("fill_color_inverted is not a valid hex string: %s", fill_color_inverted.String())
-		}
-		w.FillColorInverted = c
-	}
+	w.Thickness = int(thickness.BigInt().Int64())
 
-	w.frame_count = starlark.NewBuiltin("frame_count", plotFrameCount)
+	w.frame_count = starlark.NewBuiltin("frame_count", gaugeFrameCount)
 
 	return w, nil
 }
 
-func (w *Plot) AsRenderWidget() render.Widget {
-	return &w.Plot
+func (w *Gauge) AsRenderWidget() render.Widget {
+	return &w.Gauge
 }
 
-func (w *Plot) AttrNames() []string {
+func (w *Gauge) AttrNames() []string {
 	return []string{
-		"data", "width", "height", "color", "color_inverted", "x_lim", "y_lim", "fill", "chart_type", "fill_color", "fill_color_inverted",
+		"value", "width", "height", "color", "track_color", "thresholds", "threshold_colors", "lim", "chart_type", "thickness",
 	}
 }
 
-func (w *Plot) Attr(name string) (starlark.Value, error) {
+func (w *Gauge) Attr(name string) (starlark.Value, error) {
 	switch name {
 
-	case "data":
+	case "value":
 
-		return w.starlarkData, nil
+		if w.starlarkValue == nil {
+			return starlark.Float(w.Value), nil
+		}
+		return w.starlarkValue, nil
 
 	case "width":
 
@@ -1282,33 +1292,35 @@ func (w *Plot) Attr(name string) (starlark.Value, error) {
 
 		return w.starlarkColor, nil
 
-	case "color_inverted":
+	case "track_color":
 
-		return w.starlarkColorInverted, nil
+		return w.starlarkTrackColor, nil
 
-	case "x_lim":
+	case "thresholds":
 
-		return w.starlarkXLim, nil
+		if w.starlarkThresholds == nil {
+			return starlark.None, nil
+		}
+		return w.starlarkThresholds, nil
 
-	case "y_lim":
+	case "threshold_colors":
 
-		return w.starlarkYLim, nil
+		if w.starlarkThresholdColors == nil {
+			return starlark.None, nil
+		}
+		return w.starlarkThresholdColors, nil
 
-	case "fill":
+	case "lim":
 
-		return starlark.Bool(w.Fill), nil
+		return w.starlarkLim, nil
 
 	case "chart_type":
 
 		return starlark.String(w.ChartType), nil
 
-	case "fill_color":
-
-		return w.starlarkFillColor, nil
-
-	case "fill_color_inverted":
+	case "thickness":
 
-		return w.starlarkFillColorInverted, nil
+		return starlark.MakeInt(int(w.Thickness)), nil
 
 	case "frame_count":
 		return w.frame_count.BindReceiver(w), nil
@@ -1318,35 +1330,45 @@ func (w *Plot) Attr(name string) (starlark.Value, error) {
 	}
 }
 
-func (w *Plot) String() string       { return "Plot(...)" }
-func (w *Plot) Type() string         { return "Plot" }
-func (w *Plot) Freeze()              {}
-func (w *Plot) Truth() starlark.Bool { return true }
+func (w *Gauge) String() string       { return "Gauge(...)" }
+func (w *Gauge) Type() string         { return "Gauge" }
+func (w *Gauge) Freeze()              {}
+func (w *Gauge) Truth() starlark.Bool { return true }
 
-func (w *Plot) Hash() (uint32, error) {
+func (w *Gauge) Hash() (uint32, error) {
 	sum, err := hashstructure.Hash(w, hashstructure.FormatV2, nil)
 	return uint32(sum), err
 }
 
-func plotFrameCount(
+func gaugeFrameCount(
 	thread *starlark.Thread,
 	b *starlark.Builtin,
 	args starlark.Tuple,
 	kwargs []starlark.Tuple) (starlark.Value, error) {
 
-	w := b.Receiver().(*Plot)
+	w := b.Receiver().(*Gauge)
 	count := w.FrameCount()
 
 	return starlark.MakeInt(count), nil
 }
 
-type Root struct {
-	render.Root
+type Image struct {
+	Widget
 
-	starlarkChild starlark.Value
+	render.Image
+
+	starlarkCrop starlark.Tuple
+
+	starlarkBrightness starlark.Value
+
+	starlarkContrast starlark.Value
+
+	size *starlark.Builtin
+
+	frame_count *starlark.Builtin
 }
 
-func newRoot(
+func newImage(
 	thread *starlark.Thread,
 	_ *starlark.Builtin,
 	args starlark.Tuple,
@@ -1354,109 +1376,2247 @@ func newRoot(
 ) (starlark.Value, error) {
 
 	var (
-		child               starlark.Value
-		delay               starlark.Int
-		max_age             starlark.Int
-		show_full_animation starlark.Bool
+		src    starlark.String
+		width  starlark.Int
+		height starlark.Int
+
+		resample   starlark.String
+		fit        starlark.String
+		crop       starlark.Tuple
+		brightness starlark.Value
+		contrast   starlark.Value
+		grayscale  starlark.Bool
+		dither     starlark.Int
 	)
 
 	if err := starlark.UnpackArgs(
-		"Root",
+		"Image",
 		args, kwargs,
-		"child", &child,
-		"delay?", &delay,
-		"max_age?", &max_age,
-		"show_full_animation?", &show_full_animation,
+		"src", &src,
+		"width?", &width,
+		"height?", &height,
+		"resample?", &resample,
+		"fit?", &fit,
+		"crop?", &crop,
+		"brightness?", &brightness,
+		"contrast?", &contrast,
+		"grayscale?", &grayscale,
+		"dither?", &dither,
 	); err != nil {
-		return nil, fmt.Errorf("unpacking arguments for Root: %s", err)
+		return nil, fmt.Errorf("unpacking arguments for Image: %s", err)
 	}
 
-	w := &Root{}
+	w := &Image{}
 
-	if child != nil {
-		childWidget, ok := child.(Widget)
-		if !ok {
-			return nil, fmt.Errorf(
-				"invalid type for child: %s (expected Widget)",
-				child.Type(),
-			)
-		}
-		w.Child = childWidget.AsRenderWidget()
-		w.starlarkChild = child
-	}
+	w.Src = src.GoString()
 
-	if val, err := starlark.AsInt32(delay); err == nil {
-		w.Delay = int32(val)
+	w.Width = int(width.BigInt().Int64())
+
+	w.Height = int(height.BigInt().Int64())
+
+	w.Resample = resample.GoString()
+
+	w.Fit = fit.GoString()
+
+	w.starlarkCrop = crop
+	if val, err := RectFromStarlark(crop); err == nil {
+		w.Crop = val
 	} else {
 		return nil, err
 	}
 
-	if val, err := starlark.AsInt32(max_age); err == nil {
-		w.MaxAge = int32(val)
-	} else {
-		return nil, err
+	if brightness != nil {
+		w.starlarkBrightness = brightness
+		if val, ok := starlark.AsFloat(w.starlarkBrightness); ok {
+			w.Brightness = val
+		} else {
+			return nil, fmt.Errorf("expected number, but got: %s", w.starlarkBrightness.String())
+		}
 	}
 
-	w.ShowFullAnimation = bool(show_full_animation)
+	if contrast != nil {
+		w.starlarkContrast = contrast
+		if val, ok := starlark.AsFloat(w.starlarkContrast); ok {
+			w.Contrast = val
+		} else {
+			return nil, fmt.Errorf("expected number, but got: %s", w.starlarkContrast.String())
+		}
+	}
+
+	w.Grayscale = bool(grayscale)
+
+	w.Dither = int(dither.BigInt().Int64())
+
+	w.size = starlark.NewBuiltin("size", imageSize)
+
+	w.frame_count = starlark.NewBuiltin("frame_count", imageFrameCount)
+
+	if err := w.Init(); err != nil {
+		return nil, err
+	}
 
 	return w, nil
 }
 
-func (w *Root) AsRenderRoot() render.Root {
-	return w.Root
+func (w *Image) AsRenderWidget() render.Widget {
+	return &w.Image
 }
 
-func (w *Root) AttrNames() []string {
+func (w *Image) AttrNames() []string {
 	return []string{
-		"child", "delay", "max_age", "show_full_animation",
+		"src", "width", "height", "delay", "resample", "fit", "crop", "brightness", "contrast", "grayscale", "dither",
+	}
+}
+
+func (w *Image) Attr(name string) (starlark.Value, error) {
+	switch name {
+
+	case "src":
+
+		return starlark.String(w.Src), nil
+
+	case "width":
+
+		return starlark.MakeInt(int(w.Width)), nil
+
+	case "height":
+
+		return starlark.MakeInt(int(w.Height)), nil
+
+	case "delay":
+
+		return starlark.MakeInt(int(w.Delay)), nil
+
+	case "resample":
+
+		return starlark.String(w.Resample), nil
+
+	case "fit":
+
+		return starlark.String(w.Fit), nil
+
+	case "crop":
+
+		return w.starlarkCrop, nil
+
+	case "brightness":
+
+		if w.starlarkBrightness == nil {
+			return starlark.Float(w.Brightness), nil
+		}
+		return w.starlarkBrightness, nil
+
+	case "contrast":
+
+		if w.starlarkContrast == nil {
+			return starlark.Float(w.Contrast), nil
+		}
+		return w.starlarkContrast, nil
+
+	case "grayscale":
+
+		return starlark.Bool(w.Grayscale), nil
+
+	case "dither":
+
+		return starlark.MakeInt(int(w.Dither)), nil
+
+	case "size":
+		return w.size.BindReceiver(w), nil
+
+	case "frame_count":
+		return w.frame_count.BindReceiver(w), nil
+
+	default:
+		return nil, nil
 	}
 }
 
-func (w *Root) Attr(name string) (starlark.Value, error) {
-	switch name {
+func (w *Image) String() string       { return "Image(...)" }
+func (w *Image) Type() string         { return "Image" }
+func (w *Image) Freeze()              {}
+func (w *Image) Truth() starlark.Bool { return true }
+
+func (w *Image) Hash() (uint32, error) {
+	sum, err := hashstructure.Hash(w, hashstructure.FormatV2, nil)
+	return uint32(sum), err
+}
+
+func imageSize(
+	thread *starlark.Thread,
+	b *starlark.Builtin,
+	args starlark.Tuple,
+	kwargs []starlark.Tuple) (starlark.Value, error) {
+
+	w := b.Receiver().(*Image)
+	width, height := w.Size()
+
+	return starlark.Tuple([]starlark.Value{
+		starlark.MakeInt(width),
+		starlark.MakeInt(height),
+	}), nil
+}
+
+func imageFrameCount(
+	thread *starlark.Thread,
+	b *starlark.Builtin,
+	args starlark.Tuple,
+	kwargs []starlark.Tuple) (starlark.Value, error) {
+
+	w := b.Receiver().(*Image)
+	count := w.FrameCount()
+
+	return starlark.MakeInt(count), nil
+}
+
+type Label struct {
+	render.Label
+
+	starlarkFallback *starlark.List
+
+	starlarkColor starlark.String
+}
+
+func newLabel(
+	thread *starlark.Thread,
+	_ *starlark.Builtin,
+	args starlark.Tuple,
+	kwargs []starlark.Tuple,
+) (starlark.Value, error) {
+
+	var (
+		x           starlark.Int
+		y           starlark.Int
+		content     starlark.String
+		font        starlark.String
+		fallback    *starlark.List
+		color       starlark.String
+		start_frame starlark.Int
+		end_frame   starlark.Int
+	)
+
+	if err := starlark.UnpackArgs(
+		"Label",
+		args, kwargs,
+		"x", &x,
+		"y", &y,
+		"content", &content,
+		"font?", &font,
+		"fallback?", &fallback,
+		"color?", &color,
+		"start_frame?", &start_frame,
+		"end_frame?", &end_frame,
+	); err != nil {
+		return nil, fmt.Errorf("unpacking arguments for Label: %s", err)
+	}
+
+	w := &Label{}
+
+	w.X = int(x.BigInt().Int64())
+
+	w.Y = int(y.BigInt().Int64())
+
+	w.Content = content.GoString()
+
+	w.Font = font.GoString()
+
+	if fallback != nil {
+		w.starlarkFallback = fallback
+		if val, err := StringsFromStarlark(fallback); err == nil {
+			w.Fallback = val
+		} else {
+			return nil, err
+		}
+	}
+
+	w.starlarkColor = color
+	if color.Len() > 0 {
+		c, err := render.ParseColor(color.GoString())
+		if err != nil {
+			return nil, fmt.Errorf("color is not a valid hex string: %s", color.String())
+		}
+		w.Color = c
+	}
+
+	w.StartFrame = int(start_frame.BigInt().Int64())
+
+	w.EndFrame = int(end_frame.BigInt().Int64())
+
+	return w, nil
+}
+
+func (w *Label) AttrNames() []string {
+	return []string{
+		"x", "y", "content", "font", "fallback", "color", "start_frame", "end_frame",
+	}
+}
+
+func (w *Label) Attr(name string) (starlark.Value, error) {
+	switch name {
+
+	case "x":
+
+		return starlark.MakeInt(int(w.X)), nil
+
+	case "y":
+
+		return starlark.MakeInt(int(w.Y)), nil
+
+	case "content":
+
+		return starlark.String(w.Content), nil
+
+	case "font":
+
+		return starlark.String(w.Font), nil
+
+	case "fallback":
+
+		if w.starlarkFallback == nil {
+			return starlark.None, nil
+		}
+		return w.starlarkFallback, nil
+
+	case "color":
+
+		return w.starlarkColor, nil
+
+	case "start_frame":
+
+		return starlark.MakeInt(int(w.StartFrame)), nil
+
+	case "end_frame":
+
+		return starlark.MakeInt(int(w.EndFrame)), nil
+
+	default:
+		return nil, nil
+	}
+}
+
+func (w *Label) String() string       { return "Label(...)" }
+func (w *Label) Type() string         { return "Label" }
+func (w *Label) Freeze()              {}
+func (w *Label) Truth() starlark.Bool { return true }
+
+func (w *Label) Hash() (uint32, error) {
+	sum, err := hashstructure.Hash(w, hashstructure.FormatV2, nil)
+	return uint32(sum), err
+}
+
+type Line struct {
+	render.Line
+
+	starlarkColor starlark.String
+}
+
+func newLine(
+	thread *starlark.Thread,
+	_ *starlark.Builtin,
+	args starlark.Tuple,
+	kwargs []starlark.Tuple,
+) (starlark.Value, error) {
+
+	var (
+		x0          starlark.Int
+		y0          starlark.Int
+		x1          starlark.Int
+		y1          starlark.Int
+		color       starlark.String
+		start_frame starlark.Int
+		end_frame   starlark.Int
+	)
+
+	if err := starlark.UnpackArgs(
+		"Line",
+		args, kwargs,
+		"x0", &x0,
+		"y0", &y0,
+		"x1", &x1,
+		"y1", &y1,
+		"color?", &color,
+		"start_frame?", &start_frame,
+		"end_frame?", &end_frame,
+	); err != nil {
+		return nil, fmt.Errorf("unpacking arguments for Line: %s", err)
+	}
+
+	w := &Line{}
+
+	w.X0 = int(x0.BigInt().Int64())
+
+	w.Y0 = int(y0.BigInt().Int64())
+
+	w.X1 = int(x1.BigInt().Int64())
+
+	w.Y1 = int(y1.BigInt().Int64())
+
+	w.starlarkColor = color
+	if color.Len() > 0 {
+		c, err := render.ParseColor(color.GoString())
+		if err != nil {
+			return nil, fmt.Errorf("color is not a valid hex string: %s", color.String())
+		}
+		w.Color = c
+	}
+
+	w.StartFrame = int(start_frame.BigInt().Int64())
+
+	w.EndFrame = int(end_frame.BigInt().Int64())
+
+	return w, nil
+}
+
+func (w *Line) AttrNames() []string {
+	return []string{
+		"x0", "y0", "x1", "y1", "color", "start_frame", "end_frame",
+	}
+}
+
+func (w *Line) Attr(name string) (starlark.Value, error) {
+	switch name {
+
+	case "x0":
+
+		return starlark.MakeInt(int(w.X0)), nil
+
+	case "y0":
+
+		return starlark.MakeInt(int(w.Y0)), nil
+
+	case "x1":
+
+		return starlark.MakeInt(int(w.X1)), nil
+
+	case "y1":
+
+		return starlark.MakeInt(int(w.Y1)), nil
+
+	case "color":
+
+		return w.starlarkColor, nil
+
+	case "start_frame":
+
+		return starlark.MakeInt(int(w.StartFrame)), nil
+
+	case "end_frame":
+
+		return starlark.MakeInt(int(w.EndFrame)), nil
+
+	default:
+		return nil, nil
+	}
+}
+
+func (w *Line) String() string       { return "Line(...)" }
+func (w *Line) Type() string         { return "Line" }
+func (w *Line) Freeze()              {}
+func (w *Line) Truth() starlark.Bool { return true }
+
+func (w *Line) Hash() (uint32, error) {
+	sum, err := hashstructure.Hash(w, hashstructure.FormatV2, nil)
+	return uint32(sum), err
+}
+
+type Marquee struct {
+	Widget
+
+	render.Marquee
+
+	starlarkChild starlark.Value
+
+	frame_count *starlark.Builtin
+}
+
+func newMarquee(
+	thread *starlark.Thread,
+	_ *starlark.Builtin,
+	args starlark.Tuple,
+	kwargs []starlark.Tuple,
+) (starlark.Value, error) {
+
+	var (
+		child            starlark.Value
+		width            starlark.Int
+		height           starlark.Int
+		offset_start     starlark.Int
+		offset_end       starlark.Int
+		scroll_direction starlark.String
+		align            starlark.String
+		delay            starlark.Int
+	)
+
+	if err := starlark.UnpackArgs(
+		"Marquee",
+		args, kwargs,
+		"child", &child,
+		"width?", &width,
+		"height?", &height,
+		"offset_start?", &offset_start,
+		"offset_end?", &offset_end,
+		"scroll_direction?", &scroll_direction,
+		"align?", &align,
+		"delay?", &delay,
+	); err != nil {
+		return nil, fmt.Errorf("unpacking arguments for Marquee: %s", err)
+	}
+
+	w := &Marquee{}
+
+	if child != nil {
+		childWidget, ok := child.(Widget)
+		if !ok {
+			return nil, fmt.Errorf(
+				"invalid type for child: %s (expected Widget)",
+				child.Type(),
+			)
+		}
+		w.Child = childWidget.AsRenderWidget()
+		w.starlarkChild = child
+	}
+
+	w.Width = int(width.BigInt().Int64())
+
+	w.Height = int(height.BigInt().Int64())
+
+	w.OffsetStart = int(offset_start.BigInt().Int64())
+
+	w.OffsetEnd = int(offset_end.BigInt().Int64())
+
+	w.ScrollDirection = scroll_direction.GoString()
+
+	w.Align = align.GoString()
+
+	w.Delay = int(delay.BigInt().Int64())
+
+	w.frame_count = starlark.NewBuiltin("frame_count", marqueeFrameCount)
+
+	return w, nil
+}
+
+func (w *Marquee) AsRenderWidget() render.Widget {
+	return &w.Marquee
+}
+
+func (w *Marquee) AttrNames() []string {
+	return []string{
+		"child", "width", "height", "offset_start", "offset_end", "scroll_direction", "align", "delay",
+	}
+}
+
+func (w *Marquee) Attr(name string) (starlark.Value, error) {
+	switch name {
+
+	case "child":
+
+		return w.starlarkChild, nil
+
+	case "width":
+
+		return starlark.MakeInt(int(w.Width)), nil
+
+	case "height":
+
+		return starlark.MakeInt(int(w.Height)), nil
+
+	case "offset_start":
+
+		return starlark.MakeInt(int(w.OffsetStart)), nil
+
+	case "offset_end":
+
+		return starlark.MakeInt(int(w.OffsetEnd)), nil
+
+	case "scroll_direction":
+
+		return starlark.String(w.ScrollDirection), nil
+
+	case "align":
+
+		return starlark.String(w.Align), nil
+
+	case "delay":
+
+		return starlark.MakeInt(int(w.Delay)), nil
+
+	case "frame_count":
+		return w.frame_count.BindReceiver(w), nil
+
+	default:
+		return nil, nil
+	}
+}
+
+func (w *Marquee) String() string       { return "Marquee(...)" }
+func (w *Marquee) Type() string         { return "Marquee" }
+func (w *Marquee) Freeze()              {}
+func (w *Marquee) Truth() starlark.Bool { return true }
+
+func (w *Marquee) Hash() (uint32, error) {
+	sum, err := hashstructure.Hash(w, hashstructure.FormatV2, nil)
+	return uint32(sum), err
+}
+
+func marqueeFrameCount(
+	thread *starlark.Thread,
+	b *starlark.Builtin,
+	args starlark.Tuple,
+	kwargs []starlark.Tuple) (starlark.Value, error) {
+
+	w := b.Receiver().(*Marquee)
+	count := w.FrameCount()
+
+	return starlark.MakeInt(count), nil
+}
+
+type Padding struct {
+	Widget
+
+	render.Padding
+
+	starlarkChild starlark.Value
+
+	starlarkPad starlark.Value
+
+	starlarkColor starlark.String
+
+	frame_count *starlark.Builtin
+}
+
+func newPadding(
+	thread *starlark.Thread,
+	_ *starlark.Builtin,
+	args starlark.Tuple,
+	kwargs []starlark.Tuple,
+) (starlark.Value, error) {
+
+	var (
+		child    starlark.Value
+		pad      starlark.Value
+		expanded starlark.Bool
+		color    starlark.String
+	)
+
+	if err := starlark.UnpackArgs(
+		"Padding",
+		args, kwargs,
+		"child", &child,
+		"pad?", &pad,
+		"expanded?", &expanded,
+		"color?", &color,
+	); err != nil {
+		return nil, fmt.Errorf("unpacking arguments for Padding: %s", err)
+	}
+
+	w := &Padding{}
+
+	if child != nil {
+		childWidget, ok := child.(Widget)
+		if !ok {
+			return nil, fmt.Errorf(
+				"invalid type for child: %s (expected Widget)",
+				child.Type(),
+			)
+		}
+		w.Child = childWidget.AsRenderWidget()
+		w.starlarkChild = child
+	}
+
+	w.starlarkPad = pad
+	switch padVal := pad.(type) {
+	case starlark.Int:
+		padInt := int(padVal.BigInt().Int64())
+		w.Pad.Left = padInt
+		w.Pad.Top = padInt
+		w.Pad.Right = padInt
+		w.Pad.Bottom = padInt
+	case starlark.Tuple:
+		padList := []starlark.Value(padVal)
+		if len(padList) != 4 {
+			return nil, fmt.Errorf(
+				"pad tuple must hold 4 elements (left, top, right, bottom), found %d",
+				len(padList),
+			)
+		}
+		padListInt := make([]starlark.Int, 4)
+		for i := 0; i < 4; i++ {
+			pi, ok := padList[i].(starlark.Int)
+			if !ok {
+				return nil, fmt.Errorf("pad element %d is not int", i)
+			}
+			padListInt[i] = pi
+		}
+		w.Pad.Left = int(padListInt[0].BigInt().Int64())
+		w.Pad.Top = int(padListInt[1].BigInt().Int64())
+		w.Pad.Right = int(padListInt[2].BigInt().Int64())
+		w.Pad.Bottom = int(padListInt[3].BigInt().Int64())
+	default:
+		return nil, fmt.Errorf("pad must be int or 4-tuple of int")
+	}
+
+	w.Expanded = bool(expanded)
+
+	w.starlarkColor = color
+	if color.Len() > 0 {
+		c, err := render.ParseColor(color.GoString())
+		if err != nil {
+			return nil, fmt.Errorf("color is not a valid hex string: %s", color.String())
+		}
+		w.Color = c
+	}
+
+	w.frame_count = starlark.NewBuiltin("frame_count", paddingFrameCount)
+
+	return w, nil
+}
+
+func (w *Padding) AsRenderWidget() render.Widget {
+	return &w.Padding
+}
+
+func (w *Padding) AttrNames() []string {
+	return []string{
+		"child", "pad", "expanded", "color",
+	}
+}
+
+func (w *Padding) Attr(name string) (starlark.Value, error) {
+	switch name {
+
+	case "child":
+
+		return w.starlarkChild, nil
+
+	case "pad":
+
+		return w.starlarkPad, nil
+
+	case "expanded":
+
+		return starlark.Bool(w.Expanded), nil
+
+	case "color":
+
+		return w.starlarkColor, nil
+
+	case "frame_count":
+		return w.frame_count.BindReceiver(w), nil
+
+	default:
+		return nil, nil
+	}
+}
+
+func (w *Padding) String() string       { return "Padding(...)" }
+func (w *Padding) Type() string         { return "Padding" }
+func (w *Padding) Freeze()              {}
+func (w *Padding) Truth() starlark.Bool { return true }
+
+func (w *Padding) Hash() (uint32, error) {
+	sum, err := hashstructure.Hash(w, hashstructure.FormatV2, nil)
+	return uint32(sum), err
+}
+
+func paddingFrameCount(
+	thread *starlark.Thread,
+	b *starlark.Builtin,
+	args starlark.Tuple,
+	kwargs []starlark.Tuple) (starlark.Value, error) {
+
+	w := b.Receiver().(*Padding)
+	count := w.FrameCount()
+
+	return starlark.MakeInt(count), nil
+}
+
+type PieChart struct {
+	Widget
+
+	render.PieChart
+
+	starlarkColors *starlark.List
+
+	starlarkWeights *starlark.List
+
+	frame_count *starlark.Builtin
+}
+
+func newPieChart(
+	thread *starlark.Thread,
+	_ *starlark.Builtin,
+	args starlark.Tuple,
+	kwargs []starlark.Tuple,
+) (starlark.Value, error) {
+
+	var (
+		colors   *starlark.List
+		weights  *starlark.List
+		diameter starlark.Int
+	)
+
+	if err := starlark.UnpackArgs(
+		"PieChart",
+		args, kwargs,
+		"colors", &colors,
+		"weights", &weights,
+		"diameter", &diameter,
+	); err != nil {
+		return nil, fmt.Errorf("unpacking arguments for PieChart: %s", err)
+	}
+
+	w := &PieChart{}
+
+	if colors != nil {
+		w.starlarkColors = colors
+		if val, err := ColorSeriesFromStarlark(colors); err == nil {
+			w.Colors = val
+		} else {
+			return nil, err
+		}
+	}
+
+	if weights != nil {
+		w.starlarkWeights = weights
+		if val, err := WeightsFromStarlark(weights); err == nil {
+			w.Weights = val
+		} else {
+			return nil, err
+		}
+	}
+
+	w.Diameter = int(diameter.BigInt().Int64())
+
+	w.frame_count = starlark.NewBuiltin("frame_count", piechartFrameCount)
+
+	return w, nil
+}
+
+func (w *PieChart) AsRenderWidget() render.Widget {
+	return &w.PieChart
+}
+
+func (w *PieChart) AttrNames() []string {
+	return []string{
+		"colors", "weights", "diameter",
+	}
+}
+
+func (w *PieChart) Attr(name string) (starlark.Value, error) {
+	switch name {
+
+	case "colors":
+
+		if w.starlarkColors == nil {
+			return starlark.None, nil
+		}
+		return w.starlarkColors, nil
+
+	case "weights":
+
+		if w.starlarkWeights == nil {
+			return starlark.None, nil
+		}
+		return w.starlarkWeights, nil
+
+	case "diameter":
+
+		return starlark.MakeInt(int(w.Diameter)), nil
+
+	case "frame_count":
+		return w.frame_count.BindReceiver(w), nil
+
+	default:
+		return nil, nil
+	}
+}
+
+func (w *PieChart) String() string       { return "PieChart(...)" }
+func (w *PieChart) Type() string         { return "PieChart" }
+func (w *PieChart) Freeze()              {}
+func (w *PieChart) Truth() starlark.Bool { return true }
+
+func (w *PieChart) Hash() (uint32, error) {
+	sum, err := hashstructure.Hash(w, hashstructure.FormatV2, nil)
+	return uint32(sum), err
+}
+
+func piechartFrameCount(
+	thread *starlark.Thread,
+	b *starlark.Builtin,
+	args starlark.Tuple,
+	kwargs []starlark.Tuple) (starlark.Value, error) {
+
+	w := b.Receiver().(*PieChart)
+	count := w.FrameCount()
+
+	return starlark.MakeInt(count), nil
+}
+
+type Pixel struct {
+	render.Pixel
+
+	starlarkColor starlark.String
+}
+
+func newPixel(
+	thread *starlark.Thread,
+	_ *starlark.Builtin,
+	args starlark.Tuple,
+	kwargs []starlark.Tuple,
+) (starlark.Value, error) {
+
+	var (
+		x           starlark.Int
+		y           starlark.Int
+		color       starlark.String
+		start_frame starlark.Int
+		end_frame   starlark.Int
+	)
+
+	if err := starlark.UnpackArgs(
+		"Pixel",
+		args, kwargs,
+		"x", &x,
+		"y", &y,
+		"color?", &color,
+		"start_frame?", &start_frame,
+		"end_frame?", &end_frame,
+	); err != nil {
+		return nil, fmt.Errorf("unpacking arguments for Pixel: %s", err)
+	}
+
+	w := &Pixel{}
+
+	w.X = int(x.BigInt().Int64())
+
+	w.Y = int(y.BigInt().Int64())
+
+	w.starlarkColor = color
+	if color.Len() > 0 {
+		c, err := render.ParseColor(color.GoString())
+		if err != nil {
+			return nil, fmt.Errorf("color is not a valid hex string: %s", color.String())
+		}
+		w.Color = c
+	}
+
+	w.StartFrame = int(start_frame.BigInt().Int64())
+
+	w.EndFrame = int(end_frame.BigInt().Int64())
+
+	return w, nil
+}
+
+func (w *Pixel) AttrNames() []string {
+	return []string{
+		"x", "y", "color", "start_frame", "end_frame",
+	}
+}
+
+func (w *Pixel) Attr(name string) (starlark.Value, error) {
+	switch name {
+
+	case "x":
+
+		return starlark.MakeInt(int(w.X)), nil
+
+	case "y":
+
+		return starlark.MakeInt(int(w.Y)), nil
+
+	case "color":
+
+		return w.starlarkColor, nil
+
+	case "start_frame":
+
+		return starlark.MakeInt(int(w.StartFrame)), nil
+
+	case "end_frame":
+
+		return starlark.MakeInt(int(w.EndFrame)), nil
+
+	default:
+		return nil, nil
+	}
+}
+
+func (w *Pixel) String() string       { return "Pixel(...)" }
+func (w *Pixel) Type() string         { return "Pixel" }
+func (w *Pixel) Freeze()              {}
+func (w *Pixel) Truth() starlark.Bool { return true }
+
+func (w *Pixel) Hash() (uint32, error) {
+	sum, err := hashstructure.Hash(w, hashstructure.FormatV2, nil)
+	return uint32(sum), err
+}
+
+type Plot struct {
+	Widget
+
+	render.Plot
+
+	starlarkData *starlark.List
+
+	starlarkColor starlark.String
+
+	starlarkColorInverted starlark.String
+
+	starlarkXLim starlark.Tuple
+
+	starlarkYLim starlark.Tuple
+
+	starlarkFillColor starlark.String
+
+	starlarkFillColorInverted starlark.String
+
+	starlarkSeries *starlark.List
+
+	starlarkAxisColor starlark.String
+
+	starlarkXTicks starlark.Value
+
+	starlarkYTicks starlark.Value
+
+	starlarkGridColor starlark.String
+
+	frame_count *starlark.Builtin
+}
+
+func newPlot(
+	thread *starlark.Thread,
+	_ *starlark.Builtin,
+	args starlark.Tuple,
+	kwargs []starlark.Tuple,
+) (starlark.Value, error) {
+
+	var (
+		data                *starlark.List
+		width               starlark.Int
+		height              starlark.Int
+		color               starlark.String
+		color_inverted      starlark.String
+		x_lim               starlark.Tuple
+		y_lim               starlark.Tuple
+		fill                starlark.Bool
+		chart_type          starlark.String
+		fill_color          starlark.String
+		fill_color_inverted starlark.String
+		series              *starlark.List
+		axes                starlark.Bool
+		axis_color          starlark.String
+		x_ticks             starlark.Value
+		y_ticks             starlark.Value
+		grid                starlark.Bool
+		grid_color          starlark.String
+	)
+
+	if err := starlark.UnpackArgs(
+		"Plot",
+		args, kwargs,
+		"data", &data,
+		"width", &width,
+		"height", &height,
+		"color?", &color,
+		"color_inverted?", &color_inverted,
+		"x_lim?", &x_lim,
+		"y_lim?", &y_lim,
+		"fill?", &fill,
+		"chart_type?", &chart_type,
+		"fill_color?", &fill_color,
+		"fill_color_inverted?", &fill_color_inverted,
+		"series?", &series,
+		"axes?", &axes,
+		"axis_color?", &axis_color,
+		"x_ticks?", &x_ticks,
+		"y_ticks?", &y_ticks,
+		"grid?", &grid,
+		"grid_color?", &grid_color,
+	); err != nil {
+		return nil, fmt.Errorf("unpacking arguments for Plot: %s", err)
+	}
+
+	w := &Plot{}
+
+	w.starlarkData = data
+	if val, err := DataSeriesFromStarlark(data); err == nil {
+		w.Data = val
+	} else {
+		return nil, err
+	}
+
+	w.Width = int(width.BigInt().Int64())
+
+	w.Height = int(height.BigInt().Int64())
+
+	w.starlarkColor = color
+	if color.Len() > 0 {
+		c, err := render.ParseColor(color.GoString())
+		if err != nil {
+			return nil, fmt.Errorf("color is not a valid hex string: %s", color.String())
+		}
+		w.Color = c
+	}
+
+	w.starlarkColorInverted = color_inverted
+	if color_inverted.Len() > 0 {
+		c, err := render.ParseColor(color_inverted.GoString())
+		if err != nil {
+			return nil, fmt.Errorf("color_inverted is not a valid hex string: %s", color_inverted.String())
+		}
+		w.ColorInverted = c
+	}
+
+	w.starlarkXLim = x_lim
+	if val, err := DataPointFromStarlark(x_lim); err == nil {
+		w.XLim = val
+	} else {
+		return nil, err
+	}
+
+	w.starlarkYLim = y_lim
+	if val, err := DataPointFromStarlark(y_lim); err == nil {
+		w.YLim = val
+	} else {
+		return nil, err
+	}
+
+	w.Fill = bool(fill)
+
+	w.ChartType = chart_type.GoString()
+
+	w.starlarkFillColor = fill_color
+	if fill_color.Len() > 0 {
+		c, err := render.ParseColor(fill_color.GoString())
+		if err != nil {
+			return nil, fmt.Errorf("fill_color is not a valid hex string: %s", fill_color.String())
+		}
+		w.FillColor = c
+	}
+
+	w.starlarkFillColorInverted = fill_color_inverted
+	if fill_color_inverted.Len() > 0 {
+		c, err := render.ParseColor(fill_color_inverted.GoString())
+		if err != nil {
+			return nil, fmt.Errorf("fill_color_inverted is not a valid hex string: %s", fill_color_inverted.String())
+		}
+		w.FillColorInverted = c
+	}
+
+	if series != nil {
+		w.starlarkSeries = series
+		for i := 0; i < series.Len(); i++ {
+			val, ok := series.Index(i).(*Series)
+			if !ok {
+				return nil, fmt.Errorf("invalid type for series: %s (expected Series)", series.Index(i).Type())
+			}
+			w.Series = append(w.Series, val.Series)
+		}
+	}
+
+	w.Axes = bool(axes)
+
+	w.starlarkAxisColor = axis_color
+	if axis_color.Len() > 0 {
+		c, err := render.ParseColor(axis_color.GoString())
+		if err != nil {
+			return nil, fmt.Errorf("axis_color is not a valid hex string: %s", axis_color.String())
+		}
+		w.AxisColor = c
+	}
+
+	if x_ticks != nil {
+		w.starlarkXTicks = x_ticks
+		if val, ok := starlark.AsFloat(w.starlarkXTicks); ok {
+			w.XTicks = val
+		} else {
+			return nil, fmt.Errorf("expected number, but got: %s", w.starlarkXTicks.String())
+		}
+	}
+
+	if y_ticks != nil {
+		w.starlarkYTicks = y_ticks
+		if val, ok := starlark.AsFloat(w.starlarkYTicks); ok {
+			w.YTicks = val
+		} else {
+			return nil, fmt.Errorf("expected number, but got: %s", w.starlarkYTicks.String())
+		}
+	}
+
+	w.Grid = bool(grid)
+
+	w.starlarkGridColor = grid_color
+	if grid_color.Len() > 0 {
+		c, err := render.ParseColor(grid_color.GoString())
+		if err != nil {
+			return nil, fmt.Errorf("grid_color is not a valid hex string: %s", grid_color.String())
+		}
+		w.GridColor = c
+	}
+
+	w.frame_count = starlark.NewBuiltin("frame_count", plotFrameCount)
+
+	return w, nil
+}
+
+func (w *Plot) AsRenderWidget() render.Widget {
+	return &w.Plot
+}
+
+func (w *Plot) AttrNames() []string {
+	return []string{
+		"data", "width", "height", "color", "color_inverted", "x_lim", "y_lim", "fill", "chart_type", "fill_color", "fill_color_inverted", "series", "axes", "axis_color", "x_ticks", "y_ticks", "grid", "grid_color",
+	}
+}
+
+func (w *Plot) Attr(name string) (starlark.Value, error) {
+	switch name {
+
+	case "data":
+
+		if w.starlarkData == nil {
+			return starlark.None, nil
+		}
+		return w.starlarkData, nil
+
+	case "width":
+
+		return starlark.MakeInt(int(w.Width)), nil
+
+	case "height":
+
+		return starlark.MakeInt(int(w.Height)), nil
+
+	case "color":
+
+		return w.starlarkColor, nil
+
+	case "color_inverted":
+
+		return w.starlarkColorInverted, nil
+
+	case "x_lim":
+
+		return w.starlarkXLim, nil
+
+	case "y_lim":
+
+		return w.starlarkYLim, nil
+
+	case "fill":
+
+		return starlark.Bool(w.Fill), nil
+
+	case "chart_type":
+
+		return starlark.String(w.ChartType), nil
+
+	case "fill_color":
+
+		return w.starlarkFillColor, nil
+
+	case "fill_color_inverted":
+
+		return w.starlarkFillColorInverted, nil
+
+	case "series":
+
+		if w.starlarkSeries == nil {
+			return starlark.None, nil
+		}
+		return w.starlarkSeries, nil
+
+	case "axes":
+
+		return starlark.Bool(w.Axes), nil
+
+	case "axis_color":
+
+		return w.starlarkAxisColor, nil
+
+	case "x_ticks":
+
+		if w.starlarkXTicks == nil {
+			return starlark.Float(w.XTicks), nil
+		}
+		return w.starlarkXTicks, nil
+
+	case "y_ticks":
+
+		if w.starlarkYTicks == nil {
+			return starlark.Float(w.YTicks), nil
+		}
+		return w.starlarkYTicks, nil
+
+	case "grid":
+
+		return starlark.Bool(w.Grid), nil
+
+	case "grid_color":
+
+		return w.starlarkGridColor, nil
+
+	case "frame_count":
+		return w.frame_count.BindReceiver(w), nil
+
+	default:
+		return nil, nil
+	}
+}
+
+func (w *Plot) String() string       { return "Plot(...)" }
+func (w *Plot) Type() string         { return "Plot" }
+func (w *Plot) Freeze()              {}
+func (w *Plot) Truth() starlark.Bool { return true }
+
+func (w *Plot) Hash() (uint32, error) {
+	sum, err := hashstructure.Hash(w, hashstructure.FormatV2, nil)
+	return uint32(sum), err
+}
+
+func plotFrameCount(
+	thread *starlark.Thread,
+	b *starlark.Builtin,
+	args starlark.Tuple,
+	kwargs []starlark.Tuple) (starlark.Value, error) {
+
+	w := b.Receiver().(*Plot)
+	count := w.FrameCount()
+
+	return starlark.MakeInt(count), nil
+}
+
+type Polygon struct {
+	render.Polygon
+
+	starlarkPoints *starlark.List
+
+	starlarkColor starlark.String
+}
+
+func newPolygon(
+	thread *starlark.Thread,
+	_ *starlark.Builtin,
+	args starlark.Tuple,
+	kwargs []starlark.Tuple,
+) (starlark.Value, error) {
+
+	var (
+		points      *starlark.List
+		color       starlark.String
+		start_frame starlark.Int
+		end_frame   starlark.Int
+	)
+
+	if err := starlark.UnpackArgs(
+		"Polygon",
+		args, kwargs,
+		"points", &points,
+		"color?", &color,
+		"start_frame?", &start_frame,
+		"end_frame?", &end_frame,
+	); err != nil {
+		return nil, fmt.Errorf("unpacking arguments for Polygon: %s", err)
+	}
+
+	w := &Polygon{}
+
+	w.starlarkPoints = points
+	if val, err := DataSeriesFromStarlark(points); err == nil {
+		w.Points = val
+	} else {
+		return nil, err
+	}
+
+	w.starlarkColor = color
+	if color.Len() > 0 {
+		c, err := render.ParseColor(color.GoString())
+		if err != nil {
+			return nil, fmt.Errorf("color is not a valid hex string: %s", color.String())
+		}
+		w.Color = c
+	}
+
+	w.StartFrame = int(start_frame.BigInt().Int64())
+
+	w.EndFrame = int(end_frame.BigInt().Int64())
+
+	return w, nil
+}
+
+func (w *Polygon) AttrNames() []string {
+	return []string{
+		"points", "color", "start_frame", "end_frame",
+	}
+}
+
+func (w *Polygon) Attr(name string) (starlark.Value, error) {
+	switch name {
+
+	case "points":
+
+		if w.starlarkPoints == nil {
+			return starlark.None, nil
+		}
+		return w.starlarkPoints, nil
+
+	case "color":
+
+		return w.starlarkColor, nil
+
+	case "start_frame":
+
+		return starlark.MakeInt(int(w.StartFrame)), nil
+
+	case "end_frame":
+
+		return starlark.MakeInt(int(w.EndFrame)), nil
+
+	default:
+		return nil, nil
+	}
+}
+
+func (w *Polygon) String() string       { return "Polygon(...)" }
+func (w *Polygon) Type() string         { return "Polygon" }
+func (w *Polygon) Freeze()              {}
+func (w *Polygon) Truth() starlark.Bool { return true }
+
+func (w *Polygon) Hash() (uint32, error) {
+	sum, err := hashstructure.Hash(w, hashstructure.FormatV2, nil)
+	return uint32(sum), err
+}
+
+type Rect struct {
+	render.Rect
+
+	starlarkColor starlark.String
+}
+
+func newRect(
+	thread *starlark.Thread,
+	_ *starlark.Builtin,
+	args starlark.Tuple,
+	kwargs []starlark.Tuple,
+) (starlark.Value, error) {
+
+	var (
+		x           starlark.Int
+		y           starlark.Int
+		width       starlark.Int
+		height      starlark.Int
+		color       starlark.String
+		fill        starlark.Bool
+		start_frame starlark.Int
+		end_frame   starlark.Int
+	)
+
+	if err := starlark.UnpackArgs(
+		"Rect",
+		args, kwargs,
+		"x", &x,
+		"y", &y,
+		"width", &width,
+		"height", &height,
+		"color?", &color,
+		"fill?", &fill,
+		"start_frame?", &start_frame,
+		"end_frame?", &end_frame,
+	); err != nil {
+		return nil, fmt.Errorf("unpacking arguments for Rect: %s", err)
+	}
+
+	w := &Rect{}
+
+	w.X = int(x.BigInt().Int64())
+
+	w.Y = int(y.BigInt().Int64())
+
+	w.Width = int(width.BigInt().Int64())
+
+	w.Height = int(height.BigInt().Int64())
+
+	w.starlarkColor = color
+	if color.Len() > 0 {
+		c, err := render.ParseColor(color.GoString())
+		if err != nil {
+			return nil, fmt.Errorf("color is not a valid hex string: %s", color.String())
+		}
+		w.Color = c
+	}
+
+	w.Fill = bool(fill)
+
+	w.StartFrame = int(start_frame.BigInt().Int64())
+
+	w.EndFrame = int(end_frame.BigInt().Int64())
+
+	return w, nil
+}
+
+func (w *Rect) AttrNames() []string {
+	return []string{
+		"x", "y", "width", "height", "color", "fill", "start_frame", "end_frame",
+	}
+}
+
+func (w *Rect) Attr(name string) (starlark.Value, error) {
+	switch name {
+
+	case "x":
+
+		return starlark.MakeInt(int(w.X)), nil
+
+	case "y":
+
+		return starlark.MakeInt(int(w.Y)), nil
+
+	case "width":
+
+		return starlark.MakeInt(int(w.Width)), nil
+
+	case "height":
+
+		return starlark.MakeInt(int(w.Height)), nil
+
+	case "color":
+
+		return w.starlarkColor, nil
+
+	case "fill":
+
+		return starlark.Bool(w.Fill), nil
+
+	case "start_frame":
+
+		return starlark.MakeInt(int(w.StartFrame)), nil
+
+	case "end_frame":
+
+		return starlark.MakeInt(int(w.EndFrame)), nil
+
+	default:
+		return nil, nil
+	}
+}
+
+func (w *Rect) String() string       { return "Rect(...)" }
+func (w *Rect) Type() string         { return "Rect" }
+func (w *Rect) Freeze()              {}
+func (w *Rect) Truth() starlark.Bool { return true }
+
+func (w *Rect) Hash() (uint32, error) {
+	sum, err := hashstructure.Hash(w, hashstructure.FormatV2, nil)
+	return uint32(sum), err
+}
+
+type RichText struct {
+	Widget
+
+	render.RichText
+
+	starlarkSpans *starlark.List
+
+	starlarkColor starlark.String
+
+	starlarkFallback *starlark.List
+
+	frame_count *starlark.Builtin
+}
+
+func newRichText(
+	thread *starlark.Thread,
+	_ *starlark.Builtin,
+	args starlark.Tuple,
+	kwargs []starlark.Tuple,
+) (starlark.Value, error) {
+
+	var (
+		spans       *starlark.List
+		font        starlark.String
+		color       starlark.String
+		width       starlark.Int
+		height      starlark.Int
+		linespacing starlark.Int
+		align       starlark.String
+		fallback    *starlark.List
+	)
+
+	if err := starlark.UnpackArgs(
+		"RichText",
+		args, kwargs,
+		"spans", &spans,
+		"font?", &font,
+		"color?", &color,
+		"width?", &width,
+		"height?", &height,
+		"linespacing?", &linespacing,
+		"align?", &align,
+		"fallback?", &fallback,
+	); err != nil {
+		return nil, fmt.Errorf("unpacking arguments for RichText: %s", err)
+	}
+
+	w := &RichText{}
+
+	w.starlarkSpans = spans
+	for i := 0; i < spans.Len(); i++ {
+		switch val := spans.Index(i).(type) {
+		case *Span:
+			w.Spans = append(w.Spans, val.Span)
+		case starlark.String:
+			w.Spans = append(w.Spans, render.Span{Content: val.GoString()})
+		default:
+			return nil, fmt.Errorf("invalid type for spans: %s (expected Span or string)", val.Type())
+		}
+	}
+
+	w.Font = font.GoString()
+
+	w.starlarkColor = color
+	if color.Len() > 0 {
+		c, err := render.ParseColor(color.GoString())
+		if err != nil {
+			return nil, fmt.Errorf("color is not a valid hex string: %s", color.String())
+		}
+		w.Color = c
+	}
+
+	w.Width = int(width.BigInt().Int64())
+
+	w.Height = int(height.BigInt().Int64())
+
+	w.LineSpacing = int(linespacing.BigInt().Int64())
+
+	w.Align = align.GoString()
+
+	if fallback != nil {
+		w.starlarkFallback = fallback
+		if val, err := StringsFromStarlark(fallback); err == nil {
+			w.Fallback = val
+		} else {
+			return nil, err
+		}
+	}
+
+	w.frame_count = starlark.NewBuiltin("frame_count", richtextFrameCount)
+
+	w.SetFontSource(threadFonts(thread))
+
+	if err := w.Init(); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+func (w *RichText) AsRenderWidget() render.Widget {
+	return &w.RichText
+}
+
+func (w *RichText) AttrNames() []string {
+	return []string{
+		"spans", "font", "color", "width", "height", "linespacing", "align", "fallback",
+	}
+}
+
+func (w *RichText) Attr(name string) (starlark.Value, error) {
+	switch name {
+
+	case "spans":
+
+		if w.starlarkSpans == nil {
+			return starlark.None, nil
+		}
+		return w.starlarkSpans, nil
+
+	case "font":
+
+		return starlark.String(w.Font), nil
+
+	case "color":
+
+		return w.starlarkColor, nil
+
+	case "width":
+
+		return starlark.MakeInt(int(w.Width)), nil
+
+	case "height":
+
+		return starlark.MakeInt(int(w.Height)), nil
+
+	case "linespacing":
+
+		return starlark.MakeInt(int(w.LineSpacing)), nil
+
+	case "align":
+
+		return starlark.String(w.Align), nil
+
+	case "fallback":
+
+		if w.starlarkFallback == nil {
+			return starlark.None, nil
+		}
+		return w.starlarkFallback, nil
+
+	case "frame_count":
+		return w.frame_count.BindReceiver(w), nil
+
+	default:
+		return nil, nil
+	}
+}
+
+func (w *RichText) String() string       { return "RichText(...)" }
+func (w *RichText) Type() string         { return "RichText" }
+func (w *RichText) Freeze()              {}
+func (w *RichText) Truth() starlark.Bool { return true }
+
+func (w *RichText) Hash() (uint32, error) {
+	sum, err := hashstructure.Hash(w, hashstructure.FormatV2, nil)
+	return uint32(sum), err
+}
+
+func richtextFrameCount(
+	thread *starlark.Thread,
+	b *starlark.Builtin,
+	args starlark.Tuple,
+	kwargs []starlark.Tuple) (starlark.Value, error) {
+
+	w := b.Receiver().(*RichText)
+	count := w.FrameCount()
+
+	return starlark.MakeInt(count), nil
+}
+
+type Root struct {
+	render.Root
+
+	starlarkChild starlark.Value
+}
+
+func newRoot(
+	thread *starlark.Thread,
+	_ *starlark.Builtin,
+	args starlark.Tuple,
+	kwargs []starlark.Tuple,
+) (starlark.Value, error) {
+
+	var (
+		child               starlark.Value
+		delay               starlark.Int
+		max_age             starlark.Int
+		show_full_animation starlark.Bool
+		time_based          starlark.Bool
+	)
+
+	if err := starlark.UnpackArgs(
+		"Root",
+		args, kwargs,
+		"child", &child,
+		"delay?", &delay,
+		"max_age?", &max_age,
+		"show_full_animation?", &show_full_animation,
+		"time_based?", &time_based,
+	); err != nil {
+		return nil, fmt.Errorf("unpacking arguments for Root: %s", err)
+	}
+
+	w := &Root{}
+
+	if child != nil {
+		childWidget, ok := child.(Widget)
+		if !ok {
+			return nil, fmt.Errorf(
+				"invalid type for child: %s (expected Widget)",
+				child.Type(),
+			)
+		}
+		w.Child = childWidget.AsRenderWidget()
+		w.starlarkChild = child
+	}
+
+	if val, err := starlark.AsInt32(delay); err == nil {
+		w.Delay = int32(val)
+	} else {
+		return nil, err
+	}
+
+	if val, err := starlark.AsInt32(max_age); err == nil {
+		w.MaxAge = int32(val)
+	} else {
+		return nil, err
+	}
+
+	w.ShowFullAnimation = bool(show_full_animation)
+
+	w.TimeBased = bool(time_based)
+
+	if err := w.Init(); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+func (w *Root) AsRenderRoot() render.Root {
+	return w.Root
+}
+
+func (w *Root) AttrNames() []string {
+	return []string{
+		"child", "delay", "max_age", "show_full_animation", "time_based",
+	}
+}
+
+func (w *Root) Attr(name string) (starlark.Value, error) {
+	switch name {
+
+	case "child":
+
+		return w.starlarkChild, nil
+
+	case "delay":
+
+		return starlark.MakeInt(int(w.Delay)), nil
+
+	case "max_age":
+
+		return starlark.MakeInt(int(w.MaxAge)), nil
+
+	case "show_full_animation":
+
+		return starlark.Bool(w.ShowFullAnimation), nil
+
+	case "time_based":
+
+		return starlark.Bool(w.TimeBased), nil
+
+	default:
+		return nil, nil
+	}
+}
+
+func (w *Root) String() string       { return "Root(...)" }
+func (w *Root) Type() string         { return "Root" }
+func (w *Root) Freeze()              {}
+func (w *Root) Truth() starlark.Bool { return true }
+
+func (w *Root) Hash() (uint32, error) {
+	sum, err := hashstructure.Hash(w, hashstructure.FormatV2, nil)
+	return uint32(sum), err
+}
+
+type Row struct {
+	Widget
+
+	render.Row
+
+	starlarkChildren *starlark.List
+
+	frame_count *starlark.Builtin
+}
+
+func newRow(
+	thread *starlark.Thread,
+	_ *starlark.Builtin,
+	args starlark.Tuple,
+	kwargs []starlark.Tuple,
+) (starlark.Value, error) {
+
+	var (
+		children    *starlark.List
+		main_align  starlark.String
+		cross_align starlark.String
+		expanded    starlark.Bool
+	)
+
+	if err := starlark.UnpackArgs(
+		"Row",
+		args, kwargs,
+		"children", &children,
+		"main_align?", &main_align,
+		"cross_align?", &cross_align,
+		"expanded?", &expanded,
+	); err != nil {
+		return nil, fmt.Errorf("unpacking arguments for Row: %s", err)
+	}
+
+	w := &Row{}
+
+	var childrenVal starlark.Value
+	childrenIter := children.Iterate()
+	defer childrenIter.Done()
+	for i := 0; childrenIter.Next(&childrenVal); {
+		if _, isNone := childrenVal.(starlark.NoneType); isNone {
+			continue
+		}
+
+		childrenChild, ok := childrenVal.(Widget)
+		if !ok {
+			return nil, fmt.Errorf(
+				"expected children to be a list of Widget but found: %s (at index %d)",
+				childrenVal.Type(),
+				i,
+			)
+		}
+
+		w.Children = append(w.Children, childrenChild.AsRenderWidget())
+	}
+	w.starlarkChildren = children
+
+	w.MainAlign = main_align.GoString()
+
+	w.CrossAlign = cross_align.GoString()
+
+	w.Expanded = bool(expanded)
+
+	w.frame_count = starlark.NewBuiltin("frame_count", rowFrameCount)
+
+	return w, nil
+}
+
+func (w *Row) AsRenderWidget() render.Widget {
+	return &w.Row
+}
+
+func (w *Row) AttrNames() []string {
+	return []string{
+		"children", "main_align", "cross_align", "expanded",
+	}
+}
+
+func (w *Row) Attr(name string) (starlark.Value, error) {
+	switch name {
+
+	case "children":
+
+		if w.starlarkChildren == nil {
+			return starlark.None, nil
+		}
+		return w.starlarkChildren, nil
+
+	case "main_align":
+
+		return starlark.String(w.MainAlign), nil
+
+	case "cross_align":
+
+		return starlark.String(w.CrossAlign), nil
+
+	case "expanded":
+
+		return starlark.Bool(w.Expanded), nil
+
+	case "frame_count":
+		return w.frame_count.BindReceiver(w), nil
+
+	default:
+		return nil, nil
+	}
+}
+
+func (w *Row) String() string       { return "Row(...)" }
+func (w *Row) Type() string         { return "Row" }
+func (w *Row) Freeze()              {}
+func (w *Row) Truth() starlark.Bool { return true }
+
+func (w *Row) Hash() (uint32, error) {
+	sum, err := hashstructure.Hash(w, hashstructure.FormatV2, nil)
+	return uint32(sum), err
+}
+
+func rowFrameCount(
+	thread *starlark.Thread,
+	b *starlark.Builtin,
+	args starlark.Tuple,
+	kwargs []starlark.Tuple) (starlark.Value, error) {
+
+	w := b.Receiver().(*Row)
+	count := w.FrameCount()
+
+	return starlark.MakeInt(count), nil
+}
+
+type Sequence struct {
+	Widget
+
+	render.Sequence
+
+	starlarkChildren *starlark.List
+
+	frame_count *starlark.Builtin
+}
+
+func newSequence(
+	thread *starlark.Thread,
+	_ *starlark.Builtin,
+	args starlark.Tuple,
+	kwargs []starlark.Tuple,
+) (starlark.Value, error) {
+
+	var (
+		children *starlark.List
+	)
+
+	if err := starlark.UnpackArgs(
+		"Sequence",
+		args, kwargs,
+		"children", &children,
+	); err != nil {
+		return nil, fmt.Errorf("unpacking arguments for Sequence: %s", err)
+	}
+
+	w := &Sequence{}
+
+	var childrenVal starlark.Value
+	childrenIter := children.Iterate()
+	defer childrenIter.Done()
+	for i := 0; childrenIter.Next(&childrenVal); {
+		if _, isNone := childrenVal.(starlark.NoneType); isNone {
+			continue
+		}
+
+		childrenChild, ok := childrenVal.(Widget)
+		if !ok {
+			return nil, fmt.Errorf(
+				"expected children to be a list of Widget but found: %s (at index %d)",
+				childrenVal.Type(),
+				i,
+			)
+		}
+
+		w.Children = append(w.Children, childrenChild.AsRenderWidget())
+	}
+	w.starlarkChildren = children
+
+	w.frame_count = starlark.NewBuiltin("frame_count", sequenceFrameCount)
+
+	return w, nil
+}
+
+func (w *Sequence) AsRenderWidget() render.Widget {
+	return &w.Sequence
+}
+
+func (w *Sequence) AttrNames() []string {
+	return []string{
+		"children",
+	}
+}
+
+func (w *Sequence) Attr(name string) (starlark.Value, error) {
+	switch name {
+
+	case "children":
+
+		if w.starlarkChildren == nil {
+			return starlark.None, nil
+		}
+		return w.starlarkChildren, nil
+
+	case "frame_count":
+		return w.frame_count.BindReceiver(w), nil
+
+	default:
+		return nil, nil
+	}
+}
+
+func (w *Sequence) String() string       { return "Sequence(...)" }
+func (w *Sequence) Type() string         { return "Sequence" }
+func (w *Sequence) Freeze()              {}
+func (w *Sequence) Truth() starlark.Bool { return true }
+
+func (w *Sequence) Hash() (uint32, error) {
+	sum, err := hashstructure.Hash(w, hashstructure.FormatV2, nil)
+	return uint32(sum), err
+}
+
+func sequenceFrameCount(
+	thread *starlark.Thread,
+	b *starlark.Builtin,
+	args starlark.Tuple,
+	kwargs []starlark.Tuple) (starlark.Value, error) {
+
+	w := b.Receiver().(*Sequence)
+	count := w.FrameCount()
+
+	return starlark.MakeInt(count), nil
+}
+
+type Series struct {
+	render.Series
+
+	starlarkData *starlark.List
+
+	starlarkColor starlark.String
+
+	starlarkColorInverted starlark.String
+
+	starlarkFillColor starlark.String
+
+	starlarkFillColorInverted starlark.String
+}
+
+func newSeries(
+	thread *starlark.Thread,
+	_ *starlark.Builtin,
+	args starlark.Tuple,
+	kwargs []starlark.Tuple,
+) (starlark.Value, error) {
+
+	var (
+		data                *starlark.List
+		name                starlark.String
+		color               starlark.String
+		color_inverted      starlark.String
+		fill                starlark.Bool
+		fill_color          starlark.String
+		fill_color_inverted starlark.String
+		chart_type          starlark.String
+	)
+
+	if err := starlark.UnpackArgs(
+		"Series",
+		args, kwargs,
+		"data", &data,
+		"name?", &name,
+		"color?", &color,
+		"color_inverted?", &color_inverted,
+		"fill?", &fill,
+		"fill_color?", &fill_color,
+		"fill_color_inverted?", &fill_color_inverted,
+		"chart_type?", &chart_type,
+	); err != nil {
+		return nil, fmt.Errorf("unpacking arguments for Series: %s", err)
+	}
+
+	w := &Series{}
+
+	w.starlarkData = data
+	if val, err := DataSeriesFromStarlark(data); err == nil {
+		w.Data = val
+	} else {
+		return nil, err
+	}
+
+	w.Name = name.GoString()
+
+	w.starlarkColor = color
+	if color.Len() > 0 {
+		c, err := render.ParseColor(color.GoString())
+		if err != nil {
+			return nil, fmt.Errorf("color is not a valid hex string: %s", color.String())
+		}
+		w.Color = c
+	}
+
+	w.starlarkColorInverted = color_inverted
+	if color_inverted.Len() > 0 {
+		c, err := render.ParseColor(color_inverted.GoString())
+		if err != nil {
+			return nil, fmt.Errorf("color_inverted is not a valid hex string: %s", color_inverted.String())
+		}
+		w.ColorInverted = c
+	}
+
+	w.Fill = bool(fill)
+
+	w.starlarkFillColor = fill_color
+	if fill_color.Len() > 0 {
+		c, err := render.ParseColor(fill_color.GoString())
+		if err != nil {
+			return nil, fmt.Errorf("fill_color is not a valid hex string: %s", fill_color.String())
+		}
+		w.FillColor = c
+	}
+
+	w.starlarkFillColorInverted = fill_color_inverted
+	if fill_color_inverted.Len() > 0 {
+		c, err := render.ParseColor(fill_color_inverted.GoString())
+		if err != nil {
+			return nil, fmt.Errorf("fill_color_inverted is not a valid hex string: %s", fill_color_inverted.String())
+		}
+		w.FillColorInverted = c
+	}
+
+	w.ChartType = chart_type.GoString()
+
+	return w, nil
+}
+
+func (w *Series) AttrNames() []string {
+	return []string{
+		"data", "name", "color", "color_inverted", "fill", "fill_color", "fill_color_inverted", "chart_type",
+	}
+}
+
+func (w *Series) Attr(name string) (starlark.Value, error) {
+	switch name {
+
+	case "data":
+
+		if w.starlarkData == nil {
+			return starlark.None, nil
+		}
+		return w.starlarkData, nil
+
+	case "name":
+
+		return starlark.String(w.Name), nil
+
+	case "color":
+
+		return w.starlarkColor, nil
+
+	case "color_inverted":
 
-	case "child":
+		return w.starlarkColorInverted, nil
 
-		return w.starlarkChild, nil
+	case "fill":
 
-	case "delay":
+		return starlark.Bool(w.Fill), nil
 
-		return starlark.MakeInt(int(w.Delay)), nil
+	case "fill_color":
 
-	case "max_age":
+		return w.starlarkFillColor, nil
 
-		return starlark.MakeInt(int(w.MaxAge)), nil
+	case "fill_color_inverted":
 
-	case "show_full_animation":
+		return w.starlarkFillColorInverted, nil
 
-		return starlark.Bool(w.ShowFullAnimation), nil
+	case "chart_type":
+
+		return starlark.String(w.ChartType), nil
 
 	default:
 		return nil, nil
 	}
 }
 
-func (w *Root) String() string       { return "Root(...)" }
-func (w *Root) Type() string         { return "Root" }
-func (w *Root) Freeze()              {}
-func (w *Root) Truth() starlark.Bool { return true }
+func (w *Series) String() string       { return "Series(...)" }
+func (w *Series) Type() string         { return "Series" }
+func (w *Series) Freeze()              {}
+func (w *Series) Truth() starlark.Bool { return true }
 
-func (w *Root) Hash() (uint32, error) {
+func (w *Series) Hash() (uint32, error) {
 	sum, err := hashstructure.Hash(w, hashstructure.FormatV2, nil)
 	return uint32(sum), err
 }
 
-type Row struct {
-	Widget
-
-	render.Row
+type Span struct {
+	render.Span
 
-	starlarkChildren *starlark.List
+	starlarkColor starlark.String
 
-	frame_count *starlark.Builtin
+	starlarkImage starlark.Value
 }
 
-func newRow(
+func newSpan(
 	thread *starlark.Thread,
 	_ *starlark.Builtin,
 	args starlark.Tuple,
@@ -1464,127 +3624,114 @@ func newRow(
 ) (starlark.Value, error) {
 
 	var (
-		children    *starlark.List
-		main_align  starlark.String
-		cross_align starlark.String
-		expanded    starlark.Bool
+		content starlark.String
+		font    starlark.String
+		color   starlark.String
+		image   starlark.Value
 	)
 
 	if err := starlark.UnpackArgs(
-		"Row",
+		"Span",
 		args, kwargs,
-		"children", &children,
-		"main_align?", &main_align,
-		"cross_align?", &cross_align,
-		"expanded?", &expanded,
+		"content?", &content,
+		"font?", &font,
+		"color?", &color,
+		"image?", &image,
 	); err != nil {
-		return nil, fmt.Errorf("unpacking arguments for Row: %s", err)
+		return nil, fmt.Errorf("unpacking arguments for Span: %s", err)
 	}
 
-	w := &Row{}
+	w := &Span{}
 
-	var childrenVal starlark.Value
-	childrenIter := children.Iterate()
-	defer childrenIter.Done()
-	for i := 0; childrenIter.Next(&childrenVal); {
-		if _, isNone := childrenVal.(starlark.NoneType); isNone {
-			continue
+	w.Content = content.GoString()
+
+	w.Font = font.GoString()
+
+	w.starlarkColor = color
+	if color.Len() > 0 {
+		c, err := render.ParseColor(color.GoString())
+		if err != nil {
+			return nil, fmt.Errorf("color is not a valid hex string: %s", color.String())
 		}
+		w.Color = c
+	}
 
-		childrenChild, ok := childrenVal.(Widget)
+	if image != nil {
+		imageWidget, ok := image.(Widget)
 		if !ok {
 			return nil, fmt.Errorf(
-				"expected children to be a list of Widget but found: %s (at index %d)",
-				childrenVal.Type(),
-				i,
+				"invalid type for image: %s (expected Widget)",
+				image.Type(),
 			)
 		}
-
-		w.Children = append(w.Children, childrenChild.AsRenderWidget())
+		w.Image = imageWidget.AsRenderWidget()
+		w.starlarkImage = image
 	}
-	w.starlarkChildren = children
-
-	w.MainAlign = main_align.GoString()
-
-	w.CrossAlign = cross_align.GoString()
-
-	w.Expanded = bool(expanded)
-
-	w.frame_count = starlark.NewBuiltin("frame_count", rowFrameCount)
 
 	return w, nil
 }
 
-func (w *Row) AsRenderWidget() render.Widget {
-	return &w.Row
-}
-
-func (w *Row) AttrNames() []string {
+func (w *Span) AttrNames() []string {
 	return []string{
-		"children", "main_align", "cross_align", "expanded",
+		"content", "font", "color", "image",
 	}
 }
 
-func (w *Row) Attr(name string) (starlark.Value, error) {
+func (w *Span) Attr(name string) (starlark.Value, error) {
 	switch name {
 
-	case "children":
-
-		return w.starlarkChildren, nil
+	case "content":
 
-	case "main_align":
+		return starlark.String(w.Content), nil
 
-		return starlark.String(w.MainAlign), nil
+	case "font":
 
-	case "cross_align":
+		return starlark.String(w.Font), nil
 
-		return starlark.String(w.CrossAlign), nil
+	case "color":
 
-	case "expanded":
+		return w.starlarkColor, nil
 
-		return starlark.Bool(w.Expanded), nil
+	case "image":
 
-	case "frame_count":
-		return w.frame_count.BindReceiver(w), nil
+		return w.starlarkImage, nil
 
 	default:
 		return nil, nil
 	}
 }
 
-func (w *Row) String() string       { return "Row(...)" }
-func (w *Row) Type() string         { return "Row" }
-func (w *Row) Freeze()              {}
-func (w *Row) Truth() starlark.Bool { return true }
+func (w *Span) String() string       { return "Span(...)" }
+func (w *Span) Type() string         { return "Span" }
+func (w *Span) Freeze()              {}
+func (w *Span) Truth() starlark.Bool { return true }
 
-func (w *Row) Hash() (uint32, error) {
+func (w *Span) Hash() (uint32, error) {
 	sum, err := hashstructure.Hash(w, hashstructure.FormatV2, nil)
 	return uint32(sum), err
 }
 
-func rowFrameCount(
-	thread *starlark.Thread,
-	b *starlark.Builtin,
-	args starlark.Tuple,
-	kwargs []starlark.Tuple) (starlark.Value, error) {
+type Sparkline struct {
+	Widget
 
-	w := b.Receiver().(*Row)
-	count := w.FrameCount()
+	render.Sparkline
 
-	return starlark.MakeInt(count), nil
-}
+	starlarkData *starlark.List
 
-type Sequence struct {
-	Widget
+	starlarkColor starlark.String
 
-	render.Sequence
+	starlarkFillColor starlark.String
 
-	starlarkChildren *starlark.List
+	starlarkMinColor starlark.String
+
+	starlarkMaxColor starlark.String
+
+	starlarkLim starlark.Tuple
 
 	frame_count *starlark.Builtin
 }
 
-func newSequence(
+func newSparkline(
 	thread *starlark.Thread,
 	_ *starlark.Builtin,
 	args starlark.Tuple,
@@ -1592,61 +3739,149 @@ func newSequence(
 ) (starlark.Value, error) {
 
 	var (
-		children *starlark.List
+		data       *starlark.List
+		width      starlark.Int
+		height     starlark.Int
+		color      starlark.String
+		fill       starlark.Bool
+		fill_color starlark.String
+		min_color  starlark.String
+		max_color  starlark.String
+		lim        starlark.Tuple
 	)
 
 	if err := starlark.UnpackArgs(
-		"Sequence",
+		"Sparkline",
 		args, kwargs,
-		"children", &children,
+		"data", &data,
+		"width", &width,
+		"height", &height,
+		"color?", &color,
+		"fill?", &fill,
+		"fill_color?", &fill_color,
+		"min_color?", &min_color,
+		"max_color?", &max_color,
+		"lim?", &lim,
 	); err != nil {
-		return nil, fmt.Errorf("unpacking arguments for Sequence: %s", err)
+		return nil, fmt.Errorf("unpacking arguments for Sparkline: %s", err)
 	}
 
-	w := &Sequence{}
+	w := &Sparkline{}
 
-	var childrenVal starlark.Value
-	childrenIter := children.Iterate()
-	defer childrenIter.Done()
-	for i := 0; childrenIter.Next(&childrenVal); {
-		if _, isNone := childrenVal.(starlark.NoneType); isNone {
-			continue
+	if data != nil {
+		w.starlarkData = data
+		if val, err := WeightsFromStarlark(data); err == nil {
+			w.Data = val
+		} else {
+			return nil, err
 		}
+	}
 
-		childrenChild, ok := childrenVal.(Widget)
-		if !ok {
-			return nil, fmt.Errorf(
-				"expected children to be a list of Widget but found: %s (at index %d)",
-				childrenVal.Type(),
-				i,
-			)
+	w.Width = int(width.BigInt().Int64())
+
+	w.Height = int(height.BigInt().Int64())
+
+	w.starlarkColor = color
+	if color.Len() > 0 {
+		c, err := render.ParseColor(color.GoString())
+		if err != nil {
+			return nil, fmt.Errorf("color is not a valid hex string: %s", color.String())
 		}
+		w.Color = c
+	}
 
-		w.Children = append(w.Children, childrenChild.AsRenderWidget())
+	w.Fill = bool(fill)
+
+	w.starlarkFillColor = fill_color
+	if fill_color.Len() > 0 {
+		c, err := render.ParseColor(fill_color.GoString())
+		if err != nil {
+			return nil, fmt.Errorf("fill_color is not a valid hex string: %s", fill_color.String())
+		}
+		w.FillColor = c
 	}
-	w.starlarkChildren = children
 
-	w.frame_count = starlark.NewBuiltin("frame_count", sequenceFrameCount)
+	w.starlarkMinColor = min_color
+	if min_color.Len() > 0 {
+		c, err := render.ParseColor(min_color.GoString())
+		if err != nil {
+			return nil, fmt.Errorf("min_color is not a valid hex string: %s", min_color.String())
+		}
+		w.MinColor = c
+	}
+
+	w.starlarkMaxColor = max_color
+	if max_color.Len() > 0 {
+		c, err := render.ParseColor(max_color.GoString())
+		if err != nil {
+			return nil, fmt.Errorf("max_color is not a valid hex string: %s", max_color.String())
+		}
+		w.MaxColor = c
+	}
+
+	w.starlarkLim = lim
+	if val, err := DataPointFromStarlark(lim); err == nil {
+		w.Lim = val
+	} else {
+		return nil, err
+	}
+
+	w.frame_count = starlark.NewBuiltin("frame_count", sparklineFrameCount)
 
 	return w, nil
 }
 
-func (w *Sequence) AsRenderWidget() render.Widget {
-	return &w.Sequence
+func (w *Sparkline) AsRenderWidget() render.Widget {
+	return &w.Sparkline
 }
 
-func (w *Sequence) AttrNames() []string {
+func (w *Sparkline) AttrNames() []string {
 	return []string{
-		"children",
+		"data", "width", "height", "color", "fill", "fill_color", "min_color", "max_color", "lim",
 	}
 }
 
-func (w *Sequence) Attr(name string) (starlark.Value, error) {
+func (w *Sparkline) Attr(name string) (starlark.Value, error) {
 	switch name {
 
-	case "children":
+	case "data":
 
-		return w.starlarkChildren, nil
+		if w.starlarkData == nil {
+			return starlark.None, nil
+		}
+		return w.starlarkData, nil
+
+	case "width":
+
+		return starlark.MakeInt(int(w.Width)), nil
+
+	case "height":
+
+		return starlark.MakeInt(int(w.Height)), nil
+
+	case "color":
+
+		return w.starlarkColor, nil
+
+	case "fill":
+
+		return starlark.Bool(w.Fill), nil
+
+	case "fill_color":
+
+		return w.starlarkFillColor, nil
+
+	case "min_color":
+
+		return w.starlarkMinColor, nil
+
+	case "max_color":
+
+		return w.starlarkMaxColor, nil
+
+	case "lim":
+
+		return w.starlarkLim, nil
 
 	case "frame_count":
 		return w.frame_count.BindReceiver(w), nil
@@ -1656,23 +3891,23 @@ func (w *Sequence) Attr(name string) (starlark.Value, error) {
 	}
 }
 
-func (w *Sequence) String() string       { return "Sequence(...)" }
-func (w *Sequence) Type() string         { return "Sequence" }
-func (w *Sequence) Freeze()              {}
-func (w *Sequence) Truth() starlark.Bool { return true }
+func (w *Sparkline) String() string       { return "Sparkline(...)" }
+func (w *Sparkline) Type() string         { return "Sparkline" }
+func (w *Sparkline) Freeze()              {}
+func (w *Sparkline) Truth() starlark.Bool { return true }
 
-func (w *Sequence) Hash() (uint32, error) {
+func (w *Sparkline) Hash() (uint32, error) {
 	sum, err := hashstructure.Hash(w, hashstructure.FormatV2, nil)
 	return uint32(sum), err
 }
 
-func sequenceFrameCount(
+func sparklineFrameCount(
 	thread *starlark.Thread,
 	b *starlark.Builtin,
 	args starlark.Tuple,
 	kwargs []starlark.Tuple) (starlark.Value, error) {
 
-	w := b.Receiver().(*Sequence)
+	w := b.Receiver().(*Sparkline)
 	count := w.FrameCount()
 
 	return starlark.MakeInt(count), nil
@@ -1750,6 +3985,9 @@ func (w *Stack) Attr(name string) (starlark.Value, error) {
 
 	case "children":
 
+		if w.starlarkChildren == nil {
+			return starlark.None, nil
+		}
 		return w.starlarkChildren, nil
 
 	case "frame_count":
@@ -1789,6 +4027,8 @@ type Text struct {
 
 	starlarkColor starlark.String
 
+	starlarkFallback *starlark.List
+
 	size *starlark.Builtin
 
 	frame_count *starlark.Builtin
@@ -1802,11 +4042,12 @@ func newText(
 ) (starlark.Value, error) {
 
 	var (
-		content starlark.String
-		font    starlark.String
-		height  starlark.Int
-		offset  starlark.Int
-		color   starlark.String
+		content  starlark.String
+		font     starlark.String
+		height   starlark.Int
+		offset   starlark.Int
+		color    starlark.String
+		fallback *starlark.List
 	)
 
 	if err := starlark.UnpackArgs(
@@ -1817,6 +4058,7 @@ func newText(
 		"height?", &height,
 		"offset?", &offset,
 		"color?", &color,
+		"fallback?", &fallback,
 	); err != nil {
 		return nil, fmt.Errorf("unpacking arguments for Text: %s", err)
 	}
@@ -1840,10 +4082,21 @@ func newText(
 		w.Color = c
 	}
 
+	if fallback != nil {
+		w.starlarkFallback = fallback
+		if val, err := StringsFromStarlark(fallback); err == nil {
+			w.Fallback = val
+		} else {
+			return nil, err
+		}
+	}
+
 	w.size = starlark.NewBuiltin("size", textSize)
 
 	w.frame_count = starlark.NewBuiltin("frame_count", textFrameCount)
 
+	w.SetFontSource(threadFonts(thread))
+
 	if err := w.Init(); err != nil {
 		return nil, err
 	}
@@ -1857,7 +4110,7 @@ func (w *Text) AsRenderWidget() render.Widget {
 
 func (w *Text) AttrNames() []string {
 	return []string{
-		"content", "font", "height", "offset", "color",
+		"content", "font", "height", "offset", "color", "fallback",
 	}
 }
 
@@ -1884,6 +4137,13 @@ func (w *Text) Attr(name string) (starlark.Value, error) {
 
 		return w.starlarkColor, nil
 
+	case "fallback":
+
+		if w.starlarkFallback == nil {
+			return starlark.None, nil
+		}
+		return w.starlarkFallback, nil
+
 	case "size":
 		return w.size.BindReceiver(w), nil
 
@@ -1939,6 +4199,8 @@ type WrappedText struct {
 
 	starlarkColor starlark.String
 
+	starlarkFallback *starlark.List
+
 	frame_count *starlark.Builtin
 }
 
@@ -1957,6 +4219,7 @@ func newWrappedText(
 		linespacing starlark.Int
 		color       starlark.String
 		align       starlark.String
+		fallback    *starlark.List
 	)
 
 	if err := starlark.UnpackArgs(
@@ -1969,6 +4232,7 @@ func newWrappedText(
 		"linespacing?", &linespacing,
 		"color?", &color,
 		"align?", &align,
+		"fallback?", &fallback,
 	); err != nil {
 		return nil, fmt.Errorf("unpacking arguments for WrappedText: %s", err)
 	}
@@ -1996,8 +4260,19 @@ func newWrappedText(
 
 	w.Align = align.GoString()
 
+	if fallback != nil {
+		w.starlarkFallback = fallback
+		if val, err := StringsFromStarlark(fallback); err == nil {
+			w.Fallback = val
+		} else {
+			return nil, err
+		}
+	}
+
 	w.frame_count = starlark.NewBuiltin("frame_count", wrappedtextFrameCount)
 
+	w.SetFontSource(threadFonts(thread))
+
 	if err := w.Init(); err != nil {
 		return nil, err
 	}
@@ -2011,7 +4286,7 @@ func (w *WrappedText) AsRenderWidget() render.Widget {
 
 func (w *WrappedText) AttrNames() []string {
 	return []string{
-		"content", "font", "height", "width", "linespacing", "color", "align",
+		"content", "font", "height", "width", "linespacing", "color", "align", "fallback",
 	}
 }
 
@@ -2046,6 +4321,13 @@ func (w *WrappedText) Attr(name string) (starlark.Value, error) {
 
 		return starlark.String(w.Align), nil
 
+	case "fallback":
+
+		if w.starlarkFallback == nil {
+			return starlark.None, nil
+		}
+		return w.starlarkFallback, nil
+
 	case "frame_count":
 		return w.frame_count.BindReceiver(w), nil
 
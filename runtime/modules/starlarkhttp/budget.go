@@ -0,0 +1,50 @@
+package starlarkhttp
+
+import (
+	"io"
+
+	"go.starlark.net/starlark"
+)
+
+const (
+	// ThreadBudgetKey is the name of the Starlark thread-local that holds
+	// the thread's HTTP budget.
+	ThreadBudgetKey = "tidbyt.dev/pixlet/starlarkhttp/$budget"
+)
+
+// Budget limits the HTTP requests made on a Starlark thread. Request is
+// called before every request, and Read with the size of every chunk of
+// response body that is read. Returning an error from either fails the
+// call that made the request or read the body.
+type Budget interface {
+	Request() error
+	Read(n int) error
+}
+
+// AttachBudgetToThread attaches a Budget to a Starlark thread, so that all
+// requests made on it are counted against the budget.
+func AttachBudgetToThread(thread *starlark.Thread, budget Budget) {
+	thread.SetLocal(ThreadBudgetKey, budget)
+}
+
+func threadBudget(thread *starlark.Thread) Budget {
+	budget, _ := thread.Local(ThreadBudgetKey).(Budget)
+	return budget
+}
+
+// budgetReader counts the bytes read from a response body against a
+// Budget.
+type budgetReader struct {
+	io.ReadCloser
+	budget Budget
+}
+
+func (r *budgetReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if n > 0 {
+		if berr := r.budget.Read(n); berr != nil {
+			return n, berr
+		}
+	}
+	return n, err
+}
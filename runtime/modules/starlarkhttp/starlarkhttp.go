@@ -35,6 +35,7 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	util "github.com/qri-io/starlib/util"
 	"go.starlark.net/starlark"
@@ -160,10 +161,24 @@ func (m *Module) reqMethod(method string) func(thread *starlark.Thread, _ *starl
 			return nil, err
 		}
 
+		budget := threadBudget(thread)
+		if budget != nil {
+			if err := budget.Request(); err != nil {
+				return nil, err
+			}
+		}
+
+		start := time.Now()
 		res, err := m.cli.Do(req)
+		if tracer := threadTracer(thread); tracer != nil {
+			tracer.TraceRequest(req, res, time.Since(start), err)
+		}
 		if err != nil {
 			return nil, err
 		}
+		if budget != nil {
+			res.Body = &budgetReader{ReadCloser: res.Body, budget: budget}
+		}
 
 		r := &Response{*res}
 		return r.Struct(), nil
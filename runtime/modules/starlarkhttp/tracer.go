@@ -0,0 +1,31 @@
+package starlarkhttp
+
+import (
+	"net/http"
+	"time"
+
+	"go.starlark.net/starlark"
+)
+
+const (
+	// ThreadTracerKey is the name of the Starlark thread-local that holds
+	// the thread's HTTP tracer.
+	ThreadTracerKey = "tidbyt.dev/pixlet/starlarkhttp/$tracer"
+)
+
+// Tracer is notified of every request made on a Starlark thread, once the
+// response headers have been received or the request has failed.
+type Tracer interface {
+	TraceRequest(req *http.Request, res *http.Response, latency time.Duration, err error)
+}
+
+// AttachTracerToThread attaches a Tracer to a Starlark thread, so that it's
+// notified of all requests made on it.
+func AttachTracerToThread(thread *starlark.Thread, tracer Tracer) {
+	thread.SetLocal(ThreadTracerKey, tracer)
+}
+
+func threadTracer(thread *starlark.Thread) Tracer {
+	tracer, _ := thread.Local(ThreadTracerKey).(Tracer)
+	return tracer
+}
@@ -0,0 +1,100 @@
+package runtime
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	"go.starlark.net/starlark"
+	"go.starlark.net/syntax"
+)
+
+// ProgramCache stores compiled Starlark programs, so that reloading an
+// applet can skip parsing and compiling files that haven't changed.
+// Programs are stored in their serialized form, and keyed by a hash of the
+// compiler version, file name and source.
+type ProgramCache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, program []byte)
+}
+
+// InMemoryProgramCache is a ProgramCache that keeps programs in memory.
+// Entries are never evicted, since a changed file is stored under a new key,
+// so it's meant to be used for the lifetime of a single development session.
+type InMemoryProgramCache struct {
+	programs map[string][]byte
+	mutex    sync.RWMutex
+}
+
+func NewInMemoryProgramCache() *InMemoryProgramCache {
+	return &InMemoryProgramCache{programs: map[string][]byte{}}
+}
+
+func (c *InMemoryProgramCache) Get(key string) ([]byte, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	program, found := c.programs[key]
+	return program, found
+}
+
+func (c *InMemoryProgramCache) Set(key string, program []byte) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.programs[key] = program
+}
+
+// WithProgramCache reuses compiled programs from cache when loading the
+// applet, and stores newly compiled ones in it.
+func WithProgramCache(cache ProgramCache) AppletOption {
+	return func(a *Applet) error {
+		a.programs = cache
+		return nil
+	}
+}
+
+var fileOptions = &syntax.FileOptions{
+	Set:       true,
+	Recursion: true,
+}
+
+// compile parses and compiles a Starlark file, or decodes it from the
+// applet's program cache if the same source was compiled before.
+func (a *Applet) compile(filename string, src []byte, predeclared starlark.StringDict) (*starlark.Program, error) {
+	if a.programs == nil {
+		_, prog, err := starlark.SourceProgramOptions(fileOptions, filename, src, predeclared.Has)
+		return prog, err
+	}
+
+	key := programCacheKey(filename, src)
+	if b, ok := a.programs.Get(key); ok {
+		prog, err := starlark.CompiledProgram(bytes.NewReader(b))
+		if err == nil {
+			return prog, nil
+		}
+		// a corrupt entry is simply recompiled and replaced
+	}
+
+	_, prog, err := starlark.SourceProgramOptions(fileOptions, filename, src, predeclared.Has)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	if err := prog.Write(buf); err != nil {
+		return nil, fmt.Errorf("serializing %s: %w", filename, err)
+	}
+	a.programs.Set(key, buf.Bytes())
+
+	return prog, nil
+}
+
+func programCacheKey(filename string, src []byte) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%d\x00%s\x00", starlark.CompilerVersion, filename)
+	h.Write(src)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,81 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.starlark.net/starlark"
+)
+
+type countingProgramCache struct {
+	*InMemoryProgramCache
+	hits, misses int
+}
+
+func (c *countingProgramCache) Get(key string) ([]byte, bool) {
+	b, ok := c.InMemoryProgramCache.Get(key)
+	if ok {
+		c.hits++
+	} else {
+		c.misses++
+	}
+	return b, ok
+}
+
+func TestProgramCache(t *testing.T) {
+	vfs := fstest.MapFS{
+		"main.star": {Data: []byte(`
+load("render.star", "render")
+load("util.star", "greeting")
+
+def main(config):
+    return render.Root(child = render.Text(greeting()))
+`)},
+		"util.star": {Data: []byte(`
+def greeting():
+    return "hello"
+`)},
+	}
+
+	cache := &countingProgramCache{InMemoryProgramCache: NewInMemoryProgramCache()}
+
+	app, err := NewAppletFromFS("test", vfs, WithProgramCache(cache))
+	require.NoError(t, err)
+	assert.Equal(t, 0, cache.hits)
+	assert.Equal(t, 2, cache.misses)
+
+	// reloading an unchanged applet uses the compiled programs
+	app, err = NewAppletFromFS("test", vfs, WithProgramCache(cache))
+	require.NoError(t, err)
+	assert.Equal(t, 2, cache.hits)
+	assert.Equal(t, 2, cache.misses)
+
+	roots, err := app.Run(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, roots, 1)
+
+	// only the changed file is recompiled
+	vfs["util.star"] = &fstest.MapFile{Data: []byte(`
+def greeting():
+    return "goodbye"
+`)}
+	app, err = NewAppletFromFS("test", vfs, WithProgramCache(cache))
+	require.NoError(t, err)
+	assert.Equal(t, 3, cache.hits)
+	assert.Equal(t, 3, cache.misses)
+
+	val, err := app.Call(context.Background(), app.Globals["util.star"]["greeting"].(*starlark.Function))
+	require.NoError(t, err)
+	assert.Equal(t, `"goodbye"`, val.String())
+}
+
+func TestProgramCacheCompileError(t *testing.T) {
+	cache := NewInMemoryProgramCache()
+
+	_, err := NewApplet("broken", []byte("def main(:"), WithProgramCache(cache))
+	assert.ErrorContains(t, err, "starlark.ExecFile")
+	assert.Empty(t, cache.programs)
+}
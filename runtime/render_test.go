@@ -8,6 +8,7 @@ import (
 	"image"
 	"image/color"
 	"image/png"
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -131,6 +132,64 @@ assert(r1.children[1].cross_align == "end", 'r1.children[1].cross_align == "end"
 assert(len(r1.children) == 2, "len(r1.children) == 2")
 assert(len(r1.children[1].children) == 2, "len(r1.children[1].children) == 2")
 
+# Charts
+bc = render.BarChart(
+    data = [1, (2, 3), [4, None]],
+    width = 64,
+    height = 32,
+    colors = ["#f00", "#0f0"],
+    stacked = True,
+    lim = (0, None),
+)
+assert(len(bc.data) == 3, "len(bc.data) == 3")
+assert(bc.stacked == True, "bc.stacked == True")
+assert(bc.frame_count() == 1, "bc.frame_count() == 1")
+
+pl = render.Plot(
+    data = [(0, 1), (1, 2)],
+    series = [
+        render.Series(data = [(0, 3), (1, -1)], name = "away", color = "#f00", chart_type = "scatter"),
+    ],
+    width = 64,
+    height = 32,
+    axes = True,
+    x_ticks = 1,
+    grid = True,
+)
+assert(len(pl.series) == 1, "len(pl.series) == 1")
+assert(pl.series[0].name == "away", 'pl.series[0].name == "away"')
+assert(pl.x_ticks == 1, "pl.x_ticks == 1")
+assert(render.Plot(data = [], width = 1, height = 1).series == None, "unset series")
+assert(pl.y_ticks == 0, "unset pl.y_ticks == 0")
+
+# Optional floats read back as their defaults
+arc = render.Arc(x = 1, y = 1, radius = 1)
+assert(arc.start == 0, "unset arc.start == 0")
+assert(arc.end == 0, "unset arc.end == 0")
+assert(render.Image(src = base64.decode("iVBORw0KGgoAAAANSUhEUgAAAAIAAAACCAYAAABytg0kAAAAH0lEQVR4nAASAO3/Av8AAP8AAAAAAAAAAAD/AAD/AwAkEgP/N/JgfwAAAABJRU5ErkJggg==")).brightness == 0, "unset brightness == 0")
+
+sl = render.Sparkline(
+    data = [1, 2.5, 3],
+    width = 32,
+    height = 8,
+    max_color = "#0f0",
+)
+assert(sl.max_color == "#0f0", 'sl.max_color == "#0f0"')
+
+g = render.Gauge(
+    value = 42,
+    width = 32,
+    height = 16,
+    thresholds = [50],
+    threshold_colors = ["#f00"],
+    chart_type = "linear",
+)
+assert(g.value == 42, "g.value == 42")
+assert(g.chart_type == "linear", 'g.chart_type == "linear"')
+assert(g.track_color == "", 'g.track_color == ""')
+assert(render.Sparkline(data = [1], width = 1, height = 1).min_color == "", "unset min_color")
+assert(render.Gauge(value = 1, width = 1, height = 1).thresholds == None, "unset thresholds")
+
 def main():
     return render.Root(child=r1)
 `
@@ -215,6 +274,149 @@ def main():
 	assert.Equal(t, text.Height, rendered.Bounds().Dy())
 }
 
+func TestRichText(t *testing.T) {
+	const (
+		filename = "test_rich_text.star"
+		src      = `
+load("render.star", "render")
+
+def assert(success, message=None):
+    if not success:
+        fail(message or "assertion failed")
+
+icon = render.Box(width = 3, height = 3, color = "#00f")
+t = render.RichText(
+	spans = [
+		render.Span("BOS", color = "#fa0", image = icon),
+		" 3 - 2 ",
+		render.Span(content = "NYY", font = render.fonts["6x13"]),
+	],
+	color = "#fff",
+)
+assert(len(t.spans) == 3, "len(t.spans) == 3")
+assert(t.spans[0].content == "BOS", 't.spans[0].content == "BOS"')
+assert(t.spans[0].image == icon, "t.spans[0].image == icon")
+assert(t.spans[1] == " 3 - 2 ", 't.spans[1] == " 3 - 2 "')
+assert(t.spans[2].font == "6x13", 't.spans[2].font == "6x13"')
+
+def main():
+    return render.Root(child=t)
+`
+	)
+
+	app, err := NewApplet(filename, []byte(src))
+	require.NoError(t, err)
+
+	txt := app.Globals["test_rich_text.star"]["t"]
+	assert.IsType(t, &render_runtime.RichText{}, txt)
+
+	widget := txt.(*render_runtime.RichText).AsRenderWidget()
+	require.IsType(t, &render.RichText{}, widget)
+
+	text := widget.(*render.RichText)
+	require.Equal(t, 3, len(text.Spans))
+	assert.Equal(t, " 3 - 2 ", text.Spans[1].Content)
+	assert.Nil(t, text.Spans[1].Color)
+	assert.IsType(t, &render.Box{}, text.Spans[0].Image)
+
+	rendered := render.PaintWidget(widget, image.Rect(0, 0, 64, 32), 0)
+	assert.Greater(t, rendered.Bounds().Dx(), 0)
+	assert.Equal(t, 13, rendered.Bounds().Dy())
+
+	_, err = NewApplet("bad_span.star", []byte(`
+load("render.star", "render")
+t = render.RichText(spans = [1])
+def main():
+    return render.Root(child=t)
+`))
+	assert.Error(t, err)
+}
+
+func TestBarChart(t *testing.T) {
+	const (
+		filename = "test_bar_chart.star"
+		src      = `
+load("render.star", "render")
+b = render.BarChart(
+	data = [1, (2, 3), [4, None]],
+	width = 8,
+	height = 4,
+)
+def main():
+    return render.Root(child=b)
+`
+	)
+
+	app, err := NewApplet(filename, []byte(src))
+	require.NoError(t, err)
+
+	bc := app.Globals["test_bar_chart.star"]["b"]
+	require.IsType(t, &render_runtime.BarChart{}, bc)
+
+	widget := bc.(*render_runtime.BarChart).AsRenderWidget()
+	require.IsType(t, &render.BarChart{}, widget)
+
+	chart := widget.(*render.BarChart)
+	require.Equal(t, 3, len(chart.Data))
+	assert.Equal(t, []float64{1}, chart.Data[0])
+	assert.Equal(t, []float64{2, 3}, chart.Data[1])
+	assert.Equal(t, 4.0, chart.Data[2][0])
+	assert.True(t, math.IsNaN(chart.Data[2][1]))
+
+	_, err = NewApplet("bad_bars.star", []byte(`
+load("render.star", "render")
+b = render.BarChart(data = ["foo"], width = 8, height = 4)
+def main():
+    return render.Root(child=b)
+`))
+	assert.Error(t, err)
+}
+
+func TestCanvas(t *testing.T) {
+	const (
+		filename = "test_canvas.star"
+		src      = `
+load("render.star", "render")
+c = render.Canvas(
+	width = 8,
+	height = 8,
+	ops = [
+		render.Pixel(x = 1, y = 2, color = "#f00"),
+		render.Line(x0 = 0, y0 = 0, x1 = 7, y1 = 7, end_frame = 2),
+		render.Label(x = 0, y = 1, content = "a", start_frame = 3),
+	],
+)
+def main():
+    return render.Root(child=c)
+`
+	)
+
+	app, err := NewApplet(filename, []byte(src))
+	require.NoError(t, err)
+
+	cv := app.Globals["test_canvas.star"]["c"]
+	require.IsType(t, &render_runtime.Canvas{}, cv)
+
+	widget := cv.(*render_runtime.Canvas).AsRenderWidget()
+	require.IsType(t, &render.Canvas{}, widget)
+
+	canvas := widget.(*render.Canvas)
+	require.Equal(t, 3, len(canvas.Ops))
+	require.IsType(t, &render.Pixel{}, canvas.Ops[0])
+	assert.Equal(t, 1, canvas.Ops[0].(*render.Pixel).X)
+	require.IsType(t, &render.Label{}, canvas.Ops[2])
+	assert.Equal(t, "a", canvas.Ops[2].(*render.Label).Content)
+	assert.Equal(t, 4, canvas.FrameCount())
+
+	_, err = NewApplet("bad_ops.star", []byte(`
+load("render.star", "render")
+c = render.Canvas(width = 8, height = 8, ops = [render.Box()])
+def main():
+    return render.Root(child=c)
+`))
+	assert.Error(t, err)
+}
+
 func TestImage(t *testing.T) {
 	// create a new PNG with a single blue pixel
 	bounds := image.Rect(0, 0, 64, 32)
@@ -247,3 +449,52 @@ def main():
 	assert.Equal(t, bounds, actualIm.Bounds())
 	assert.Equal(t, blue, actualIm.At(12, 12))
 }
+
+func TestImageCrop(t *testing.T) {
+	bounds := image.Rect(0, 0, 64, 32)
+	blue := color.RGBA{0, 0, 255, 255}
+
+	im := image.NewRGBA(bounds)
+	im.Set(12, 12, blue)
+
+	var p bytes.Buffer
+	require.NoError(t, png.Encode(&p, im))
+
+	const filename = "test_crop.star"
+	src := fmt.Sprintf(`
+load("render.star", "render")
+load("encoding/base64.star", "base64")
+
+img = render.Image(
+    src = base64.decode("%s"),
+    crop = (10, 10, 8, 4),
+    width = 16,
+    resample = "nearest",
+)
+def main():
+    return render.Root(child=img)
+
+`, base64.StdEncoding.EncodeToString(p.Bytes()))
+
+	app, err := NewApplet(filename, []byte(src))
+	require.NoError(t, err)
+
+	starlarkP := app.Globals["test_crop.star"]["img"]
+	require.IsType(t, &render_runtime.Image{}, starlarkP)
+
+	actualIm := render.PaintWidget(starlarkP.(*render_runtime.Image).AsRenderWidget(), image.Rect(0, 0, 64, 32), 0)
+	assert.Equal(t, image.Rect(0, 0, 16, 8), actualIm.Bounds())
+	assert.Equal(t, blue, actualIm.At(4, 4))
+	assert.Equal(t, blue, actualIm.At(5, 5))
+
+	for _, crop := range []string{"(1, 2, 3)", "(0, 0, -1, 4)", `("a", 0, 1, 1)`} {
+		_, err = NewApplet("bad_crop.star", []byte(fmt.Sprintf(`
+load("render.star", "render")
+load("encoding/base64.star", "base64")
+img = render.Image(src = base64.decode("%s"), crop = %s)
+def main():
+    return render.Root(child=img)
+`, base64.StdEncoding.EncodeToString(p.Bytes()), crop)))
+		assert.Error(t, err, crop)
+	}
+}
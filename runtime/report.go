@@ -0,0 +1,207 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.starlark.net/starlark"
+
+	"tidbyt.dev/pixlet/render"
+	"tidbyt.dev/pixlet/runtime/modules/starlarkhttp"
+)
+
+const (
+	// ThreadReportKey is the name of the Starlark thread-local that holds
+	// the report of the current run.
+	ThreadReportKey = "tidbyt.dev/pixlet/runtime/$report"
+)
+
+// Report is a structured trace of a single run of an applet. RunWithReport
+// fills in the time spent in Starlark along with every HTTP and cache call.
+// Painting and encoding happen outside the runtime, so callers that do them
+// fill in the remaining fields.
+type Report struct {
+	Starlark time.Duration
+	Paint    time.Duration
+	Encode   time.Duration
+
+	HTTP  []HTTPCall
+	Cache []CacheCall
+
+	Frames      int
+	EncodedSize int
+
+	mutex sync.Mutex
+}
+
+// HTTPCall is an HTTP request made by an applet.
+type HTTPCall struct {
+	Method  string
+	URL     string
+	Status  int
+	Latency time.Duration
+
+	// CacheStatus is HIT or MISS when the request went through the HTTP
+	// cache, and empty otherwise.
+	CacheStatus string
+
+	Err string
+}
+
+// CacheCall is a call to cache.get or cache.set made by an applet.
+type CacheCall struct {
+	Op  string
+	Key string
+
+	// Hit is whether cache.get found the key.
+	Hit bool
+
+	// TTL is the expiration passed to cache.set, in seconds.
+	TTL int64
+}
+
+// RunWithReport is like RunWithConfig, but also returns a report of the
+// run.
+func (a *Applet) RunWithReport(ctx context.Context, config map[string]string) ([]render.Root, *Report, error) {
+	report := &Report{}
+
+	start := time.Now()
+	roots, err := a.run(ctx, config, func(t *starlark.Thread) *starlark.Thread {
+		t.SetLocal(ThreadReportKey, report)
+		starlarkhttp.AttachTracerToThread(t, httpTracer(report.addHTTPCall))
+		return t
+	})
+	report.Starlark = time.Since(start)
+
+	return roots, report, err
+}
+
+func threadReport(thread *starlark.Thread) *Report {
+	report, _ := thread.Local(ThreadReportKey).(*Report)
+	return report
+}
+
+func (r *Report) addCacheCall(call CacheCall) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.Cache = append(r.Cache, call)
+}
+
+func (r *Report) addHTTPCall(call HTTPCall) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.HTTP = append(r.HTTP, call)
+}
+
+// WithHTTPTracer calls trace with every HTTP request made by the applet, as
+// soon as its response headers arrive or it fails.
+func WithHTTPTracer(trace func(HTTPCall)) AppletOption {
+	return WithThreadInitializer(func(t *starlark.Thread) *starlark.Thread {
+		starlarkhttp.AttachTracerToThread(t, httpTracer(trace))
+		return t
+	})
+}
+
+// httpTracer adapts a function to the starlarkhttp.Tracer interface.
+type httpTracer func(HTTPCall)
+
+func (trace httpTracer) TraceRequest(req *http.Request, res *http.Response, latency time.Duration, err error) {
+	call := HTTPCall{
+		Method:  req.Method,
+		URL:     req.URL.String(),
+		Latency: latency,
+	}
+
+	if err != nil {
+		call.Err = err.Error()
+	} else {
+		call.Status = res.StatusCode
+		call.CacheStatus = res.Header.Get("tidbyt-cache-status")
+	}
+
+	trace(call)
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// MarshalJSON serializes the report with all durations in milliseconds.
+func (r *Report) MarshalJSON() ([]byte, error) {
+	type timings struct {
+		Starlark float64 `json:"starlark_ms"`
+		Paint    float64 `json:"paint_ms"`
+		Encode   float64 `json:"encode_ms"`
+	}
+
+	http := r.HTTP
+	if http == nil {
+		http = []HTTPCall{}
+	}
+	cache := r.Cache
+	if cache == nil {
+		cache = []CacheCall{}
+	}
+
+	return json.Marshal(struct {
+		Timings     timings     `json:"timings"`
+		HTTP        []HTTPCall  `json:"http"`
+		Cache       []CacheCall `json:"cache"`
+		Frames      int         `json:"frames"`
+		EncodedSize int         `json:"encoded_size"`
+	}{
+		Timings: timings{
+			Starlark: milliseconds(r.Starlark),
+			Paint:    milliseconds(r.Paint),
+			Encode:   milliseconds(r.Encode),
+		},
+		HTTP:        http,
+		Cache:       cache,
+		Frames:      r.Frames,
+		EncodedSize: r.EncodedSize,
+	})
+}
+
+// MarshalJSON serializes the call with its latency in milliseconds.
+func (c HTTPCall) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Method      string  `json:"method"`
+		URL         string  `json:"url"`
+		Status      int     `json:"status,omitempty"`
+		Latency     float64 `json:"latency_ms"`
+		CacheStatus string  `json:"cache_status,omitempty"`
+		Err         string  `json:"error,omitempty"`
+	}{
+		Method:      c.Method,
+		URL:         c.URL,
+		Status:      c.Status,
+		Latency:     milliseconds(c.Latency),
+		CacheStatus: c.CacheStatus,
+		Err:         c.Err,
+	})
+}
+
+// MarshalJSON serializes the call, leaving out fields that don't apply to
+// its operation.
+func (c CacheCall) MarshalJSON() ([]byte, error) {
+	type get struct {
+		Op  string `json:"op"`
+		Key string `json:"key"`
+		Hit bool   `json:"hit"`
+	}
+	type set struct {
+		Op  string `json:"op"`
+		Key string `json:"key"`
+		TTL int64  `json:"ttl_seconds"`
+	}
+
+	if c.Op == "set" {
+		return json.Marshal(set{c.Op, c.Key, c.TTL})
+	}
+	return json.Marshal(get{c.Op, c.Key, c.Hit})
+}
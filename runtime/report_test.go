@@ -0,0 +1,73 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunWithReport(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(404)
+		}
+		fmt.Fprint(w, "hello")
+	}))
+	defer ts.Close()
+
+	InitHTTP(NewInMemoryCache())
+	InitCache(NewInMemoryCache())
+	defer InitCache(nil)
+
+	src := fmt.Sprintf(`
+load("cache.star", "cache")
+load("http.star", "http")
+load("render.star", "render")
+
+def main(config):
+    cache.get("greeting")
+    cache.set("greeting", "hello", ttl_seconds = 30)
+    cache.get("greeting")
+    http.get("%[1]s/hello")
+    http.get("%[1]s/hello")
+    http.get("%[1]s/missing")
+    return render.Root(child = render.Box())
+`, ts.URL)
+
+	app, err := NewApplet("reporter", []byte(src))
+	require.NoError(t, err)
+
+	roots, report, err := app.RunWithReport(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Len(t, roots, 1)
+	assert.Greater(t, report.Starlark, report.HTTP[0].Latency)
+
+	assert.Equal(t, []CacheCall{
+		{Op: "get", Key: "greeting"},
+		{Op: "set", Key: "greeting", TTL: 30},
+		{Op: "get", Key: "greeting", Hit: true},
+	}, report.Cache)
+
+	require.Len(t, report.HTTP, 3)
+	assert.Equal(t, "GET", report.HTTP[0].Method)
+	assert.Equal(t, ts.URL+"/hello", report.HTTP[0].URL)
+	assert.Equal(t, 200, report.HTTP[0].Status)
+	assert.Equal(t, "MISS", report.HTTP[0].CacheStatus)
+	assert.Equal(t, "HIT", report.HTTP[1].CacheStatus)
+	assert.Equal(t, 404, report.HTTP[2].Status)
+
+	b, err := json.Marshal(report)
+	require.NoError(t, err)
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	assert.Contains(t, decoded["timings"], "starlark_ms")
+	assert.Equal(t, "HIT", decoded["http"].([]interface{})[1].(map[string]interface{})["cache_status"])
+	assert.Equal(t, map[string]interface{}{"op": "set", "key": "greeting", "ttl_seconds": float64(30)}, decoded["cache"].([]interface{})[1])
+}
@@ -10,11 +10,13 @@ import (
 	"html/template"
 	"log"
 	"net/http"
+	"strconv"
 
 	"github.com/gorilla/mux"
 	"github.com/gorilla/websocket"
 	"golang.org/x/sync/errgroup"
 	"tidbyt.dev/pixlet/dist"
+	"tidbyt.dev/pixlet/render"
 	"tidbyt.dev/pixlet/server/fanout"
 	"tidbyt.dev/pixlet/server/loader"
 )
@@ -50,6 +52,18 @@ type previewData struct {
 	Watch  bool      `json:"-"`
 	Err    string    `json:"error,omitempty"`
 }
+
+// layoutMessage is the layout of a frame, along with the size of the
+// frame so that the browser can scale it to the preview, and the number
+// of frames to pick from.
+type layoutMessage struct {
+	Width  int                `json:"width"`
+	Height int                `json:"height"`
+	Frame  int                `json:"frame"`
+	Frames int                `json:"frames"`
+	Layout *render.LayoutNode `json:"layout"`
+}
+
 type handlerRequest struct {
 	ID    string `json:"id"`
 	Param string `json:"param"`
@@ -98,6 +112,7 @@ func NewBrowser(addr string, title string, watch bool, updateChan chan loader.Up
 	r.HandleFunc("/api/v1/preview.gif", b.imageHandler)
 	r.HandleFunc("/api/v1/push", b.pushHandler)
 	r.HandleFunc("/api/v1/schema", b.schemaHandler).Methods("GET")
+	r.HandleFunc("/api/v1/layout", b.layoutHandler).Methods("GET")
 	r.HandleFunc("/api/v1/handlers/{handler}", b.schemaHandlerHandler).Methods("POST")
 	r.HandleFunc("/api/v1/ws", b.websocketHandler)
 	b.r = r
@@ -118,6 +133,19 @@ func (b *Browser) Run() error {
 	return g.Wait()
 }
 
+// RunUpdates sends updates to connected clients in a blocking fashion, without
+// serving HTTP. Together with ServeHTTP, it allows mounting the browser in
+// another server.
+func (b *Browser) RunUpdates() error {
+	defer b.fo.Quit()
+	return b.updateWatcher()
+}
+
+// ServeHTTP serves the browser's pages and API.
+func (b *Browser) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.r.ServeHTTP(w, r)
+}
+
 func (b *Browser) faviconHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "image/png")
 	w.Write(favicon)
@@ -133,6 +161,42 @@ func (b *Browser) schemaHandler(w http.ResponseWriter, r *http.Request) {
 	w.Write(b.loader.GetSchema())
 }
 
+// layoutHandler serves the layout of a frame of the last render, for
+// outlining widgets on top of the preview. The frame is picked with the
+// frame query parameter, and defaults to the first one.
+func (b *Browser) layoutHandler(w http.ResponseWriter, r *http.Request) {
+	frame := 0
+	if v := r.URL.Query().Get("frame"); v != "" {
+		var err error
+		if frame, err = strconv.Atoi(v); err != nil {
+			w.WriteHeader(400)
+			fmt.Fprintf(w, "invalid frame: %s\n", v)
+			return
+		}
+	}
+
+	layout, frames, err := b.loader.Layout(frame)
+	if err != nil {
+		w.WriteHeader(400)
+		fmt.Fprintln(w, err)
+		return
+	}
+	if layout == nil {
+		w.WriteHeader(404)
+		fmt.Fprintln(w, "no layout")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(layoutMessage{
+		Width:  render.FrameWidth,
+		Height: render.FrameHeight,
+		Frame:  frame,
+		Frames: frames,
+		Layout: layout,
+	})
+}
+
 func (b *Browser) schemaHandlerHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	if _, ok := vars["handler"]; !ok {
@@ -284,7 +348,40 @@ func (b *Browser) updateWatcher() error {
 					},
 				)
 			}
+
+		case ev := <-b.loader.Events():
+			b.broadcastEvent(ev)
+		}
+	}
+}
+
+// broadcastEvent sends an event from a render in progress to the
+// inspector panel.
+func (b *Browser) broadcastEvent(ev loader.Event) {
+	switch ev.Type {
+	case loader.EventRender:
+		b.fo.Broadcast(fanout.WebsocketEvent{Type: fanout.EventTypeRender})
+
+	case loader.EventHTTP:
+		msg, err := json.Marshal(ev.HTTP)
+		if err != nil {
+			log.Printf("serializing HTTP event: %v", err)
+			return
 		}
+		b.fo.Broadcast(
+			fanout.WebsocketEvent{
+				Type:    fanout.EventTypeHTTP,
+				Message: string(msg),
+			},
+		)
+
+	case loader.EventPrint:
+		b.fo.Broadcast(
+			fanout.WebsocketEvent{
+				Type:    fanout.EventTypePrint,
+				Message: ev.Message,
+			},
+		)
 	}
 }
 func (b *Browser) rootHandler(w http.ResponseWriter, r *http.Request) {
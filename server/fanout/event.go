@@ -12,6 +12,17 @@ const (
 	// EventTypeErr is used to signal there was an error encountered rendering
 	// the image.
 	EventTypeErr = "error"
+
+	// EventTypeRender is used to signal that the app started rendering.
+	EventTypeRender = "render"
+
+	// EventTypeHTTP is used to send an HTTP request made by the app while
+	// rendering. The message is the request, JSON encoded.
+	EventTypeHTTP = "http"
+
+	// EventTypePrint is used to send the output of a print() call made by
+	// the app while rendering.
+	EventTypePrint = "print"
 )
 
 // WebsocketEvent is a structure used to send messages over the socket.
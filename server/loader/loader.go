@@ -9,9 +9,13 @@ import (
 	"fmt"
 	"io/fs"
 	"log"
+	"sync"
 	"time"
 
+	"go.starlark.net/starlark"
+
 	"tidbyt.dev/pixlet/encode"
+	"tidbyt.dev/pixlet/render"
 	"tidbyt.dev/pixlet/runtime"
 	"tidbyt.dev/pixlet/schema"
 )
@@ -19,6 +23,7 @@ import (
 // Loader is a structure to provide applet loading when a file changes or on
 // demand.
 type Loader struct {
+	appID            string
 	fs               fs.FS
 	fileChanges      chan bool
 	watch            bool
@@ -31,6 +36,13 @@ type Loader struct {
 	initialLoad      chan bool
 	timeout          int
 	renderGif		 bool
+	programs         runtime.ProgramCache
+	appletOpts       []runtime.AppletOption
+	events           chan Event
+
+	// roots of the last render, for inspecting its layout
+	rootsMu sync.Mutex
+	roots   []render.Root
 }
 
 type Update struct {
@@ -40,11 +52,41 @@ type Update struct {
 	Err       error
 }
 
+type EventType int
+
+const (
+	// EventRender marks the start of a render.
+	EventRender EventType = iota
+
+	// EventHTTP is an HTTP request made by the applet.
+	EventHTTP
+
+	// EventPrint is a call to print() made by the applet.
+	EventPrint
+)
+
+// Event is something the applet did while rendering, for inspecting what
+// happens during a render as it happens.
+type Event struct {
+	Type EventType
+
+	// HTTP is the request, for EventHTTP.
+	HTTP runtime.HTTPCall
+
+	// Message is the printed message, for EventPrint.
+	Message string
+}
+
 // NewLoader instantiates a new loader structure. The loader will read off of
 // fileChanges channel and write updates to the updatesChan. Updates are base64
 // encoded WebP strings. If watch is enabled, both file changes and on demand
-// requests will send updates over the updatesChan.
+// requests will send updates over the updatesChan. appletOpts are used every
+// time the applet is loaded. appID identifies the applet, and scopes its cache
+// keys. The HTTP client and cache are shared by every loader in the process,
+// so they must be set up beforehand with runtime.InitHTTP and
+// runtime.InitCache.
 func NewLoader(
+	appID string,
 	fs fs.FS,
 	watch bool,
 	fileChanges chan bool,
@@ -52,8 +94,10 @@ func NewLoader(
 	maxDuration int,
 	timeout int,
 	renderGif bool,
+	appletOpts ...runtime.AppletOption,
 ) (*Loader, error) {
 	l := &Loader{
+		appID:            appID,
 		fs:               fs,
 		fileChanges:      fileChanges,
 		watch:            watch,
@@ -66,14 +110,17 @@ func NewLoader(
 		initialLoad:      make(chan bool),
 		timeout:          timeout,
 		renderGif:        renderGif,
+		programs:         runtime.NewInMemoryProgramCache(),
+		events:           make(chan Event, 100),
 	}
 
-	cache := runtime.NewInMemoryCache()
-	runtime.InitHTTP(cache)
-	runtime.InitCache(cache)
+	l.appletOpts = append([]runtime.AppletOption{
+		runtime.WithPrintFunc(l.print),
+		runtime.WithHTTPTracer(l.traceHTTP),
+	}, appletOpts...)
 
 	if !l.watch {
-		app, err := loadScript("app-id", l.fs)
+		app, err := loadScript(l.appID, l.fs, l.appletOpts...)
 		l.markInitialLoadComplete()
 		if err != nil {
 			return nil, err
@@ -166,9 +213,64 @@ func (l *Loader) CallSchemaHandler(ctx context.Context, handlerName, parameter s
 	return l.applet.CallSchemaHandler(ctx, handlerName, parameter)
 }
 
+// Layout returns the layout of a frame of the last render, along with
+// the number of frames in it. Like with `pixlet render --layout-frame`,
+// frameIdx counts the frames of every root in turn. The layout is nil if
+// the last render didn't return anything. It's only worked out when asked
+// for, since it means painting the frame again.
+func (l *Loader) Layout(frameIdx int) (*render.LayoutNode, int, error) {
+	l.rootsMu.Lock()
+	roots := l.roots
+	l.rootsMu.Unlock()
+
+	if len(roots) == 0 {
+		return nil, 0, nil
+	}
+
+	frames := 0
+	for _, r := range roots {
+		frames += r.Child.FrameCount()
+	}
+
+	layout, _, err := render.InspectRootsLayout(roots, frameIdx)
+	if err != nil {
+		return nil, frames, err
+	}
+
+	return layout, frames, nil
+}
+
+// Events returns a channel of the events that happen while the applet is
+// rendering.
+func (l *Loader) Events() <-chan Event {
+	return l.events
+}
+
+// emit sends an event without ever blocking the render. Events are dropped
+// if nobody is keeping up with them.
+func (l *Loader) emit(ev Event) {
+	select {
+	case l.events <- ev:
+	default:
+	}
+}
+
+func (l *Loader) print(thread *starlark.Thread, msg string) {
+	fmt.Printf("[%s] %s\n", thread.Name, msg)
+	l.emit(Event{Type: EventPrint, Message: msg})
+}
+
+func (l *Loader) traceHTTP(call runtime.HTTPCall) {
+	l.emit(Event{Type: EventHTTP, HTTP: call})
+}
+
 func (l *Loader) loadApplet(config map[string]string) (string, error) {
+	l.emit(Event{Type: EventRender})
+
 	if l.watch {
-		app, err := loadScript("app-id", l.fs)
+		// unchanged files are not recompiled when reloading
+		opts := append([]runtime.AppletOption{runtime.WithProgramCache(l.programs)}, l.appletOpts...)
+		app, err := loadScript(l.appID, l.fs, opts...)
 		l.markInitialLoadComplete()
 		if err != nil {
 			return "", err
@@ -184,6 +286,11 @@ func (l *Loader) loadApplet(config map[string]string) (string, error) {
 	)
 
 	roots, err := l.applet.RunWithConfig(ctx, config)
+
+	l.rootsMu.Lock()
+	l.roots = roots
+	l.rootsMu.Unlock()
+
 	if err != nil {
 		return "", fmt.Errorf("error running script: %w", err)
 	}
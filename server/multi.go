@@ -0,0 +1,183 @@
+package server
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"golang.org/x/sync/errgroup"
+
+	"tidbyt.dev/pixlet/dist"
+	"tidbyt.dev/pixlet/runtime"
+	"tidbyt.dev/pixlet/server/browser"
+	"tidbyt.dev/pixlet/server/loader"
+)
+
+//go:embed index.html
+var indexHTML string
+
+var invalidIDChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// MultiServer serves several applets from one address. Each applet is
+// mounted under /apps/{id}, with its own watcher, loader and browser, and
+// the index page links to all of them.
+type MultiServer struct {
+	addr     string
+	apps     []*mountedApplet
+	watch    bool
+	serveGif bool
+	index    *template.Template
+	r        *mux.Router
+}
+
+type mountedApplet struct {
+	ID      string
+	watcher *Watcher
+	loader  *loader.Loader
+	browser *browser.Browser
+}
+
+// NewMultiServer creates a server for the applets at paths. Applet IDs are
+// derived from the paths' base names.
+func NewMultiServer(host string, port int, watch bool, paths []string, maxDuration int, timeout int, serveGif bool, appletOpts ...runtime.AppletOption) (*MultiServer, error) {
+	tmpl, err := template.New("index").Parse(indexHTML)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &MultiServer{
+		addr:     fmt.Sprintf("%s:%d", host, port),
+		watch:    watch,
+		serveGif: serveGif,
+		index:    tmpl,
+		r:        mux.NewRouter(),
+	}
+
+	ids := map[string]bool{}
+	for _, path := range paths {
+		id := appletID(path)
+		for i := 2; ids[id]; i++ {
+			id = fmt.Sprintf("%s-%d", appletID(path), i)
+		}
+		ids[id] = true
+
+		w, l, b, err := newApplet(s.addr, id, path, watch, maxDuration, timeout, serveGif, appletOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("loading %s: %w", path, err)
+		}
+
+		s.apps = append(s.apps, &mountedApplet{
+			ID:      id,
+			watcher: w,
+			loader:  l,
+			browser: b,
+		})
+
+		prefix := "/apps/" + id
+		s.r.Handle(prefix, http.RedirectHandler(prefix+"/", http.StatusMovedPermanently))
+		s.r.PathPrefix(prefix + "/").Handler(http.StripPrefix(prefix, b))
+	}
+
+	s.r.HandleFunc("/", s.indexHandler)
+	s.r.PathPrefix("/static").Handler(http.FileServer(http.FS(dist.Static)))
+
+	return s, nil
+}
+
+// FindApplets returns the applets at path. If path is an applet itself,
+// either a .star file or a directory with .star files in it, that's the only
+// one. Otherwise, every subdirectory of path that is an applet is returned.
+func FindApplets(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("stat'ing %s: %w", path, err)
+	}
+
+	if !info.IsDir() || isAppletDir(path) {
+		return []string{path}, nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	var applets []string
+	for _, e := range entries {
+		dir := filepath.Join(path, e.Name())
+		if e.IsDir() && isAppletDir(dir) {
+			applets = append(applets, dir)
+		}
+	}
+
+	if len(applets) == 0 {
+		return nil, fmt.Errorf("no apps found in %s", path)
+	}
+
+	sort.Strings(applets)
+	return applets, nil
+}
+
+func isAppletDir(dir string) bool {
+	matches, _ := filepath.Glob(filepath.Join(dir, "*.star"))
+	return len(matches) > 0
+}
+
+// appletID derives a URL safe ID from an applet's path.
+func appletID(path string) string {
+	name := strings.TrimSuffix(filepath.Base(path), ".star")
+	name = strings.Trim(invalidIDChars.ReplaceAllString(name, "-"), "-")
+	if name == "" {
+		return "app"
+	}
+	return name
+}
+
+// Run serves all applets and runs forever in a blocking fashion.
+func (s *MultiServer) Run() error {
+	g := errgroup.Group{}
+
+	for _, app := range s.apps {
+		app := app
+		g.Go(app.loader.Run)
+		g.Go(app.browser.RunUpdates)
+		if s.watch {
+			g.Go(app.watcher.Run)
+			g.Go(func() error {
+				app.loader.LoadApplet(make(map[string]string))
+				return nil
+			})
+		}
+	}
+
+	g.Go(func() error {
+		log.Printf("serving %d apps at http://%s\n", len(s.apps), s.addr)
+		return http.ListenAndServe(s.addr, s.r)
+	})
+
+	return g.Wait()
+}
+
+func (s *MultiServer) indexHandler(w http.ResponseWriter, r *http.Request) {
+	imageType := "webp"
+	if s.serveGif {
+		imageType = "gif"
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	s.index.Execute(w, struct {
+		Apps      []*mountedApplet
+		ImageType string
+	}{
+		Apps:      s.apps,
+		ImageType: imageType,
+	})
+}
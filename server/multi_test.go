@@ -0,0 +1,95 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"tidbyt.dev/pixlet/runtime"
+)
+
+func TestFindApplets(t *testing.T) {
+	dir := t.TempDir()
+	for _, f := range []string{
+		"clock/clock.star",
+		"weather/weather.star",
+		"weather/util.star",
+		"docs/README.md",
+	} {
+		path := filepath.Join(dir, f)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, nil, 0644))
+	}
+
+	applets, err := FindApplets(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "clock"),
+		filepath.Join(dir, "weather"),
+	}, applets)
+
+	// an applet directory is served on its own
+	applets, err = FindApplets(filepath.Join(dir, "weather"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "weather")}, applets)
+
+	applets, err = FindApplets(filepath.Join(dir, "clock", "clock.star"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "clock", "clock.star")}, applets)
+
+	_, err = FindApplets(filepath.Join(dir, "docs"))
+	assert.ErrorContains(t, err, "no apps found")
+}
+
+func TestAppletID(t *testing.T) {
+	assert.Equal(t, "clock", appletID("examples/clock"))
+	assert.Equal(t, "hello_world", appletID("examples/hello_world/hello_world.star"))
+	assert.Equal(t, "my-app", appletID("apps/my app!"))
+	assert.Equal(t, "app", appletID("apps/???"))
+}
+
+func TestMultiServerAppletIDs(t *testing.T) {
+	dir := t.TempDir()
+	for name, src := range map[string]string{
+		"writer": `
+load("cache.star", "cache")
+load("render.star", "render")
+
+def main():
+    cache.set("key", "writer")
+    return render.Root(child = render.Box())
+`,
+		"reader": `
+load("cache.star", "cache")
+load("render.star", "render")
+
+def main():
+    if cache.get("key") != None:
+        fail("read the cache of another app")
+    return render.Root(child = render.Box())
+`,
+	} {
+		path := filepath.Join(dir, name, name+".star")
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(src), 0644))
+	}
+
+	// apps share the cache, but not their keys
+	runtime.InitCache(runtime.NewInMemoryCache())
+	defer runtime.InitCache(nil)
+
+	s, err := NewMultiServer("127.0.0.1", 0, false, []string{
+		filepath.Join(dir, "writer"),
+		filepath.Join(dir, "reader"),
+	}, 15000, 30000, false)
+	require.NoError(t, err)
+
+	for _, app := range s.apps {
+		go app.loader.Run()
+		_, err := app.loader.LoadApplet(map[string]string{})
+		assert.NoError(t, err, app.ID)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"golang.org/x/sync/errgroup"
+	"tidbyt.dev/pixlet/runtime"
 	"tidbyt.dev/pixlet/server/browser"
 	"tidbyt.dev/pixlet/server/loader"
 	"tidbyt.dev/pixlet/tools"
@@ -22,14 +23,38 @@ type Server struct {
 	watch   bool
 }
 
+// singleAppletID is the ID of the applet served by Server. Applets
+// mounted by MultiServer are identified by their paths instead.
+const singleAppletID = "app-id"
+
 // NewServer creates a new server initialized with the applet.
-func NewServer(host string, port int, watch bool, path string, maxDuration int, timeout int, serveGif bool) (*Server, error) {
+//
+// Applet options, such as resource limits, are applied every time the
+// applet is loaded.
+func NewServer(host string, port int, watch bool, path string, maxDuration int, timeout int, serveGif bool, appletOpts ...runtime.AppletOption) (*Server, error) {
+	addr := fmt.Sprintf("%s:%d", host, port)
+	w, l, b, err := newApplet(addr, singleAppletID, path, watch, maxDuration, timeout, serveGif, appletOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Server{
+		watcher: w,
+		browser: b,
+		loader:  l,
+		watch:   watch,
+	}, nil
+}
+
+// newApplet sets up the watcher, loader and browser for the applet at path,
+// identified by id.
+func newApplet(addr string, id string, path string, watch bool, maxDuration int, timeout int, serveGif bool, appletOpts ...runtime.AppletOption) (*Watcher, *loader.Loader, *browser.Browser, error) {
 	fileChanges := make(chan bool, 100)
 
 	// check if path exists, and whether it is a directory or a file
 	info, err := os.Stat(path)
 	if err != nil {
-		return nil, fmt.Errorf("stat'ing %s: %w", path, err)
+		return nil, nil, nil, fmt.Errorf("stat'ing %s: %w", path, err)
 	}
 
 	var fs fs.FS
@@ -39,7 +64,7 @@ func NewServer(host string, port int, watch bool, path string, maxDuration int,
 		w = NewWatcher(path, fileChanges)
 	} else {
 		if !strings.HasSuffix(path, ".star") {
-			return nil, fmt.Errorf("script file must have suffix .star: %s", path)
+			return nil, nil, nil, fmt.Errorf("script file must have suffix .star: %s", path)
 		}
 
 		fs = tools.NewSingleFileFS(path)
@@ -47,23 +72,17 @@ func NewServer(host string, port int, watch bool, path string, maxDuration int,
 	}
 
 	updatesChan := make(chan loader.Update, 100)
-	l, err := loader.NewLoader(fs, watch, fileChanges, updatesChan, maxDuration, timeout, serveGif)
+	l, err := loader.NewLoader(id, fs, watch, fileChanges, updatesChan, maxDuration, timeout, serveGif, appletOpts...)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 
-	addr := fmt.Sprintf("%s:%d", host, port)
 	b, err := browser.NewBrowser(addr, filepath.Base(path), watch, updatesChan, l, serveGif)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 
-	return &Server{
-		watcher: w,
-		browser: b,
-		loader:  l,
-		watch:   watch,
-	}, nil
+	return w, l, b, nil
 }
 
 // Run serves the http server and runs forever in a blocking fashion.
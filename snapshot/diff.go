@@ -0,0 +1,141 @@
+package snapshot
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// diffScale magnifies diff images, since frames are usually far too small
+// to inspect at their original size.
+const diffScale = 4
+
+var (
+	diffChanged   = color.RGBA{0xff, 0, 0xff, 0xff}
+	diffSeparator = color.RGBA{0x40, 0x40, 0x40, 0xff}
+)
+
+// Mismatch describes how a frame differs from its golden.
+type Mismatch struct {
+	// Frame is the index of the frame.
+	Frame int
+
+	// Changed is the number of pixels that differ.
+	Changed int
+
+	// Bounds is the smallest rectangle containing every changed pixel.
+	Bounds image.Rectangle
+
+	// Path is the location of the diff image.
+	Path string
+}
+
+func (m Mismatch) String() string {
+	return fmt.Sprintf("frame %d: %d pixels differ within %v", m.Frame, m.Changed, m.Bounds)
+}
+
+// Compare counts the pixels that differ between two images. Pixels whose
+// color channels all differ by at most tolerance are considered equal.
+// Images of different sizes differ in every pixel.
+func Compare(expected, actual image.Image, tolerance uint8) Mismatch {
+	m := Mismatch{}
+
+	eb, ab := expected.Bounds(), actual.Bounds()
+	if eb.Size() != ab.Size() {
+		m.Bounds = image.Rectangle{Max: eb.Size()}.Union(image.Rectangle{Max: ab.Size()})
+		m.Changed = m.Bounds.Dx() * m.Bounds.Dy()
+		return m
+	}
+
+	for y := 0; y < eb.Dy(); y++ {
+		for x := 0; x < eb.Dx(); x++ {
+			e := expected.At(eb.Min.X+x, eb.Min.Y+y)
+			a := actual.At(ab.Min.X+x, ab.Min.Y+y)
+			if colorsEqual(e, a, tolerance) {
+				continue
+			}
+
+			m.Changed++
+			m.Bounds = m.Bounds.Union(image.Rect(x, y, x+1, y+1))
+		}
+	}
+
+	return m
+}
+
+// DiffImage returns a magnified image with the expected frame on the left,
+// the actual frame in the middle, and the difference between them on the
+// right. In the difference, changed pixels are highlighted and unchanged
+// ones are dimmed.
+func DiffImage(expected, actual image.Image, tolerance uint8) image.Image {
+	eb, ab := expected.Bounds(), actual.Bounds()
+	w := max(eb.Dx(), ab.Dx())
+	h := max(eb.Dy(), ab.Dy())
+
+	out := image.NewRGBA(image.Rect(0, 0, (3*w+2)*diffScale, h*diffScale))
+	draw.Draw(out, out.Bounds(), image.NewUniform(diffSeparator), image.Point{}, draw.Src)
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			e, eok := pixelAt(expected, x, y)
+			a, aok := pixelAt(actual, x, y)
+
+			var d color.Color
+			if eok && aok && colorsEqual(e, a, tolerance) {
+				d = dim(e)
+			} else {
+				d = diffChanged
+			}
+
+			if eok {
+				fillScaled(out, x, y, e)
+			}
+			if aok {
+				fillScaled(out, w+1+x, y, a)
+			}
+			fillScaled(out, 2*w+2+x, y, d)
+		}
+	}
+
+	return out
+}
+
+func pixelAt(im image.Image, x, y int) (color.Color, bool) {
+	b := im.Bounds()
+	if x >= b.Dx() || y >= b.Dy() {
+		return nil, false
+	}
+	return im.At(b.Min.X+x, b.Min.Y+y), true
+}
+
+func fillScaled(im *image.RGBA, x, y int, c color.Color) {
+	r := image.Rect(x*diffScale, y*diffScale, (x+1)*diffScale, (y+1)*diffScale)
+	draw.Draw(im, r, image.NewUniform(c), image.Point{}, draw.Src)
+}
+
+// dim converts a color to a darkened grayscale, so that changed pixels
+// stand out.
+func dim(c color.Color) color.Color {
+	g := color.GrayModel.Convert(c).(color.Gray)
+	return color.Gray{Y: g.Y / 4}
+}
+
+func colorsEqual(a, b color.Color, tolerance uint8) bool {
+	ar, ag, ab, aa := a.RGBA()
+	br, bg, bb, ba := b.RGBA()
+
+	// scale the tolerance to the 16-bit channels returned by RGBA()
+	t := uint32(tolerance) * 0x101
+	return absDiff(ar, br) <= t &&
+		absDiff(ag, bg) <= t &&
+		absDiff(ab, bb) <= t &&
+		absDiff(aa, ba) <= t
+}
+
+func absDiff(a, b uint32) uint32 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
@@ -0,0 +1,210 @@
+package snapshot
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"tidbyt.dev/pixlet/encode"
+	"tidbyt.dev/pixlet/render"
+)
+
+// Format is the file format golden frames are stored in.
+type Format string
+
+const (
+	// FormatPNG stores every frame as a separate, lossless PNG file in a
+	// directory named after the snapshot.
+	FormatPNG Format = "png"
+
+	// FormatWebP stores all frames in a single animated WebP file named
+	// after the snapshot.
+	FormatWebP Format = "webp"
+)
+
+var validCaseName = regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
+
+// golden is the set of golden frames of a single snapshot.
+type golden struct {
+	dir  string
+	name string
+}
+
+func (g golden) pngDir() string {
+	return filepath.Join(g.dir, g.name)
+}
+
+func (g golden) webpPath() string {
+	return filepath.Join(g.dir, g.name+".webp")
+}
+
+func (g golden) framePath(i int) string {
+	return filepath.Join(g.pngDir(), fmt.Sprintf("%03d.png", i))
+}
+
+// read returns the golden frames, and the format they were stored in.
+func (g golden) read() ([]image.Image, Format, error) {
+	if _, err := os.Stat(g.pngDir()); err == nil {
+		frames, err := g.readPNG()
+		return frames, FormatPNG, err
+	}
+
+	if _, err := os.Stat(g.webpPath()); err == nil {
+		frames, err := g.readWebP()
+		return frames, FormatWebP, err
+	}
+
+	return nil, "", fmt.Errorf("no golden frames for snapshot %q in %s, run with --update to create them", g.name, g.dir)
+}
+
+func (g golden) readPNG() ([]image.Image, error) {
+	var frames []image.Image
+
+	for i := 0; ; i++ {
+		f, err := os.Open(g.framePath(i))
+		if errors.Is(err, fs.ErrNotExist) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading golden frame: %w", err)
+		}
+
+		im, err := png.Decode(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("decoding golden frame %s: %w", g.framePath(i), err)
+		}
+
+		frames = append(frames, im)
+	}
+
+	return frames, nil
+}
+
+func (g golden) readWebP() ([]image.Image, error) {
+	data, err := os.ReadFile(g.webpPath())
+	if err != nil {
+		return nil, fmt.Errorf("reading golden: %w", err)
+	}
+
+	frames, err := decodeWebP(data)
+	if err != nil {
+		return nil, fmt.Errorf("decoding golden %s: %w", g.webpPath(), err)
+	}
+
+	return frames, nil
+}
+
+// decodeWebP returns the frames of an animated WebP. Since render.Image
+// already knows how to decode them, it's simplest to paint each of its
+// frames.
+func decodeWebP(data []byte) ([]image.Image, error) {
+	im := &render.Image{Src: string(data)}
+	if err := im.Init(); err != nil {
+		return nil, err
+	}
+
+	frames := make([]image.Image, im.FrameCount())
+	for i := range frames {
+		frames[i] = render.PaintWidget(im, im.PaintBounds(image.Rectangle{}, i), i)
+	}
+
+	return frames, nil
+}
+
+// write replaces any existing goldens of the snapshot.
+func (g golden) write(screens *encode.Screens, frames []image.Image, format Format) error {
+	if err := os.RemoveAll(g.pngDir()); err != nil {
+		return fmt.Errorf("removing old goldens: %w", err)
+	}
+	if err := os.Remove(g.webpPath()); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("removing old goldens: %w", err)
+	}
+
+	switch format {
+	case FormatWebP:
+		if err := os.MkdirAll(g.dir, 0755); err != nil {
+			return fmt.Errorf("creating golden directory: %w", err)
+		}
+
+		buf, err := screens.EncodeWebP(0)
+		if err != nil {
+			return fmt.Errorf("encoding golden: %w", err)
+		}
+
+		if err := os.WriteFile(g.webpPath(), buf, 0644); err != nil {
+			return fmt.Errorf("writing golden: %w", err)
+		}
+
+	case FormatPNG, "":
+		if err := os.MkdirAll(g.pngDir(), 0755); err != nil {
+			return fmt.Errorf("creating golden directory: %w", err)
+		}
+
+		for i, im := range frames {
+			if err := writePNG(g.framePath(i), im); err != nil {
+				return fmt.Errorf("writing golden frame: %w", err)
+			}
+		}
+
+	default:
+		return fmt.Errorf("unsupported golden format: %s", format)
+	}
+
+	return nil
+}
+
+// diffDir is where diff images are written. It's a sibling of the golden
+// directory rather than inside it, so that it's easy to ignore in version
+// control.
+func (g golden) diffDir() string {
+	return filepath.Join(filepath.Clean(g.dir)+"-diff", g.name)
+}
+
+func (g golden) writeDiff(i int, im image.Image) (string, error) {
+	if err := os.MkdirAll(g.diffDir(), 0755); err != nil {
+		return "", fmt.Errorf("creating diff directory: %w", err)
+	}
+
+	path := filepath.Join(g.diffDir(), fmt.Sprintf("%03d.png", i))
+	if err := writePNG(path, im); err != nil {
+		return "", fmt.Errorf("writing diff: %w", err)
+	}
+
+	return path, nil
+}
+
+// clearDiffs removes diff images left over from a previous run, so that
+// only diffs for currently mismatching frames remain.
+func (g golden) clearDiffs() {
+	entries, err := os.ReadDir(g.diffDir())
+	if err != nil {
+		return
+	}
+
+	for _, e := range entries {
+		if strings.HasSuffix(e.Name(), ".png") {
+			os.Remove(filepath.Join(g.diffDir(), e.Name()))
+		}
+	}
+}
+
+func writePNG(path string, im image.Image) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := png.Encode(f, im); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
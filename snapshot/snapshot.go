@@ -0,0 +1,243 @@
+// Package snapshot provides golden image testing for applets. It renders an
+// applet with a set of configs, and compares every frame against golden
+// frames stored alongside the applet.
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"tidbyt.dev/pixlet/encode"
+	"tidbyt.dev/pixlet/runtime"
+)
+
+const (
+	// SuiteFileName is the default name of the file that lists the
+	// snapshots of an applet.
+	SuiteFileName = "snapshots.yaml"
+
+	// GoldenDirName is the default name of the directory that golden
+	// frames are stored in.
+	GoldenDirName = "snapshots"
+
+	// DefaultCaseName is the name of the snapshot taken when an applet
+	// doesn't have a suite file.
+	DefaultCaseName = "default"
+)
+
+// Case is a single snapshot: the applet rendered with a specific config.
+type Case struct {
+	Name   string            `json:"name" yaml:"name"`
+	Config map[string]string `json:"config" yaml:"config"`
+}
+
+// Suite is the list of snapshots taken of an applet.
+type Suite struct {
+	Cases []Case `json:"snapshots" yaml:"snapshots"`
+}
+
+// DefaultSuite returns a suite with a single snapshot of the applet run
+// without any config.
+func DefaultSuite() *Suite {
+	return &Suite{Cases: []Case{{Name: DefaultCaseName}}}
+}
+
+// LoadSuite reads a suite from an io.Reader, with the most common reader
+// being a file from os.Open.
+func LoadSuite(r io.Reader) (*Suite, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("could not read snapshot suite: %w", err)
+	}
+
+	suite := &Suite{}
+	if err := yaml.Unmarshal(b, suite); err != nil {
+		return nil, fmt.Errorf("could not unmarshal snapshot suite: %w", err)
+	}
+
+	if err := suite.Validate(); err != nil {
+		return nil, err
+	}
+
+	return suite, nil
+}
+
+// Validate ensures every snapshot has a unique name that can be used as a
+// file name.
+func (s *Suite) Validate() error {
+	names := map[string]bool{}
+
+	for i, c := range s.Cases {
+		if c.Name == "" {
+			return fmt.Errorf("snapshot %d has no name", i)
+		}
+
+		if !validCaseName.MatchString(c.Name) {
+			return fmt.Errorf("snapshot name %q may only contain letters, digits, '-', '_' and '.'", c.Name)
+		}
+
+		if names[c.Name] {
+			return fmt.Errorf("duplicate snapshot name %q", c.Name)
+		}
+		names[c.Name] = true
+	}
+
+	return nil
+}
+
+// Runner renders snapshots of an applet and compares them to its golden
+// frames.
+type Runner struct {
+	Applet *runtime.Applet
+
+	// GoldenDir is the directory that golden frames are read from and
+	// written to.
+	GoldenDir string
+
+	// Update rewrites the golden frames instead of comparing against them.
+	Update bool
+
+	// Format is the format golden frames are written in when updating.
+	// Existing goldens are read in whichever format they're stored in.
+	Format Format
+
+	// Tolerance is the largest difference allowed in any color channel
+	// before a pixel is considered changed. Lossy formats like WebP need a
+	// small tolerance.
+	Tolerance uint8
+}
+
+// Result is the outcome of a single snapshot.
+type Result struct {
+	Case Case
+
+	// Frames is the number of frames the applet rendered.
+	Frames int
+
+	// Mismatches lists the frames that differ from their goldens.
+	Mismatches []Mismatch
+
+	// Updated is true if the golden frames were rewritten.
+	Updated bool
+
+	// Err is set if the snapshot couldn't be taken or compared, or if the
+	// number of frames differs from the goldens.
+	Err error
+}
+
+// Passed returns true if the snapshot matched its goldens, or if the
+// goldens were updated.
+func (r *Result) Passed() bool {
+	return r.Err == nil && len(r.Mismatches) == 0
+}
+
+// Run takes a snapshot for every case in the suite.
+func (r *Runner) Run(ctx context.Context, suite *Suite) []*Result {
+	results := make([]*Result, 0, len(suite.Cases))
+	for _, c := range suite.Cases {
+		results = append(results, r.RunCase(ctx, c))
+	}
+	return results
+}
+
+// RunCase takes a single snapshot, and either compares it to the goldens or
+// writes new goldens.
+func (r *Runner) RunCase(ctx context.Context, c Case) *Result {
+	result := &Result{Case: c}
+
+	roots, err := r.Applet.RunWithConfig(ctx, c.Config)
+	if err != nil {
+		result.Err = fmt.Errorf("error running script: %w", err)
+		return result
+	}
+
+	screens := encode.ScreensFromRoots(roots)
+	frames, err := screens.Frames()
+	if err != nil {
+		result.Err = fmt.Errorf("error rendering: %w", err)
+		return result
+	}
+	result.Frames = len(frames)
+
+	golden := r.golden(c)
+
+	if r.Update {
+		result.Err = golden.write(screens, frames, r.Format)
+		result.Updated = result.Err == nil
+		return result
+	}
+
+	expected, format, err := golden.read()
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	if format == FormatWebP {
+		// WebP is lossy, and its encoder merges identical consecutive
+		// frames, so compare against frames that went through the same
+		// encoding as the goldens
+		buf, err := screens.EncodeWebP(0)
+		if err != nil {
+			result.Err = fmt.Errorf("error encoding: %w", err)
+			return result
+		}
+
+		frames, err = decodeWebP(buf)
+		if err != nil {
+			result.Err = fmt.Errorf("error decoding: %w", err)
+			return result
+		}
+	}
+
+	if len(expected) != len(frames) {
+		result.Err = fmt.Errorf("expected %d frames, found %d", len(expected), len(frames))
+		return result
+	}
+
+	golden.clearDiffs()
+	for i := range frames {
+		diff := Compare(expected[i], frames[i], r.Tolerance)
+		if diff.Changed == 0 {
+			continue
+		}
+
+		diff.Frame = i
+		diff.Path, err = golden.writeDiff(i, DiffImage(expected[i], frames[i], r.Tolerance))
+		if err != nil {
+			result.Err = err
+			return result
+		}
+
+		result.Mismatches = append(result.Mismatches, diff)
+	}
+
+	return result
+}
+
+// RunTests runs the applet's test functions with runtime.Applet.RunTests,
+// and then every snapshot in the suite, each as a Go subtest.
+func (r *Runner) RunTests(t *testing.T, suite *Suite) {
+	r.Applet.RunTests(t)
+
+	for _, c := range suite.Cases {
+		t.Run(c.Name, func(t *testing.T) {
+			result := r.RunCase(context.Background(), c)
+			if result.Err != nil {
+				t.Fatal(result.Err)
+			}
+
+			for _, m := range result.Mismatches {
+				t.Errorf("%s, see %s", m, m.Path)
+			}
+		})
+	}
+}
+
+func (r *Runner) golden(c Case) golden {
+	return golden{dir: r.GoldenDir, name: c.Name}
+}
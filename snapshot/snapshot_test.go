@@ -0,0 +1,198 @@
+package snapshot
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"tidbyt.dev/pixlet/runtime"
+)
+
+var snapshotSource = `
+load("render.star", "render")
+
+def main(config):
+    c = config.get("color", "#f00")
+    return render.Root(
+        child = render.Animation(
+            children = [
+                render.Box(width = 2, height = 2, color = c),
+                render.Box(width = 3, height = 3, color = c),
+            ],
+        ),
+    )
+`
+
+func TestSuiteLoad(t *testing.T) {
+	suite, err := LoadSuite(strings.NewReader(`
+snapshots:
+  - name: red
+  - name: blue
+    config:
+      color: "#00f"
+`))
+	require.NoError(t, err)
+	assert.Equal(t, []Case{
+		{Name: "red"},
+		{Name: "blue", Config: map[string]string{"color": "#00f"}},
+	}, suite.Cases)
+
+	_, err = LoadSuite(strings.NewReader(`
+snapshots:
+  - name: red
+  - name: red
+`))
+	assert.ErrorContains(t, err, "duplicate")
+
+	_, err = LoadSuite(strings.NewReader(`
+snapshots:
+  - name: ../escape
+`))
+	assert.Error(t, err)
+
+	_, err = LoadSuite(strings.NewReader(`
+snapshots:
+  - config:
+      color: "#00f"
+`))
+	assert.ErrorContains(t, err, "no name")
+}
+
+func TestSnapshotUpdateAndCompare(t *testing.T) {
+	app, err := runtime.NewApplet("snapshot.star", []byte(snapshotSource))
+	require.NoError(t, err)
+
+	dir := filepath.Join(t.TempDir(), "snapshots")
+	suite := &Suite{Cases: []Case{
+		{Name: "red"},
+		{Name: "blue", Config: map[string]string{"color": "#00f"}},
+	}}
+
+	r := &Runner{Applet: app, GoldenDir: dir}
+
+	// no goldens yet
+	results := r.Run(context.Background(), suite)
+	require.Len(t, results, 2)
+	assert.ErrorContains(t, results[0].Err, "--update")
+
+	// create goldens
+	r.Update = true
+	for _, result := range r.Run(context.Background(), suite) {
+		assert.NoError(t, result.Err)
+		assert.True(t, result.Updated)
+		assert.Equal(t, 2, result.Frames)
+	}
+	assert.FileExists(t, filepath.Join(dir, "red", "000.png"))
+	assert.FileExists(t, filepath.Join(dir, "red", "001.png"))
+	assert.FileExists(t, filepath.Join(dir, "blue", "001.png"))
+
+	// compare against them
+	r.Update = false
+	for _, result := range r.Run(context.Background(), suite) {
+		assert.True(t, result.Passed(), result.Case.Name)
+	}
+
+	// a snapshot that no longer matches its goldens
+	suite.Cases[1].Config["color"] = "#0f0"
+	results = r.Run(context.Background(), suite)
+	assert.True(t, results[0].Passed())
+	assert.False(t, results[1].Passed())
+	require.NoError(t, results[1].Err)
+	require.Len(t, results[1].Mismatches, 2)
+
+	m := results[1].Mismatches[1]
+	assert.Equal(t, 1, m.Frame)
+	assert.Equal(t, 9, m.Changed)
+	assert.Equal(t, image.Rect(0, 0, 3, 3), m.Bounds)
+	assert.Equal(t, filepath.Join(dir+"-diff", "blue", "001.png"), m.Path)
+	assert.FileExists(t, m.Path)
+
+	// diffs are cleared once the snapshot matches again
+	suite.Cases[1].Config["color"] = "#00f"
+	results = r.Run(context.Background(), suite)
+	assert.True(t, results[1].Passed())
+	_, err = os.Stat(m.Path)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestSnapshotFrameCountChanged(t *testing.T) {
+	app, err := runtime.NewApplet("snapshot.star", []byte(snapshotSource))
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	r := &Runner{Applet: app, GoldenDir: dir, Update: true}
+	require.NoError(t, r.RunCase(context.Background(), Case{Name: "default"}).Err)
+
+	require.NoError(t, os.Remove(filepath.Join(dir, "default", "001.png")))
+
+	r.Update = false
+	result := r.RunCase(context.Background(), Case{Name: "default"})
+	assert.ErrorContains(t, result.Err, "expected 1 frames, found 2")
+}
+
+func TestCompare(t *testing.T) {
+	a := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	b := image.NewRGBA(image.Rect(0, 0, 4, 2))
+
+	assert.Equal(t, 0, Compare(a, b, 0).Changed)
+
+	b.SetRGBA(1, 1, color.RGBA{2, 0, 0, 0})
+	b.SetRGBA(3, 0, color.RGBA{0, 0, 1, 0})
+	m := Compare(a, b, 0)
+	assert.Equal(t, 2, m.Changed)
+	assert.Equal(t, image.Rect(1, 0, 4, 2), m.Bounds)
+
+	m = Compare(a, b, 1)
+	assert.Equal(t, 1, m.Changed)
+	assert.Equal(t, image.Rect(1, 1, 2, 2), m.Bounds)
+
+	assert.Equal(t, 0, Compare(a, b, 2).Changed)
+
+	c := image.NewRGBA(image.Rect(0, 0, 4, 3))
+	assert.Equal(t, 12, Compare(a, c, 0).Changed)
+}
+
+func TestDiffImage(t *testing.T) {
+	a := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	b := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	b.SetRGBA(1, 0, color.RGBA{0xff, 0xff, 0xff, 0xff})
+
+	d := DiffImage(a, b, 0)
+	assert.Equal(t, image.Rect(0, 0, 8*diffScale, diffScale), d.Bounds())
+
+	// unchanged pixel is dimmed, changed pixel is highlighted
+	assert.Equal(t, color.RGBA{0, 0, 0, 0xff}, color.RGBAModel.Convert(d.At(6*diffScale, 0)))
+	assert.Equal(t, diffChanged, d.At(7*diffScale, 0))
+
+	// actual frame is in the middle
+	assert.Equal(t, color.RGBA{0xff, 0xff, 0xff, 0xff}, d.At(4*diffScale, 0))
+}
+
+func TestRunnerRunTests(t *testing.T) {
+	src := snapshotSource + `
+load("assert.star", "assert")
+
+def test_main():
+    assert.eq(len(main({}).child.children), 2)
+`
+	app, err := runtime.NewApplet("snapshot.star", []byte(src))
+	require.NoError(t, err)
+	require.Len(t, app.TestFunctions(), 1)
+
+	suite := DefaultSuite()
+	r := &Runner{Applet: app, GoldenDir: filepath.Join(t.TempDir(), "snapshots"), Update: true}
+	for _, result := range r.Run(context.Background(), suite) {
+		require.NoError(t, result.Err)
+	}
+
+	// runs test_main along with the snapshot
+	r.Update = false
+	r.RunTests(t, suite)
+}
@@ -14,6 +14,7 @@ import Preview from './features/preview/Preview';
 import Schema from './features/schema/Schema';
 import WatcherManager from './features/watcher/WatcherManager';
 import Controls from './features/controls/Controls';
+import Inspector from './features/inspector/Inspector';
 import { Typography } from '@mui/material';
 
 
@@ -39,6 +40,7 @@ export default function Main() {
                         <Grid item xs={12} lg={size}>
                             <Preview scale={10} />
                             <Controls />
+                            <Inspector />
                         </Grid>
                         <Grid item xs={12} lg={4}>
                             <Schema />
@@ -1,9 +1,11 @@
 import React from 'react';
 import { useSelector, useDispatch } from 'react-redux';
 
-import { Button, Stack } from '@mui/material';
+import { Button, FormControlLabel, Stack, Switch, TextField } from '@mui/material';
 import { resetConfig, setConfig } from '../config/actions';
 import { set } from '../config/configSlice';
+import { showLayout, setLayoutFrame } from '../preview/previewSlice';
+import { fetchLayout } from '../preview/actions';
 
 export default function Controls() {
     const preview = useSelector(state => state.preview);
@@ -97,12 +99,39 @@ export default function Controls() {
         });
     };
 
+    function toggleLayout(show) {
+        dispatch(showLayout(show));
+        if (show) {
+            fetchLayout();
+        }
+    }
+
+    function selectLayoutFrame(frame) {
+        if (isNaN(frame) || frame < 0) {
+            return;
+        }
+        dispatch(setLayoutFrame(frame));
+        fetchLayout();
+    }
+
     return (
         <Stack sx={{ marginTop: '32px' }} spacing={2} direction="row">
             <Button variant="outlined" onClick={() => selectConfig()}>Open Config</Button>
             <Button variant="outlined" onClick={() => downloadConfig()}>Save Config</Button>
             <Button variant="outlined" onClick={() => resetSchema()}>Reset</Button>
             <Button variant="contained" onClick={() => downloadPreview()}>Export Image</Button>
+            <FormControlLabel
+                control={<Switch checked={preview.showLayout} onChange={(e) => toggleLayout(e.target.checked)} />}
+                label="Layout"
+            />
+            {preview.showLayout && <TextField
+                type="number"
+                size="small"
+                label="Frame"
+                value={preview.layoutFrame}
+                inputProps={{ min: 0, max: preview.layout ? preview.layout.frames - 1 : 0 }}
+                onChange={(e) => selectLayoutFrame(parseInt(e.target.value, 10))}
+            />}
         </Stack>
     );
 }
\ No newline at end of file
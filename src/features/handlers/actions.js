@@ -4,6 +4,7 @@ import { update, loading } from './handlerSlice';
 import { updateGenerated } from '../schema/schemaSlice';
 import { set as setError } from '../errors/errorSlice';
 import store from "../../store";
+import mountPath from '../../mount';
 
 function parseErrorMessage(handler, error) {
     let msg = `could not call handler ${handler}: ${error.message}`;
@@ -20,7 +21,7 @@ export function callHandler(id, handler, param) {
     }
 
     store.dispatch(loading(true));
-    axios.post(`${PIXLET_API_BASE}/api/v1/handlers/` + handler, JSON.stringify(data))
+    axios.post(`${PIXLET_API_BASE}${mountPath()}/api/v1/handlers/` + handler, JSON.stringify(data))
         .then(res => {
             store.dispatch(update({ id: id, value: res.data }));
         })
@@ -42,7 +43,7 @@ export function callGeneratedHandler(id, handler, param) {
     }
 
     store.dispatch(loading(true));
-    axios.post(`${PIXLET_API_BASE}/api/v1/handlers/` + handler, JSON.stringify(data))
+    axios.post(`${PIXLET_API_BASE}${mountPath()}/api/v1/handlers/` + handler, JSON.stringify(data))
         .then(res => {
             store.dispatch(updateGenerated(res.data));
         })
@@ -64,7 +65,7 @@ export function callHandlerSetValue(id, handler, param, valueHandler) {
     }
 
     store.dispatch(loading(true));
-    axios.post(`${PIXLET_API_BASE}/api/v1/handlers/` + handler, JSON.stringify(data))
+    axios.post(`${PIXLET_API_BASE}${mountPath()}/api/v1/handlers/` + handler, JSON.stringify(data))
         .then(res => {
             store.dispatch(update({ id: id, value: res.data }));
             valueHandler(res.data);
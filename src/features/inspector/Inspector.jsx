@@ -0,0 +1,88 @@
+import React, { useState } from 'react';
+import { useSelector } from 'react-redux';
+
+import Box from '@mui/material/Box';
+import Chip from '@mui/material/Chip';
+import Paper from '@mui/material/Paper';
+import Tab from '@mui/material/Tab';
+import Table from '@mui/material/Table';
+import TableBody from '@mui/material/TableBody';
+import TableCell from '@mui/material/TableCell';
+import TableContainer from '@mui/material/TableContainer';
+import TableHead from '@mui/material/TableHead';
+import TableRow from '@mui/material/TableRow';
+import Tabs from '@mui/material/Tabs';
+import Typography from '@mui/material/Typography';
+
+import styles from './styles.css';
+
+
+function CacheStatus({ status }) {
+    if (!status) {
+        return null;
+    }
+
+    return <Chip size="small" label={status} color={status === 'HIT' ? 'success' : 'default'} />;
+}
+
+function Network({ requests }) {
+    if (requests.length === 0) {
+        return <Typography className={styles.empty}>No HTTP requests during the last render.</Typography>;
+    }
+
+    return (
+        <TableContainer className={styles.content}>
+            <Table size="small" stickyHeader>
+                <TableHead>
+                    <TableRow>
+                        <TableCell>Method</TableCell>
+                        <TableCell>URL</TableCell>
+                        <TableCell>Status</TableCell>
+                        <TableCell>Cache</TableCell>
+                        <TableCell align="right">Time</TableCell>
+                    </TableRow>
+                </TableHead>
+                <TableBody>
+                    {requests.map((req, i) => (
+                        <TableRow key={i}>
+                            <TableCell>{req.method}</TableCell>
+                            <TableCell className={styles.url}>{req.url}</TableCell>
+                            <TableCell className={req.error || req.status >= 400 ? styles.failed : ''}>
+                                {req.error || req.status}
+                            </TableCell>
+                            <TableCell><CacheStatus status={req.cache_status} /></TableCell>
+                            <TableCell align="right">{req.latency_ms.toFixed(1)} ms</TableCell>
+                        </TableRow>
+                    ))}
+                </TableBody>
+            </Table>
+        </TableContainer>
+    );
+}
+
+function Console({ logs }) {
+    if (logs.length === 0) {
+        return <Typography className={styles.empty}>Nothing printed during the last render.</Typography>;
+    }
+
+    return (
+        <Box className={`${styles.content} ${styles.console}`}>
+            {logs.map((line, i) => <div key={i}>{line}</div>)}
+        </Box>
+    );
+}
+
+export default function Inspector() {
+    const inspector = useSelector(state => state.inspector);
+    const [tab, setTab] = useState('network');
+
+    return (
+        <Paper sx={{ marginTop: '32px' }} variant="outlined">
+            <Tabs value={tab} onChange={(e, value) => setTab(value)}>
+                <Tab value="network" label={`Network (${inspector.requests.length})`} />
+                <Tab value="console" label={`Console (${inspector.logs.length})`} />
+            </Tabs>
+            {tab === 'network' ? <Network requests={inspector.requests} /> : <Console logs={inspector.logs} />}
+        </Paper>
+    );
+}
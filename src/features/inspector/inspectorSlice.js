@@ -0,0 +1,36 @@
+import { createSlice } from '@reduxjs/toolkit';
+
+// Only the most recent entries are kept, so that an app that makes a lot of
+// requests doesn't slow down the page.
+const maxEntries = 500;
+
+export const inspectorSlice = createSlice({
+    name: 'inspector',
+    initialState: {
+        requests: [],
+        logs: [],
+    },
+    reducers: {
+        start: (state = initialState) => {
+            return {
+                requests: [],
+                logs: [],
+            }
+        },
+        addRequest: (state = initialState, action) => {
+            return {
+                ...state,
+                requests: [...state.requests, action.payload].slice(-maxEntries),
+            }
+        },
+        addLog: (state = initialState, action) => {
+            return {
+                ...state,
+                logs: [...state.logs, action.payload].slice(-maxEntries),
+            }
+        },
+    },
+});
+
+export const { start, addRequest, addLog } = inspectorSlice.actions;
+export default inspectorSlice.reducer;
@@ -71,8 +71,46 @@ export default function Preview() {
 
     let content = <img src={displayType + img} className={styles.image} />
     return (
-        <Paper sx={{ bgcolor: "black" }}>
+        <Paper sx={{ bgcolor: "black" }} className={styles.frame}>
             {content}
+            {preview.showLayout && preview.layout && <LayoutOverlay {...preview.layout} />}
         </Paper>
     );
+}
+
+// Outline colors, by depth in the tree. These match the ones used by
+// pixlet render --layout-debug.
+const layoutColors = ['#ff4040', '#40ff40', '#4080ff', '#ffff40', '#ff40ff', '#40ffff'];
+
+function LayoutBox({ node, depth, width, height }) {
+    const children = (node.children || []).map((child, i) =>
+        <LayoutBox key={i} node={child} depth={depth + 1} width={width} height={height} />
+    );
+
+    if (!node.painted || node.bounds.width === 0 || node.bounds.height === 0) {
+        return children;
+    }
+
+    const style = {
+        left: `${node.bounds.x / width * 100}%`,
+        top: `${node.bounds.y / height * 100}%`,
+        width: `${node.bounds.width / width * 100}%`,
+        height: `${node.bounds.height / height * 100}%`,
+        borderColor: layoutColors[depth % layoutColors.length],
+    };
+
+    return (
+        <>
+            <div className={styles.layoutBox} style={style} title={node.type} />
+            {children}
+        </>
+    );
+}
+
+function LayoutOverlay({ width, height, layout }) {
+    return (
+        <div className={styles.layout}>
+            <LayoutBox node={layout} depth={0} width={width} height={height} />
+        </div>
+    );
 }
\ No newline at end of file
@@ -1,7 +1,8 @@
 import axios from 'axios';
-import { update, loading } from './previewSlice';
+import { update, loading, setLayout } from './previewSlice';
 import { set as setError, clear as clearErrors } from '../errors/errorSlice';
 import store from '../../store';
+import mountPath from '../../mount';
 import axiosRetry from 'axios-retry';
 
 let timeout = null;
@@ -16,7 +17,7 @@ export default function fetchPreview(formData) {
         },
     });
 
-    client.post(`${PIXLET_API_BASE}/api/v1/preview`, formData)
+    client.post(`${PIXLET_API_BASE}${mountPath()}/api/v1/preview`, formData)
         .then(res => {
             document.title = res.data.title;
             store.dispatch(update(res.data));
@@ -24,6 +25,9 @@ export default function fetchPreview(formData) {
                 store.dispatch(setError({ id: res.data.error, message: res.data.error }));
             } else {
                 store.dispatch(clearErrors());
+                if (store.getState().preview.showLayout) {
+                    fetchLayout();
+                }
             }
         })
         .catch(err => {
@@ -36,4 +40,17 @@ export default function fetchPreview(formData) {
         .then(() => {
             store.dispatch(loading(false));
         })
-}
\ No newline at end of file
+}
+
+// The layout is only worked out on request, since it means painting
+// the selected frame again.
+export function fetchLayout() {
+    const frame = store.getState().preview.layoutFrame;
+    axios.get(`${PIXLET_API_BASE}${mountPath()}/api/v1/layout`, { params: { frame } })
+        .then(res => {
+            store.dispatch(setLayout(res.data));
+        })
+        .catch(() => {
+            store.dispatch(setLayout(null));
+        });
+}
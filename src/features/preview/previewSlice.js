@@ -4,6 +4,9 @@ export const previewSlice = createSlice({
     name: 'preview',
     initialState: {
         loading: false,
+        layout: null,
+        showLayout: false,
+        layoutFrame: 0,
         value: {
             img: '',
             img_type: '',
@@ -31,8 +34,17 @@ export const previewSlice = createSlice({
         loading: (state = initialState, action) => {
             return { ...state, loading: action.payload }
         },
+        setLayout: (state = initialState, action) => {
+            return { ...state, layout: action.payload }
+        },
+        showLayout: (state = initialState, action) => {
+            return { ...state, showLayout: action.payload }
+        },
+        setLayoutFrame: (state = initialState, action) => {
+            return { ...state, layoutFrame: action.payload }
+        },
     },
 });
 
-export const { update, loading } = previewSlice.actions;
+export const { update, loading, setLayout, showLayout, setLayoutFrame } = previewSlice.actions;
 export default previewSlice.reducer;
\ No newline at end of file
@@ -3,6 +3,7 @@ import axiosRetry from 'axios-retry';
 
 import { update, loading, error } from './schemaSlice';
 import store from "../../store";
+import mountPath from '../../mount';
 
 
 export default function refreshSchema() {
@@ -17,7 +18,7 @@ export default function refreshSchema() {
         },
     });
 
-    client.get(`${PIXLET_API_BASE}/api/v1/schema`)
+    client.get(`${PIXLET_API_BASE}${mountPath()}/api/v1/schema`)
         .then(res => {
             store.dispatch(update(res.data));
         })
@@ -7,13 +7,14 @@ import Button from '@mui/material/Button';
 import { callHandlerSetValue } from '../../../handlers/actions';
 import { set as setError } from '../../../errors/errorSlice';
 import { set, remove } from '../../../config/configSlice';
+import mountPath from '../../../../mount';
 
 
 export default function OAuth2({ field }) {
     const [loggedIn, setLoggedIn] = useState("");
     const dispatch = useDispatch();
     const config = useSelector(state => state.config);
-    const redirectUri = document.location.protocol + "//" + document.location.host + "/oauth-callback"
+    const redirectUri = document.location.protocol + "//" + document.location.host + mountPath() + "/oauth-callback"
 
     useEffect(() => {
         if (field.id in config) {
@@ -1,7 +1,10 @@
 import store from '../../store';
+import mountPath from '../../mount';
 import { update } from '../preview/previewSlice';
+import { fetchLayout } from '../preview/actions';
 import { update as updateSchema } from '../schema/schemaSlice';
 import { set as setError, clear as clearErrors } from '../errors/errorSlice';
+import { start as startRender, addRequest, addLog } from '../inspector/inspectorSlice';
 
 export default class Watcher {
     constructor() {
@@ -10,7 +13,7 @@ export default class Watcher {
 
     connect() {
         const proto = document.location.protocol === "https:" ? "wss:" : "ws:";
-        this.conn = new WebSocket(proto + '//' + document.location.host + '/api/v1/ws');
+        this.conn = new WebSocket(proto + '//' + document.location.host + mountPath() + '/api/v1/ws');
         this.conn.open = this.open.bind(this);
         this.conn.onmessage = this.process.bind(this);
         this.conn.onclose = this.close.bind(this);
@@ -33,6 +36,9 @@ export default class Watcher {
                     img_type: data.img_type
                 }));
                 store.dispatch(clearErrors());
+                if (store.getState().preview.showLayout) {
+                    fetchLayout();
+                }
                 break;
             case 'schema':
                 store.dispatch(updateSchema(JSON.parse(data.message)));
@@ -40,6 +46,15 @@ export default class Watcher {
             case 'error':
                 store.dispatch(setError({ id: data.message, message: data.message }));
                 break;
+            case 'render':
+                store.dispatch(startRender());
+                break;
+            case 'http':
+                store.dispatch(addRequest(JSON.parse(data.message)));
+                break;
+            case 'print':
+                store.dispatch(addLog(data.message));
+                break;
             default:
                 console.log(`[watcher] unknown type ${data.type}`);
         }
@@ -6,13 +6,14 @@ import { BrowserRouter, Route, Routes } from "react-router-dom";
 import Main from './Main';
 import OAuth2Handler from './features/schema/fields/oauth2/OAuth2Handler';
 import store from './store';
+import mountPath from './mount';
 import DevToolsTheme from './features/theme/DevToolsTheme';
 
 const App = () => {
     return (
         <Provider store={store}>
             <DevToolsTheme>
-                <BrowserRouter>
+                <BrowserRouter basename={mountPath()}>
                     <Routes>
                         <Route exact path="/" element={<Main />} />
                         <Route path="oauth-callback" element={<OAuth2Handler />} />
@@ -0,0 +1,7 @@
+// When pixlet serves several apps at once, each one is mounted under
+// /apps/<id>. mountPath returns that prefix for the app this page belongs to,
+// or an empty string when a single app is served at the root.
+export default function mountPath() {
+    const match = document.location.pathname.match(/^\/apps\/[^/]+/);
+    return match ? match[0] : '';
+}
@@ -3,6 +3,7 @@ import { configureStore } from '@reduxjs/toolkit'
 import configSlice from './features/config/configSlice';
 import errorSlice from './features/errors/errorSlice';
 import handlerSlice from './features/handlers/handlerSlice';
+import inspectorSlice from './features/inspector/inspectorSlice';
 import paramSlice from './features/config/paramSlice';
 import previewSlice from './features/preview/previewSlice';
 import schemaSlice from './features/schema/schemaSlice';
@@ -12,6 +13,7 @@ export default configureStore({
         config: configSlice,
         errors: errorSlice,
         handlers: handlerSlice,
+        inspector: inspectorSlice,
         param: paramSlice,
         preview: previewSlice,
         schema: schemaSlice,